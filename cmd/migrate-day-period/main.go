@@ -0,0 +1,53 @@
+// Command migrate-day-period converts the days and periods stored as free text before model.Day and
+// model.Period existed. It is run once against a project after upgrading:
+//
+//	PROJECT_ID=my-project go run ./cmd/migrate-day-period -dry-run
+//
+// Entities whose day or period cannot be converted are listed so they can be corrected by hand.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+)
+
+func main() {
+	projectID := flag.String("project", os.Getenv("PROJECT_ID"), "Google Cloud project of the datastore, defaults to $PROJECT_ID")
+	dryRun := flag.Bool("dry-run", false, "report what would be converted without writing anything")
+	flag.Parse()
+
+	if *projectID == "" {
+		log.Fatal("a project is required, set -project or PROJECT_ID")
+	}
+
+	appDb, err := db.NewAppDatastore(*projectID)
+	if err != nil {
+		log.Fatalf("failed to init appDb connection: %v", err)
+	}
+	defer appDb.Close()
+
+	res, err := appDb.MigrateDayPeriod(*dryRun)
+	if res != nil {
+		kinds := make([]string, 0, len(res.Rewritten))
+		for kind := range res.Rewritten {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			log.Printf("%v: %d entities converted", kind, res.Rewritten[kind])
+		}
+		for _, key := range res.Invalid {
+			log.Printf("%v %v: day or period could not be converted", key.Kind, key.Name)
+		}
+	}
+	if err != nil {
+		log.Fatalf("migration failed: %v", err)
+	}
+	if *dryRun {
+		log.Printf("dry run, nothing was written")
+	}
+}
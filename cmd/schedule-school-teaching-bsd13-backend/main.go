@@ -7,18 +7,18 @@ import (
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/api"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/config"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
-	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/messaging"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db/memory"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db/postgres"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/handlers"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/router"
-	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/security"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
-	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/thirdparty"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"time"
+	_ "time/tzdata"
 )
 
 // @title Merchant Config Service API
@@ -40,42 +40,40 @@ func main() {
 
 	l := util.NewLogger(cfg.Debug)
 
-	// Setup Cloud Storage Client
-	cs, err := util.NewCloudStorage(cfg.KymBucketName)
-	if err != nil {
-		log.Fatalf("failed to connect cloud storage: %v", err)
-	}
-
-	appDb, err := db.NewAppDatastore(cfg.ProjectID)
+	appDb, err := openDB(cfg)
 	if err != nil {
 		log.Fatalf("failed to init appDb connection: %v", err)
 	}
 	defer appDb.Close()
 
-	publisher, err := messaging.NewPubsubPublisher(cfg.ProjectID, cfg.MerchantCreatePublisher.TopicID, l)
+	hu := util.NewHandlerUtil(l)
+
+	location, err := time.LoadLocation(cfg.TimeZone)
 	if err != nil {
-		log.Fatalf("failed to init publisher connection: %v", err)
+		log.Fatalf("failed to load time zone %v: %v", cfg.TimeZone, err)
 	}
 
-	hu := util.NewHandlerUtil(l)
-	roleAuth := security.NewRoleAuth(appDb)
-
-	apiClient := thirdparty.NewApiClient(l, cfg.OrganisationServiceURL, cfg.RecipientServiceURL)
-	merchantService := service.NewMerchantService(appDb, publisher)
-	newKymService := service.NewKymService(appDb, cs, apiClient, merchantService)
-	teacherService := service.NewTeacherService(appDb)
+	teacherService := service.NewTeacherService(appDb, appDb, appDb, appDb, appDb)
 	mainSubjectService := service.NewMainSubjectService(appDb)
-	subjectService := service.NewSubjectService(appDb)
-	confirmationService := service.NewConfirmationService(appDb)
+	subjectService := service.NewSubjectService(appDb, appDb, appDb)
+	confirmationService := service.NewConfirmationService(appDb, appDb, appDb, appDb, appDb)
+	scheduleService := service.NewScheduleService(appDb, appDb, appDb, appDb, appDb, appDb, appDb, appDb, appDb, appDb, location)
+	bellScheduleService := service.NewBellScheduleService(appDb)
+	calendarService := service.NewCalendarService(appDb, appDb)
+	roomService := service.NewRoomService(appDb)
+	studentService := service.NewStudentService(appDb, appDb, appDb, appDb, appDb, appDb, appDb)
 
-	mh := handlers.NewMerchantsHandler(hu, roleAuth, publisher, merchantService)
-	kym := handlers.NewKymHandler(hu, roleAuth, newKymService)
 	th := handlers.NewTeacherHandler(hu,teacherService)
 	msh := handlers.NewMainSubjectHandler(hu,mainSubjectService)
 	sh := handlers.NewSubjectHandler(hu,subjectService)
 	ch := handlers.NewConfirmationHandler(hu,confirmationService)
+	sch := handlers.NewScheduleHandler(hu, scheduleService)
+	bsh := handlers.NewBellScheduleHandler(hu, bellScheduleService)
+	calh := handlers.NewCalendarHandler(hu, calendarService)
+	rh := handlers.NewRoomHandler(hu, roomService)
+	sth := handlers.NewStudentHandler(hu, studentService)
 
-	r := router.NewRouter(l, mh, kym,th,msh, sh,ch)
+	r := router.NewRouter(l, th, msh, sh, ch, sch, bsh, calh, rh, sth)
 	cor := cors.New(cors.Options{
 		AllowedOrigins: []string{"http://localhost:3000"},
 		AllowCredentials: true,
@@ -114,3 +112,14 @@ func main() {
 	l.Info().Msg("Graceful shutdown")
 	os.Exit(0)
 }
+
+// openDB connects to the storage backend selected by DB_DRIVER
+func openDB(cfg *config.Config) (db.Store, error) {
+	switch cfg.DBDriver {
+	case config.DBDriverMemory:
+		return memory.NewDB(), nil
+	case config.DBDriverPostgres:
+		return postgres.NewDB(cfg.DatabaseURL)
+	}
+	return db.NewAppDatastore(cfg.ProjectID)
+}
@@ -7,13 +7,33 @@ import (
 	"github.com/Netflix/go-env"
 )
 
+// the storage backends DBDriver selects from
+const (
+	// DBDriverDatastore keeps the data in Google Cloud Datastore
+	DBDriverDatastore = "datastore"
+	// DBDriverMemory keeps the data in memory, it is lost when the server stops. It lets the server run
+	// locally without Google Cloud.
+	DBDriverMemory = "memory"
+	// DBDriverPostgres keeps the data in the PostgreSQL database at DatabaseURL
+	DBDriverPostgres = "postgres"
+)
+
 // Config loads and stores all the environment variables for the program
 type Config struct {
-	ProjectID              string `env:"PROJECT_ID,required=true"`
-	KymBucketName          string `env:"KYM_BUCKET_NAME,required=true"`
+	// DBDriver selects the storage backend, see the DBDriver constants
+	DBDriver string `env:"DB_DRIVER,default=datastore"`
+	// ProjectID is the Google Cloud project of the datastore, it is only required by DBDriverDatastore
+	ProjectID string `env:"PROJECT_ID"`
+	// DatabaseURL is the connection string of the database, it is only required by DBDriverPostgres
+	DatabaseURL string `env:"DATABASE_URL"`
+	// KymBucketName, OrganisationServiceURL, RecipientServiceURL and MerchantCreatePublisher are only
+	// used by the merchant services, the scheduling runs without them
+	KymBucketName          string `env:"KYM_BUCKET_NAME"`
 	Debug                  bool   `env:"DEBUG,default=false"`
-	OrganisationServiceURL string `env:"ORGANISATION_SERVICE_URL,required=true"`
-	RecipientServiceURL    string `env:"RECIPIENT_SERVICE_URL,required=true"`
+	OrganisationServiceURL string `env:"ORGANISATION_SERVICE_URL"`
+	RecipientServiceURL    string `env:"RECIPIENT_SERVICE_URL"`
+	// TimeZone is the IANA name of the school's time zone, the times of the bell schedule are in it
+	TimeZone string `env:"TIME_ZONE,default=Asia/Bangkok"`
 
 	Server struct {
 		Port         string        `env:"PORT,default=8080"`
@@ -23,7 +43,7 @@ type Config struct {
 	}
 
 	MerchantCreatePublisher struct {
-		TopicID string `env:"PUBLISHER_TOPIC_ID,required=true"`
+		TopicID string `env:"PUBLISHER_TOPIC_ID"`
 	}
 }
 
@@ -36,5 +56,19 @@ func AppConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse environment variables: %w", err)
 	}
 
+	switch config.DBDriver {
+	case DBDriverDatastore:
+		if len(config.ProjectID) == 0 {
+			return nil, fmt.Errorf("PROJECT_ID is required with DB_DRIVER %v", config.DBDriver)
+		}
+	case DBDriverPostgres:
+		if len(config.DatabaseURL) == 0 {
+			return nil, fmt.Errorf("DATABASE_URL is required with DB_DRIVER %v", config.DBDriver)
+		}
+	case DBDriverMemory:
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q, use %v, %v or %v", config.DBDriver, DBDriverDatastore, DBDriverPostgres, DBDriverMemory)
+	}
+
 	return config, nil
 }
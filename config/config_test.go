@@ -33,6 +33,50 @@ func TestAppConfig(t *testing.T) {
 	cmp(t, "KYM_BUCKET_NAME", cfg.KymBucketName, "bucket-dev")
 	cmp(t, "ORGANISATION_SERVICE_URL", cfg.OrganisationServiceURL, "organisation-dev-url")
 	cmp(t, "RECIPIENT_SERVICE_URL", cfg.RecipientServiceURL, "recipient-dev-url")
+	cmp(t, "TIME_ZONE", cfg.TimeZone, "Asia/Bangkok")
+	cmp(t, "DB_DRIVER", cfg.DBDriver, DBDriverDatastore)
+}
+
+func TestAppConfigDBDriver(t *testing.T) {
+	_ = os.Setenv("SERVER_TIMEOUT_READ", "10s")
+	_ = os.Setenv("SERVER_TIMEOUT_WRITE", "10s")
+	_ = os.Setenv("SERVER_TIMEOUT_IDLE", "10s")
+	_ = os.Unsetenv("PUBLISHER_TOPIC_ID")
+	_ = os.Unsetenv("KYM_BUCKET_NAME")
+	_ = os.Unsetenv("ORGANISATION_SERVICE_URL")
+	_ = os.Unsetenv("RECIPIENT_SERVICE_URL")
+	_ = os.Unsetenv("PROJECT_ID")
+	_ = os.Unsetenv("DATABASE_URL")
+	defer os.Unsetenv("DB_DRIVER")
+	defer os.Unsetenv("DATABASE_URL")
+
+	_ = os.Setenv("DB_DRIVER", "memory")
+	cfg, err := AppConfig()
+	if err != nil {
+		t.Fatalf("failed to read config without a project or the merchant settings: %v", err)
+	}
+	cmp(t, "DB_DRIVER", cfg.DBDriver, DBDriverMemory)
+
+	_ = os.Setenv("DB_DRIVER", "datastore")
+	if _, err := AppConfig(); err == nil {
+		t.Errorf("expected the datastore driver to require PROJECT_ID")
+	}
+
+	_ = os.Setenv("DB_DRIVER", "postgres")
+	if _, err := AppConfig(); err == nil {
+		t.Errorf("expected the postgres driver to require DATABASE_URL")
+	}
+	_ = os.Setenv("DATABASE_URL", "postgres://localhost/schedule")
+	cfg, err = AppConfig()
+	if err != nil {
+		t.Fatalf("failed to read config with a database url: %v", err)
+	}
+	cmp(t, "DATABASE_URL", cfg.DatabaseURL, "postgres://localhost/schedule")
+
+	_ = os.Setenv("DB_DRIVER", "mysql")
+	if _, err := AppConfig(); err == nil {
+		t.Errorf("expected an unknown DB_DRIVER to fail")
+	}
 }
 
 func cmp(t *testing.T, field, got, want interface{}) {
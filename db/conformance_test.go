@@ -0,0 +1,18 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db/dbtest"
+)
+
+func TestConformance(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) db.Store {
+		ds, err := db.EmptyTestDatastore(t)
+		if err != nil {
+			t.Fatalf("failed to empty the datastore: %v", err)
+		}
+		return ds
+	})
+}
@@ -1,15 +1,96 @@
 package db
 
 import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
 )
 
+// Page selects a single page of a list. Token is the nextPageToken returned with the previous
+// page, an empty Token starts from the first entity. List methods return the token of the next
+// page, which is empty once the last page has been read
+type Page struct {
+	Size  int
+	Token string
+}
+
+// OffsetToken is the page token of backends without query cursors, it holds the position of the
+// next page in the ordered list
+func OffsetToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// ParseOffsetToken reads the position held by a token made by OffsetToken, an empty token starts
+// from the first entity. Other tokens fail with c.ErrValidation.
+func ParseOffsetToken(token string) (int, error) {
+	if len(token) == 0 {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, &c.ErrValidation{Violations: fmt.Errorf("invalid pageToken %q", token)}
+	}
+	offset, err := strconv.Atoi(string(b))
+	if err != nil || offset < 0 {
+		return 0, &c.ErrValidation{Violations: fmt.Errorf("invalid pageToken %q", token)}
+	}
+	return offset, nil
+}
+
+// TeacherSort is the property teachers are listed by
+type TeacherSort string
+
+const (
+	TeacherSortFirstName TeacherSort = "FirstName"
+	TeacherSortNickName  TeacherSort = "NickName"
+	TeacherSortLastName  TeacherSort = "LastName"
+)
+
+// TeacherQuery filters and orders the teachers returned by GetAllTeacher, empty values are not applied
+type TeacherQuery struct {
+	MainSubjectID string
+	// Q matches teachers whose first, nick or last name starts with it, ignoring case
+	Q string
+	// Sort orders the teachers ascending by the property, by id when empty
+	Sort TeacherSort
+}
+
+// SubjectQuery filters the subjects returned by GetAllSubject, zero values are not applied
+type SubjectQuery struct {
+	MainSubjectID  string
+	MinStudentsGte int
+}
+
 type AppDB interface {
 	MerchantDB
 
 	RoleDB
 }
 
+// Store is implemented by each storage backend, it persists every kind of the application
+type Store interface {
+	AppDB
+	KymDB
+	TeacherDB
+	TeacherResponsibilityDB
+	MainSubjectDB
+	SubjectDB
+	ConfirmationDB
+	BellScheduleDB
+	TermDB
+	HolidayDB
+	StudentDB
+	RoomDB
+	ScheduleDB
+
+	// Close releases the connection to the backend
+	Close() error
+}
+
 // MerchantDB defines an interface for our Application's data access methods
 type MerchantDB interface {
 	// GetMerchant gets the Merchant from the given id
@@ -29,12 +110,15 @@ type MerchantDB interface {
 type RoleDB interface {
 	// GetRole retrieves an organisation role by ID
 	GetRole(organisationID, userID string) (*model.Role, error)
+
+	// AddRole stores a role of a user in an organisation, roles are managed outside of this service
+	AddRole(role *model.Role) error
 }
 
 // KymDB defines an interface for our Application's data access methods
 type KymDB interface {
 	// GetAllKym gets all kym detail from db
-	GetAllKym(status string) ([]*model.Kym, error)
+	GetAllKym(status string, page Page) ([]*model.Kym, string, error)
 
 	// GetKym gets all kym detail from db
 	GetKym(id string) (*model.Kym, error)
@@ -50,36 +134,201 @@ type KymDB interface {
 
 // TeacherDB defines an interface for our Application's data access methods
 type TeacherDB interface {
-	// GetAllTeacher gets all kym detail from db
-	GetAllTeacher() ([]*model.Teacher, error)
+	// GetAllTeacher gets a page of the teachers matching the query
+	GetAllTeacher(query TeacherQuery, page Page) ([]*model.Teacher, string, error)
 
 
 	// AddTeacher creates the klm detail to db
 	AddTeacher(kym *model.Teacher) error
 
+	// GetTeacher gets the Teacher from the given id
+	GetTeacher(id string) (*model.Teacher, error)
+
+	// UpdateTeacherAvailability replaces the weekly windows and exceptions of an existing Teacher
+	UpdateTeacherAvailability(id string, availability []model.AvailabilityWindow, exceptions []model.AvailabilityException) error
+
+	// UpdateTeacher replaces an existing Teacher
+	UpdateTeacher(t *model.Teacher) error
+
+	// DeleteTeacher removes an existing Teacher together with its responsibilities
+	DeleteTeacher(id string) error
+
+}
+
+// TeacherResponsibilityDB defines an interface for the teacher-to-subject qualification matrix
+type TeacherResponsibilityDB interface {
+	// AddTeacherResponsibility qualifies an existing teacher for an existing subject
+	AddTeacherResponsibility(tr *model.TeacherResponsibility) error
+
+	// GetAllTeacherResponsibility gets the qualifications, optionally only those of the given teacher and/or subject
+	GetAllTeacherResponsibility(teacherID string, subjectID string, page Page) ([]*model.TeacherResponsibility, string, error)
+
+	// ReplaceTeacherResponsibility replaces all qualifications of a teacher with the given subjects, a
+	// subject listed more than once is stored once
+	ReplaceTeacherResponsibility(teacherID string, subjectIDs []string) error
+
+	// DeleteTeacherResponsibility removes a single qualification of a teacher
+	DeleteTeacherResponsibility(teacherID string, subjectID string) error
+
+	// GetAllTeacherBySubject gets the teachers qualified for the subject through their main subject or a responsibility,
+	// returns c.ErrDBNoSuchEntity when the subject does not exist
+	GetAllTeacherBySubject(subjectID string, page Page) ([]*model.Teacher, string, error)
+
+	// GetAllSubjectByTeacher gets the subjects the teacher is qualified for through their main subject or a responsibility,
+	// returns c.ErrDBNoSuchEntity when the teacher does not exist
+	GetAllSubjectByTeacher(teacherID string, page Page) ([]*model.Subject, string, error)
 }
 
 // MainSubjectDB defines an interface for our Application's data access methods
 type MainSubjectDB interface {
 	// GetAllMainSubject gets all kym detail from db
-	GetAllMainSubject() ([]*model.MainSubject, error)
+	GetAllMainSubject(page Page) ([]*model.MainSubject, string, error)
 
 
 	// AddMainSubject creates the klm detail to db
 	AddMainSubject(kym *model.MainSubject) error
 
+	// GetMainSubject gets the MainSubject from the given id
+	GetMainSubject(id string) (*model.MainSubject, error)
+
+	// UpdateMainSubject replaces an existing MainSubject
+	UpdateMainSubject(m *model.MainSubject) error
+
+	// DeleteMainSubject removes an existing MainSubject. Subjects and teachers referring to it
+	// block the delete, unless cascade is set in which case they are removed as well
+	DeleteMainSubject(id string, cascade bool) error
+
 }
 
 type SubjectDB interface {
-	GetAllSubject() ([]*model.Subject, error)
+	GetAllSubject(query SubjectQuery, page Page) ([]*model.Subject, string, error)
 	AddSubject(kym *model.Subject) error
+	GetSubject(id string) (*model.Subject, error)
+	UpdateSubject(m *model.Subject) error
+	DeleteSubject(id string, cascade bool) error
 
 }
 
 
 type ConfirmationDB interface {
 	AddConfirmation(m *model.Confirmation) error
+	GetConfirmation(id string) (*model.Confirmation, error)
 	AddConfirmationDetail(m *model.ConfirmationDetail) error
-	GetAllConfirmation() ([]*model.Confirmation, error)
-	GetAllConfirmationDetail(confirmationId string) ([]*model.ConfirmationDetail, error)
+	// AddConfirmationDetails writes the details in batches, the details written by the batches before
+	// a failing one are kept
+	AddConfirmationDetails(list []*model.ConfirmationDetail) error
+	GetAllConfirmation(page Page) ([]*model.Confirmation, string, error)
+	GetAllConfirmationDetail(confirmationId string, page Page) ([]*model.ConfirmationDetail, string, error)
+	// UpdateConfirmationStatus moves the confirmation to the status and records who changed it and when.
+	// Transitions the lifecycle does not allow fail with c.ErrConflict.
+	UpdateConfirmationStatus(id string, status model.ConfirmationStatus, changedBy string, changedAt time.Time) (*model.Confirmation, error)
+}
+
+// BellScheduleDB defines an interface for the periods of the school day
+type BellScheduleDB interface {
+	// AddBellSchedule creates the BellSchedule, a default bell schedule replaces the previous default
+	AddBellSchedule(b *model.BellSchedule) error
+
+	// GetAllBellSchedule gets a page of the bell schedules
+	GetAllBellSchedule(page Page) ([]*model.BellSchedule, string, error)
+
+	// GetBellSchedule gets the BellSchedule from the given id
+	GetBellSchedule(id string) (*model.BellSchedule, error)
+
+	// GetDefaultBellSchedule gets the BellSchedule marked as default
+	GetDefaultBellSchedule() (*model.BellSchedule, error)
+
+	// UpdateBellSchedule replaces an existing BellSchedule, a default bell schedule replaces the previous default
+	UpdateBellSchedule(b *model.BellSchedule) error
+
+	// DeleteBellSchedule removes an existing BellSchedule, confirmations referring to it block the delete
+	DeleteBellSchedule(id string) error
+}
+
+// TermDB defines an interface for the academic terms
+type TermDB interface {
+	// AddTerm creates the Term
+	AddTerm(t *model.Term) error
+
+	// GetAllTerm gets a page of the terms ordered by start date
+	GetAllTerm(page Page) ([]*model.Term, string, error)
+
+	// GetTerm gets the Term from the given id
+	GetTerm(id string) (*model.Term, error)
+
+	// UpdateTerm replaces an existing Term
+	UpdateTerm(t *model.Term) error
+
+	// DeleteTerm removes an existing Term, confirmations referring to it block the delete
+	DeleteTerm(id string) error
+}
+
+// HolidayDB defines an interface for the holiday and closure calendar
+type HolidayDB interface {
+	// AddHoliday creates the Holiday
+	AddHoliday(h *model.Holiday) error
+
+	// GetAllHoliday gets a page of the holidays ordered by start date
+	GetAllHoliday(page Page) ([]*model.Holiday, string, error)
+
+	// GetHoliday gets the Holiday from the given id
+	GetHoliday(id string) (*model.Holiday, error)
+
+	// UpdateHoliday replaces an existing Holiday
+	UpdateHoliday(h *model.Holiday) error
+
+	// DeleteHoliday removes an existing Holiday
+	DeleteHoliday(id string) error
+}
+
+// StudentDB defines an interface for the students taking lessons
+type StudentDB interface {
+	// AddStudent creates the Student
+	AddStudent(s *model.Student) error
+
+	// GetAllStudent gets a page of the students ordered by name
+	GetAllStudent(page Page) ([]*model.Student, string, error)
+
+	// GetStudent gets the Student from the given id
+	GetStudent(id string) (*model.Student, error)
+
+	// UpdateStudent replaces an existing Student
+	UpdateStudent(s *model.Student) error
+
+	// DeleteStudent removes an existing Student, confirmation details referring to it block the delete
+	DeleteStudent(id string) error
+}
+
+// RoomDB defines an interface for the rooms classes are taught in
+type RoomDB interface {
+	// AddRoom creates the Room
+	AddRoom(r *model.Room) error
+
+	// GetAllRoom gets a page of the rooms
+	GetAllRoom(page Page) ([]*model.Room, string, error)
+
+	// GetRoom gets the Room from the given id
+	GetRoom(id string) (*model.Room, error)
+
+	// UpdateRoom replaces an existing Room
+	UpdateRoom(r *model.Room) error
+
+	// DeleteRoom removes an existing Room, schedules generated earlier keep referring to it
+	DeleteRoom(id string) error
+}
+
+// ScheduleDB defines an interface for persisting generated timetables
+type ScheduleDB interface {
+	// AddSchedule creates the Schedule together with all of its slots
+	AddSchedule(s *model.Schedule, slots []*model.ScheduleSlot) error
+
+	// GetSchedule gets the Schedule from the given id
+	GetSchedule(id string) (*model.Schedule, error)
+
+	// GetAllScheduleSlot gets the slots of a Schedule, optionally only those of the given
+	// teacher and/or attended by the Student with the given id
+	GetAllScheduleSlot(scheduleID string, teacherID string, studentID string) ([]*model.ScheduleSlot, error)
+
+	// GetAllStudentScheduleSlot gets the slots of a Schedule attended by the Student with the given id
+	GetAllStudentScheduleSlot(scheduleID string, studentID string) ([]*model.ScheduleSlot, error)
 }
@@ -2,11 +2,16 @@ package db
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/datastore"
+	"google.golang.org/api/iterator"
 
 	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
@@ -24,6 +29,14 @@ type AppDatastore struct {
 	KindSubject string
 	KindConfirmation string
 	KindConfirmationDetail string``
+	KindTeacherResponsibility string
+	KindSchedule string
+	KindScheduleSlot string
+	KindBellSchedule string
+	KindTerm string
+	KindHoliday string
+	KindRoom string
+	KindStudent string
 }
 
 // NewAppDatastore create a datastore client to persist application data on Google Cloud Datastore
@@ -46,7 +59,7 @@ func NewAppDatastore(projectID string) (*AppDatastore, error) {
 		return nil, fmt.Errorf("unable to communicate to datastore: %w", err)
 	}
 
-	return &AppDatastore{client, "Merchant", "Role", "Kym","Teacher", "MainSubject", "Subject","Confirmation","ConfirmationDetail"}, nil
+	return &AppDatastore{client, "Merchant", "Role", "Kym","Teacher", "MainSubject", "Subject","Confirmation","ConfirmationDetail", "TeacherResponsibility", "Schedule", "ScheduleSlot", "BellSchedule", "Term", "Holiday", "Room", "Student"}, nil
 }
 
 // GetMerchant returns the Merchant given the ID
@@ -94,8 +107,8 @@ func (db *AppDatastore) UpdateMerchant(m *model.Merchant) error {
 		switch err {
 		case nil:
 			// entity exists, go ahead with update
-			if err := validateMerchantUpdate(old, m); err != nil {
-				return &c.ErrValidation{Violations: err}
+			if err := old.CheckUpdate(m); err != nil {
+				return err
 			}
 
 			// preserve other fields and update the last updated timestamp
@@ -114,28 +127,6 @@ func (db *AppDatastore) UpdateMerchant(m *model.Merchant) error {
 	return err
 }
 
-// validateMerchantUpdate is a simple helper which checks where `to` can be updated from `from` or not
-func validateMerchantUpdate(from *model.Merchant, to *model.Merchant) error {
-
-	if from.OrganisationID != to.OrganisationID {
-		return errors.New("changing organisationID is unallowed")
-	}
-
-	if from.MerchantID != to.MerchantID {
-		return errors.New("changing organisationID is unallowed")
-	}
-
-	if from.FullName != to.FullName {
-		return errors.New("changing fullName is unallowed")
-	}
-
-	if from.CurrencyCode != to.CurrencyCode {
-		return errors.New("changing CurrencyCode is unallowed")
-	}
-
-	return nil
-}
-
 // UpsertPayOutConfig Merchant's PayOutConfig to be updated or inserted
 func (db *AppDatastore) UpsertPayOutConfig(merchantID string, poc *model.PayOutConfig) error {
 	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
@@ -173,6 +164,23 @@ func (db *AppDatastore) GetRole(organisationID, userID string) (*model.Role, err
 	}
 }
 
+// AddRole attempts to add a role, a user has a single role entity in each organisation
+func (db *AppDatastore) AddRole(role *model.Role) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.roleDatastoreKey(role.OrganisationID, role.UserID)
+		switch err := tx.Get(key, &model.Role{}); err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			_, err = tx.Put(key, role)
+			return err
+		default:
+			return err
+		}
+	})
+	return err
+}
+
 // Close is needed to close the client connection
 func (db *AppDatastore) Close() error {
 	return db.client.Close()
@@ -196,6 +204,11 @@ func (db *AppDatastore) teacherKey(id string) *datastore.Key {
 	return datastore.NameKey(db.KindTeacher, id, nil)
 }
 
+// teacherResponsibilityKey is a child of the teacher so a teacher's qualifications can be queried in a transaction
+func (db *AppDatastore) teacherResponsibilityKey(teacherID, subjectID string) *datastore.Key {
+	return datastore.NameKey(db.KindTeacherResponsibility, subjectID, db.teacherKey(teacherID))
+}
+
 func (db *AppDatastore) mainSubjectKey(id string) *datastore.Key {
 	return datastore.NameKey(db.KindMainSubject, id, nil)
 }
@@ -212,6 +225,34 @@ func (db *AppDatastore) confirmationDetailKey(id string) *datastore.Key {
 	return datastore.NameKey(db.KindConfirmationDetail, id, nil)
 }
 
+func (db *AppDatastore) scheduleKey(id string) *datastore.Key {
+	return datastore.NameKey(db.KindSchedule, id, nil)
+}
+
+func (db *AppDatastore) bellScheduleKey(id string) *datastore.Key {
+	return datastore.NameKey(db.KindBellSchedule, id, nil)
+}
+
+func (db *AppDatastore) termKey(id string) *datastore.Key {
+	return datastore.NameKey(db.KindTerm, id, nil)
+}
+
+func (db *AppDatastore) holidayKey(id string) *datastore.Key {
+	return datastore.NameKey(db.KindHoliday, id, nil)
+}
+
+func (db *AppDatastore) roomKey(id string) *datastore.Key {
+	return datastore.NameKey(db.KindRoom, id, nil)
+}
+
+func (db *AppDatastore) studentKey(id string) *datastore.Key {
+	return datastore.NameKey(db.KindStudent, id, nil)
+}
+
+func (db *AppDatastore) scheduleSlotKey(id string) *datastore.Key {
+	return datastore.NameKey(db.KindScheduleSlot, id, nil)
+}
+
 // AddKym attempts to add Kym to datastore.
 func (db *AppDatastore) AddKym(kym *model.Kym) error {
 	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
@@ -231,27 +272,28 @@ func (db *AppDatastore) AddKym(kym *model.Kym) error {
 	return err
 }
 
-// GetAllKym attempts to get all kym from datastore.
-func (db *AppDatastore) GetAllKym(status string) ([]*model.Kym, error) {
+// GetAllKym attempts to get a page of kym from datastore, newest first.
+func (db *AppDatastore) GetAllKym(status string, page Page) ([]*model.Kym, string, error) {
 	ctx := context.Background()
 
 	var query *datastore.Query
 
 	if len(status) > 0 {
 		if c.IsValidKymStatus(status) {
-			query = datastore.NewQuery(db.KindKym).Filter("Status=", status).Order("-DatetimeCreated").Limit(30)
+			query = datastore.NewQuery(db.KindKym).Filter("Status=", status).Order("-DatetimeCreated")
 		} else {
-			return nil, &c.ErrValidation{Violations: errors.New(fmt.Sprintf("filter is not allowed with status : %v", status))}
+			return nil, "", &c.ErrValidation{Violations: errors.New(fmt.Sprintf("filter is not allowed with status : %v", status))}
 		}
 	} else {
-		query = datastore.NewQuery(db.KindKym).Order("-DatetimeCreated").Limit(30)
+		query = datastore.NewQuery(db.KindKym).Order("-DatetimeCreated")
 	}
 
 	klmList := make([]*model.Kym, 0)
-	if _, err := db.client.GetAll(ctx, query, &klmList); err != nil {
-		return nil, err
+	next, err := db.getPage(ctx, query, page, &klmList)
+	if err != nil {
+		return nil, "", err
 	}
-	return klmList, nil
+	return klmList, next, nil
 }
 
 // GetKym attempts to get single kym from datastore by id.
@@ -293,6 +335,10 @@ func (db *AppDatastore) UpdateKymStatus(kym *model.Kym, status string, notes str
 // Since merchantID == organisationID, we do not allow duplicate organisationIDs
 func (db *AppDatastore) AddTeacher(m *model.Teacher) error {
 	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		if err := db.checkMainSubjectExists(tx, m.MainSubjectID); err != nil {
+			return err
+		}
+
 		key := db.teacherKey(m.ID)
 		err := tx.Get(key, &model.Teacher{})
 
@@ -310,68 +356,133 @@ func (db *AppDatastore) AddTeacher(m *model.Teacher) error {
 	return err
 }
 
-// GetAllTeacher attempts to get all kym from datastore.
-func (db *AppDatastore) GetAllTeacher() ([]*model.Teacher, error) {
+// GetAllTeacher attempts to get a page of the teachers matching tq from datastore.
+func (db *AppDatastore) GetAllTeacher(tq TeacherQuery, page Page) ([]*model.Teacher, string, error) {
 	ctx := context.Background()
 
-	var query *datastore.Query
-
-	query = datastore.NewQuery(db.KindTeacher).Limit(30)
+	query := datastore.NewQuery(db.KindTeacher)
+	if len(tq.MainSubjectID) > 0 {
+		query = query.Filter("MainSubjectID =", tq.MainSubjectID)
+	}
+	if q := strings.ToLower(strings.TrimSpace(tq.Q)); len(q) > 0 {
+		query = query.Filter(model.TeacherNamePrefixes+" =", q)
+	}
+	if len(tq.Sort) > 0 {
+		query = query.Order(string(tq.Sort))
+	}
 	teacherList := make([]*model.Teacher, 0)
-	if _, err := db.client.GetAll(ctx, query, &teacherList); err != nil {
-		return nil, err
+	next, err := db.getPage(ctx, query, page, &teacherList)
+	if err != nil {
+		return nil, "", err
 	}
-	return teacherList, nil
+	return teacherList, next, nil
 }
 
+// GetTeacher attempts to get single teacher from datastore by id.
+func (db *AppDatastore) GetTeacher(id string) (*model.Teacher, error) {
+	key := db.teacherKey(id)
+	t := &model.Teacher{}
+	err := db.client.Get(context.Background(), key, t)
+	switch err {
+	case nil:
+		return t, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
 
-// AddMainSubject attempts to add a NewMerchant to the datastore.
-func (db *AppDatastore) AddMainSubject(m *model.MainSubject) error {
+// UpdateTeacherAvailability attempts to replace the availability of an existing teacher.
+func (db *AppDatastore) UpdateTeacherAvailability(id string, availability []model.AvailabilityWindow, exceptions []model.AvailabilityException) error {
 	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
-		key := db.mainSubjectKey(m.ID)
-		err := tx.Get(key, &model.MainSubject{})
-
+		key := db.teacherKey(id)
+		old := &model.Teacher{}
+		err := tx.Get(key, old)
 		switch err {
 		case nil:
-			return c.ErrDBEntityAlreadyExists
-		case datastore.ErrNoSuchEntity:
-			// no existing entity id, proceed with write
-			_, err = tx.Put(key, m)
+			old.Availability = availability
+			old.AvailabilityExceptions = exceptions
+			_, err = tx.Put(key, old)
 			return err
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
 		}
 		return err
 	})
-
 	return err
 }
 
-// GetAllMainSubject attempts to get all kym from datastore.
-func (db *AppDatastore) GetAllMainSubject() ([]*model.MainSubject, error) {
-	ctx := context.Background()
+// UpdateTeacher attempts to replace an existing teacher.
+func (db *AppDatastore) UpdateTeacher(m *model.Teacher) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		if err := db.checkMainSubjectExists(tx, m.MainSubjectID); err != nil {
+			return err
+		}
 
-	var query *datastore.Query
+		key := db.teacherKey(m.ID)
+		err := tx.Get(key, &model.Teacher{})
+		switch err {
+		case nil:
+			_, err = tx.Put(key, m)
+			return err
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		}
+		return err
+	})
+	return err
+}
 
-	query = datastore.NewQuery(db.KindMainSubject).Limit(30)
-	mainSubjectList := make([]*model.MainSubject, 0)
-	if _, err := db.client.GetAll(ctx, query, &mainSubjectList); err != nil {
-		return nil, err
-	}
-	return mainSubjectList, nil
+// DeleteTeacher attempts to remove an existing teacher and the responsibilities stored under it.
+func (db *AppDatastore) DeleteTeacher(id string) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.teacherKey(id)
+		err := tx.Get(key, &model.Teacher{})
+		switch err {
+		case nil:
+			query := datastore.NewQuery(db.KindTeacherResponsibility).Ancestor(key).KeysOnly().Transaction(tx)
+			keys, err := db.client.GetAll(context.Background(), query, nil)
+			if err != nil {
+				return err
+			}
+			return tx.DeleteMulti(append(keys, key))
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		}
+		return err
+	})
+	return err
 }
 
+// AddTeacherResponsibility attempts to qualify a teacher for a subject.
+// Both the teacher and the subject need to exist, and a teacher can only be qualified once per subject.
+func (db *AppDatastore) AddTeacherResponsibility(tr *model.TeacherResponsibility) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		switch err := tx.Get(db.teacherKey(tr.TeacherId), &model.Teacher{}); err {
+		case nil:
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
 
+		switch err := tx.Get(db.subjectKey(tr.SubjectId), &model.Subject{}); err {
+		case nil:
+		case datastore.ErrNoSuchEntity:
+			return &c.ErrValidation{Violations: fmt.Errorf("subject %v does not exist", tr.SubjectId)}
+		default:
+			return err
+		}
 
-func (db *AppDatastore) AddSubject(m *model.Subject) error {
-	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
-		key := db.subjectKey(m.ID)
-		err := tx.Get(key, &model.Subject{})
+		key := db.teacherResponsibilityKey(tr.TeacherId, tr.SubjectId)
+		err := tx.Get(key, &model.TeacherResponsibility{})
 
 		switch err {
 		case nil:
 			return c.ErrDBEntityAlreadyExists
 		case datastore.ErrNoSuchEntity:
 			// no existing entity id, proceed with write
-			_, err = tx.Put(key, m)
+			_, err = tx.Put(key, tr)
 			return err
 		}
 		return err
@@ -380,87 +491,1645 @@ func (db *AppDatastore) AddSubject(m *model.Subject) error {
 	return err
 }
 
-
-func (db *AppDatastore) GetAllSubject() ([]*model.Subject, error) {
+// GetAllTeacherResponsibility attempts to get a page of teacher qualifications from datastore.
+// Empty teacherID or subjectID values are not filtered on.
+func (db *AppDatastore) GetAllTeacherResponsibility(teacherID string, subjectID string, page Page) ([]*model.TeacherResponsibility, string, error) {
 	ctx := context.Background()
 
-	var query *datastore.Query
+	query := datastore.NewQuery(db.KindTeacherResponsibility)
+	if len(teacherID) > 0 {
+		query = query.Ancestor(db.teacherKey(teacherID))
+	}
+	if len(subjectID) > 0 {
+		query = query.Filter("SubjectId =", subjectID)
+	}
 
-	query = datastore.NewQuery(db.KindSubject).Limit(30)
-	subjectList := make([]*model.Subject, 0)
-	if _, err := db.client.GetAll(ctx, query, &subjectList); err != nil {
-		return nil, err
+	list := make([]*model.TeacherResponsibility, 0)
+	next, err := db.getPage(ctx, query, page, &list)
+	if err != nil {
+		return nil, "", err
 	}
-	return subjectList, nil
+	return list, next, nil
 }
 
-
-func (db *AppDatastore) AddConfirmation(m *model.Confirmation) error {
+// ReplaceTeacherResponsibility attempts to replace all qualifications of a teacher in a single transaction.
+// A subject listed more than once is stored once.
+func (db *AppDatastore) ReplaceTeacherResponsibility(teacherID string, subjectIDs []string) error {
 	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
-		key := db.confirmationKey(m.ID)
-		err := tx.Get(key, &model.Confirmation{})
-
-		switch err {
+		teacherKey := db.teacherKey(teacherID)
+		switch err := tx.Get(teacherKey, &model.Teacher{}); err {
 		case nil:
-			return c.ErrDBEntityAlreadyExists
 		case datastore.ErrNoSuchEntity:
-			// no existing entity id, proceed with write
-			_, err = tx.Put(key, m)
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
+
+		list := model.NewTeacherResponsibilities(teacherID, subjectIDs)
+		keys := make([]*datastore.Key, len(list))
+		subjectKeys := make([]*datastore.Key, len(list))
+		for i, tr := range list {
+			keys[i] = db.teacherResponsibilityKey(teacherID, tr.SubjectId)
+			subjectKeys[i] = db.subjectKey(tr.SubjectId)
+		}
+
+		if len(subjectKeys) > 0 {
+			if err := tx.GetMulti(subjectKeys, make([]model.Subject, len(subjectKeys))); err != nil {
+				merr, ok := err.(datastore.MultiError)
+				if !ok {
+					return err
+				}
+				missing := make([]string, 0)
+				for i, e := range merr {
+					switch e {
+					case nil:
+					case datastore.ErrNoSuchEntity:
+						missing = append(missing, list[i].SubjectId)
+					default:
+						return e
+					}
+				}
+				return &c.ErrValidation{Violations: fmt.Errorf("subjects %v do not exist", missing)}
+			}
+		}
+
+		query := datastore.NewQuery(db.KindTeacherResponsibility).Ancestor(teacherKey).KeysOnly().Transaction(tx)
+		existing, err := db.client.GetAll(context.Background(), query, nil)
+		if err != nil {
 			return err
 		}
+		if err := tx.DeleteMulti(existing); err != nil {
+			return err
+		}
+
+		_, err = tx.PutMulti(keys, list)
 		return err
 	})
-
 	return err
 }
 
-
-func (db *AppDatastore) AddConfirmationDetail(m *model.ConfirmationDetail) error {
+// DeleteTeacherResponsibility attempts to remove a single qualification of a teacher.
+func (db *AppDatastore) DeleteTeacherResponsibility(teacherID string, subjectID string) error {
 	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
-		key := db.confirmationDetailKey(m.ID)
-		err := tx.Get(key, &model.ConfirmationDetail{})
-
+		key := db.teacherResponsibilityKey(teacherID, subjectID)
+		err := tx.Get(key, &model.TeacherResponsibility{})
 		switch err {
 		case nil:
-			return c.ErrDBEntityAlreadyExists
+			return tx.Delete(key)
 		case datastore.ErrNoSuchEntity:
-			// no existing entity id, proceed with write
-			_, err = tx.Put(key, m)
-			return err
+			return c.ErrDBNoSuchEntity
 		}
 		return err
 	})
-
 	return err
 }
 
+// GetAllTeacherBySubject attempts to get the teachers qualified for a subject from datastore,
+// either through their main subject or an explicit responsibility.
+// The union of both queries is merged in memory and paged by teacher id.
+// Returns c.ErrDBNoSuchEntity when the subject does not exist.
+func (db *AppDatastore) GetAllTeacherBySubject(subjectID string, page Page) ([]*model.Teacher, string, error) {
+	ctx := context.Background()
+
+	subject := &model.Subject{}
+	switch err := db.client.Get(ctx, db.subjectKey(subjectID), subject); err {
+	case nil:
+	case datastore.ErrNoSuchEntity:
+		return nil, "", c.ErrDBNoSuchEntity
+	default:
+		return nil, "", err
+	}
+
+	list := make([]*model.Teacher, 0)
+	query := datastore.NewQuery(db.KindTeacher).Filter("MainSubjectID =", subject.MainSubjectId)
+	if _, err := db.client.GetAll(ctx, query, &list); err != nil {
+		return nil, "", err
+	}
+
+	seen := make(map[string]bool, len(list))
+	for _, t := range list {
+		seen[t.ID] = true
+	}
+
+	query = datastore.NewQuery(db.KindTeacherResponsibility).Filter("SubjectId =", subjectID).KeysOnly()
+	keys, err := db.client.GetAll(ctx, query, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	teacherKeys := make([]*datastore.Key, 0, len(keys))
+	for _, k := range keys {
+		if !seen[k.Parent.Name] {
+			teacherKeys = append(teacherKeys, k.Parent)
+		}
+	}
+
+	teachers := make([]model.Teacher, len(teacherKeys))
+	found, err := db.getMultiExisting(ctx, teacherKeys, teachers)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range teachers {
+		if found[i] {
+			list = append(list, &teachers[i])
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	ids := make([]string, len(list))
+	for i, t := range list {
+		ids[i] = t.ID
+	}
 
+	start, end, next, err := pageByID(ids, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list[start:end], next, nil
+}
 
-func (db *AppDatastore) GetAllConfirmation() ([]*model.Confirmation, error) {
+// GetAllSubjectByTeacher attempts to get the subjects a teacher is qualified for from datastore,
+// either through the teacher's main subject or an explicit responsibility.
+// The union of both queries is merged in memory and paged by subject id.
+// Returns c.ErrDBNoSuchEntity when the teacher does not exist.
+func (db *AppDatastore) GetAllSubjectByTeacher(teacherID string, page Page) ([]*model.Subject, string, error) {
 	ctx := context.Background()
 
-	var query *datastore.Query
+	teacher := &model.Teacher{}
+	switch err := db.client.Get(ctx, db.teacherKey(teacherID), teacher); err {
+	case nil:
+	case datastore.ErrNoSuchEntity:
+		return nil, "", c.ErrDBNoSuchEntity
+	default:
+		return nil, "", err
+	}
 
-	query = datastore.NewQuery(db.KindConfirmation).Limit(100)
-	list := make([]*model.Confirmation, 0)
+	list := make([]*model.Subject, 0)
+	query := datastore.NewQuery(db.KindSubject).Filter("MainSubjectId =", teacher.MainSubjectID)
 	if _, err := db.client.GetAll(ctx, query, &list); err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return list, nil
+
+	seen := make(map[string]bool, len(list))
+	for _, s := range list {
+		seen[s.ID] = true
+	}
+
+	query = datastore.NewQuery(db.KindTeacherResponsibility).Ancestor(db.teacherKey(teacherID)).KeysOnly()
+	keys, err := db.client.GetAll(ctx, query, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	subjectKeys := make([]*datastore.Key, 0, len(keys))
+	for _, k := range keys {
+		if !seen[k.Name] {
+			subjectKeys = append(subjectKeys, db.subjectKey(k.Name))
+		}
+	}
+
+	subjects := make([]model.Subject, len(subjectKeys))
+	found, err := db.getMultiExisting(ctx, subjectKeys, subjects)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range subjects {
+		if found[i] {
+			list = append(list, &subjects[i])
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	ids := make([]string, len(list))
+	for i, s := range list {
+		ids[i] = s.ID
+	}
+
+	start, end, next, err := pageByID(ids, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list[start:end], next, nil
 }
 
+// getPage loads a single page of the query into dst, which must be a pointer to a slice of entity pointers.
+// The returned token is the cursor following the last loaded entity and is empty on the last page.
+func (db *AppDatastore) getPage(ctx context.Context, query *datastore.Query, page Page, dst interface{}) (string, error) {
+	if len(page.Token) > 0 {
+		cursor, err := datastore.DecodeCursor(page.Token)
+		if err != nil {
+			return "", &c.ErrValidation{Violations: fmt.Errorf("invalid pageToken %q", page.Token)}
+		}
+		query = query.Start(cursor)
+	}
 
-func (db *AppDatastore) GetAllConfirmationDetail(confirmationId string) ([]*model.ConfirmationDetail, error) {
-	ctx := context.Background()
+	list := reflect.ValueOf(dst).Elem()
+	entityType := list.Type().Elem().Elem()
+
+	// one entity more than the page size is read to find out whether another page follows
+	it := db.client.Run(ctx, query.Limit(page.Size+1))
+	var cursor datastore.Cursor
+	for i := 0; ; i++ {
+		entity := reflect.New(entityType)
+		_, err := it.Next(entity.Interface())
+		if err == iterator.Done {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if i == page.Size {
+			return cursor.String(), nil
+		}
 
-	var query *datastore.Query
+		list.Set(reflect.Append(list, entity))
+		if i == page.Size-1 {
+			if cursor, err = it.Cursor(); err != nil {
+				return "", err
+			}
+		}
+	}
+}
 
-	query = datastore.NewQuery(db.KindConfirmationDetail).Filter("ConfirmationID =", confirmationId)
-	list := make([]*model.ConfirmationDetail, 0)
-	if _, err := db.client.GetAll(ctx, query, &list); err != nil {
+// pageByID pages a list sorted by id which was merged in memory and so has no query cursor.
+// It returns the bounds of the page in ids, the token is the encoded id of the last entity on the page.
+func pageByID(ids []string, page Page) (int, int, string, error) {
+	start := 0
+	if len(page.Token) > 0 {
+		last, err := base64.RawURLEncoding.DecodeString(page.Token)
+		if err != nil {
+			return 0, 0, "", &c.ErrValidation{Violations: fmt.Errorf("invalid pageToken %q", page.Token)}
+		}
+		start = sort.Search(len(ids), func(i int) bool { return ids[i] > string(last) })
+	}
+
+	end := start + page.Size
+	if end >= len(ids) {
+		return start, len(ids), "", nil
+	}
+	return start, end, base64.RawURLEncoding.EncodeToString([]byte(ids[end-1])), nil
+}
+
+// getMultiExisting loads the keys into dst and reports which of them exist.
+// Entities that no longer exist are skipped rather than failing the whole lookup.
+func (db *AppDatastore) getMultiExisting(ctx context.Context, keys []*datastore.Key, dst interface{}) ([]bool, error) {
+	if len(keys) == 0 {
+		return make([]bool, 0), nil
+	}
+	return existingFromMultiError(len(keys), db.client.GetMulti(ctx, keys, dst))
+}
+
+// getMultiExistingTx is getMultiExisting inside a transaction
+func (db *AppDatastore) getMultiExistingTx(tx *datastore.Transaction, keys []*datastore.Key, dst interface{}) ([]bool, error) {
+	if len(keys) == 0 {
+		return make([]bool, 0), nil
+	}
+	return existingFromMultiError(len(keys), tx.GetMulti(keys, dst))
+}
+
+// existingFromMultiError reports which of n entities were found given the error returned by GetMulti
+func existingFromMultiError(n int, err error) ([]bool, error) {
+	found := make([]bool, n)
+	if err == nil {
+		for i := range found {
+			found[i] = true
+		}
+		return found, nil
+	}
+
+	merr, ok := err.(datastore.MultiError)
+	if !ok {
 		return nil, err
 	}
-	return list, nil
+	for i, e := range merr {
+		switch e {
+		case nil:
+			found[i] = true
+		case datastore.ErrNoSuchEntity:
+		default:
+			return nil, e
+		}
+	}
+	return found, nil
+}
+
+// uniqueKeys drops repeated keys, a transaction cannot mutate the same entity twice
+func uniqueKeys(keys []*datastore.Key) []*datastore.Key {
+	seen := make(map[string]bool, len(keys))
+	res := make([]*datastore.Key, 0, len(keys))
+	for _, k := range keys {
+		if seen[k.String()] {
+			continue
+		}
+		seen[k.String()] = true
+		res = append(res, k)
+	}
+	return res
 }
 
+// AddMainSubject attempts to add a NewMerchant to the datastore.
+func (db *AppDatastore) AddMainSubject(m *model.MainSubject) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.mainSubjectKey(m.ID)
+		err := tx.Get(key, &model.MainSubject{})
 
+		switch err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			// no existing entity id, proceed with write
+			_, err = tx.Put(key, m)
+			return err
+		}
+		return err
+	})
+
+	return err
+}
+
+// GetAllMainSubject attempts to get a page of main subjects from datastore.
+func (db *AppDatastore) GetAllMainSubject(page Page) ([]*model.MainSubject, string, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindMainSubject)
+	mainSubjectList := make([]*model.MainSubject, 0)
+	next, err := db.getPage(ctx, query, page, &mainSubjectList)
+	if err != nil {
+		return nil, "", err
+	}
+	return mainSubjectList, next, nil
+}
+
+
+// GetMainSubject attempts to get single main subject from datastore by id.
+func (db *AppDatastore) GetMainSubject(id string) (*model.MainSubject, error) {
+	key := db.mainSubjectKey(id)
+	m := &model.MainSubject{}
+	err := db.client.Get(context.Background(), key, m)
+	switch err {
+	case nil:
+		return m, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
+
+// UpdateMainSubject attempts to replace an existing main subject.
+func (db *AppDatastore) UpdateMainSubject(m *model.MainSubject) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.mainSubjectKey(m.ID)
+		err := tx.Get(key, &model.MainSubject{})
+		switch err {
+		case nil:
+			_, err = tx.Put(key, m)
+			return err
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		}
+		return err
+	})
+	return err
+}
+
+// DeleteMainSubject attempts to remove an existing main subject.
+// Subjects and teachers referring to the main subject block the delete with c.ErrConflict. When cascade
+// is set they are removed in the same transaction, together with the responsibilities attached to them,
+// unless confirmation details ask for one of the subjects.
+func (db *AppDatastore) DeleteMainSubject(id string, cascade bool) error {
+	ctx := context.Background()
+
+	// non-ancestor queries cannot run inside a transaction, the references are looked up first
+	// and re-checked inside it so a concurrent change aborts the delete instead of orphaning data
+	query := datastore.NewQuery(db.KindSubject).Filter("MainSubjectId =", id).KeysOnly()
+	subjectKeys, err := db.client.GetAll(ctx, query, nil)
+	if err != nil {
+		return err
+	}
+	query = datastore.NewQuery(db.KindTeacher).Filter("MainSubjectID =", id).KeysOnly()
+	teacherKeys, err := db.client.GetAll(ctx, query, nil)
+	if err != nil {
+		return err
+	}
+
+	responsibilityKeys := make([]*datastore.Key, 0)
+	detailKeys := make([]*datastore.Key, 0)
+	if cascade {
+		for _, k := range subjectKeys {
+			keys, err := db.subjectResponsibilityKeys(ctx, k.Name)
+			if err != nil {
+				return err
+			}
+			responsibilityKeys = append(responsibilityKeys, keys...)
+			keys, err = db.subjectDetailKeys(ctx, k.Name)
+			if err != nil {
+				return err
+			}
+			detailKeys = append(detailKeys, keys...)
+		}
+	}
+
+	_, err = db.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		key := db.mainSubjectKey(id)
+		switch err := tx.Get(key, &model.MainSubject{}); err {
+		case nil:
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
+
+		subjects := make([]model.Subject, len(subjectKeys))
+		foundSubjects, err := db.getMultiExistingTx(tx, subjectKeys, subjects)
+		if err != nil {
+			return err
+		}
+		teachers := make([]model.Teacher, len(teacherKeys))
+		foundTeachers, err := db.getMultiExistingTx(tx, teacherKeys, teachers)
+		if err != nil {
+			return err
+		}
+
+		keys := []*datastore.Key{key}
+		deletedSubjects := make(map[string]bool)
+		for i, k := range subjectKeys {
+			if foundSubjects[i] && subjects[i].MainSubjectId == id {
+				keys = append(keys, k)
+				deletedSubjects[k.Name] = true
+			}
+		}
+		for i, k := range teacherKeys {
+			if foundTeachers[i] && teachers[i].MainSubjectID == id {
+				keys = append(keys, k)
+			}
+		}
+
+		if len(keys) > 1 && !cascade {
+			return fmt.Errorf("%w: main subject %v is still used by %d subjects and teachers", c.ErrConflict, id, len(keys)-1)
+		}
+		asked, err := db.countSubjectDetails(tx, uniqueKeys(detailKeys), deletedSubjects)
+		if err != nil {
+			return err
+		}
+		if asked > 0 {
+			return fmt.Errorf("%w: subjects of main subject %v are still asked for by %d confirmation details", c.ErrConflict, id, asked)
+		}
+
+		for _, k := range keys {
+			if k.Kind != db.KindTeacher {
+				continue
+			}
+			query := datastore.NewQuery(db.KindTeacherResponsibility).Ancestor(k).KeysOnly().Transaction(tx)
+			children, err := db.client.GetAll(ctx, query, nil)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, children...)
+		}
+		keys = append(keys, responsibilityKeys...)
+
+		return tx.DeleteMulti(uniqueKeys(keys))
+	})
+	return err
+}
+
+
+func (db *AppDatastore) AddSubject(m *model.Subject) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		if err := db.checkMainSubjectExists(tx, m.MainSubjectId); err != nil {
+			return err
+		}
+
+		key := db.subjectKey(m.ID)
+		err := tx.Get(key, &model.Subject{})
+
+		switch err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			// no existing entity id, proceed with write
+			_, err = tx.Put(key, m)
+			return err
+		}
+		return err
+	})
+
+	return err
+}
+
+
+// GetAllSubject attempts to get a page of the subjects matching sq from datastore.
+func (db *AppDatastore) GetAllSubject(sq SubjectQuery, page Page) ([]*model.Subject, string, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindSubject)
+	if len(sq.MainSubjectID) > 0 {
+		query = query.Filter("MainSubjectId =", sq.MainSubjectID)
+	}
+	if sq.MinStudentsGte > 0 {
+		query = query.Filter("MinOfStudent >=", sq.MinStudentsGte)
+	}
+	subjectList := make([]*model.Subject, 0)
+	next, err := db.getPage(ctx, query, page, &subjectList)
+	if err != nil {
+		return nil, "", err
+	}
+	return subjectList, next, nil
+}
+
+// GetSubject attempts to get single subject from datastore by id.
+func (db *AppDatastore) GetSubject(id string) (*model.Subject, error) {
+	key := db.subjectKey(id)
+	m := &model.Subject{}
+	err := db.client.Get(context.Background(), key, m)
+	switch err {
+	case nil:
+		return m, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
+
+// UpdateSubject attempts to replace an existing subject.
+func (db *AppDatastore) UpdateSubject(m *model.Subject) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		if err := db.checkMainSubjectExists(tx, m.MainSubjectId); err != nil {
+			return err
+		}
+
+		key := db.subjectKey(m.ID)
+		err := tx.Get(key, &model.Subject{})
+		switch err {
+		case nil:
+			_, err = tx.Put(key, m)
+			return err
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		}
+		return err
+	})
+	return err
+}
+
+// DeleteSubject attempts to remove an existing subject.
+// Teacher responsibilities for the subject block the delete with c.ErrConflict, unless cascade is set
+// in which case they are removed in the same transaction. Confirmation details asking for the subject
+// always block it, they are never cascaded.
+func (db *AppDatastore) DeleteSubject(id string, cascade bool) error {
+	ctx := context.Background()
+
+	responsibilityKeys, err := db.subjectResponsibilityKeys(ctx, id)
+	if err != nil {
+		return err
+	}
+	detailKeys, err := db.subjectDetailKeys(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		key := db.subjectKey(id)
+		switch err := tx.Get(key, &model.Subject{}); err {
+		case nil:
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
+
+		asked, err := db.countSubjectDetails(tx, detailKeys, map[string]bool{id: true})
+		if err != nil {
+			return err
+		}
+		if asked > 0 {
+			return fmt.Errorf("%w: subject %v is still asked for by %d confirmation details", c.ErrConflict, id, asked)
+		}
+
+		responsibilities := make([]model.TeacherResponsibility, len(responsibilityKeys))
+		found, err := db.getMultiExistingTx(tx, responsibilityKeys, responsibilities)
+		if err != nil {
+			return err
+		}
+
+		keys := []*datastore.Key{key}
+		for i, k := range responsibilityKeys {
+			if found[i] {
+				keys = append(keys, k)
+			}
+		}
+
+		if len(keys) > 1 && !cascade {
+			return fmt.Errorf("%w: subject %v is still assigned to %d teachers", c.ErrConflict, id, len(keys)-1)
+		}
+
+		return tx.DeleteMulti(keys)
+	})
+	return err
+}
+
+// subjectResponsibilityKeys gets the keys of all teacher responsibilities for the subject
+func (db *AppDatastore) subjectResponsibilityKeys(ctx context.Context, subjectID string) ([]*datastore.Key, error) {
+	query := datastore.NewQuery(db.KindTeacherResponsibility).Filter("SubjectId =", subjectID).KeysOnly()
+	return db.client.GetAll(ctx, query, nil)
+}
+
+// subjectDetailKeys gets the keys of all confirmation details asking for one of the subjects
+func (db *AppDatastore) subjectDetailKeys(ctx context.Context, subjectIDs ...string) ([]*datastore.Key, error) {
+	keys := make([]*datastore.Key, 0)
+	for _, subjectID := range subjectIDs {
+		query := datastore.NewQuery(db.KindConfirmationDetail).Filter("SubjectDetailID =", subjectID).KeysOnly()
+		found, err := db.client.GetAll(ctx, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, found...)
+	}
+	return uniqueKeys(keys), nil
+}
+
+// countSubjectDetails reads the details again in the transaction and counts those still asking for one of
+// the subjects, a detail added meanwhile has read the subjects in its own transaction and conflicts with it
+func (db *AppDatastore) countSubjectDetails(tx *datastore.Transaction, keys []*datastore.Key, subjectIDs map[string]bool) (int, error) {
+	details := make([]model.ConfirmationDetail, len(keys))
+	found, err := db.getMultiExistingTx(tx, keys, details)
+	if err != nil {
+		return 0, err
+	}
+	asked := 0
+	for i := range keys {
+		if !found[i] {
+			continue
+		}
+		for _, subjectID := range details[i].SubjectDetailID {
+			if subjectIDs[subjectID] {
+				asked++
+				break
+			}
+		}
+	}
+	return asked, nil
+}
+
+// checkMainSubjectExists returns a validation error when the main subject referred to does not exist
+func (db *AppDatastore) checkMainSubjectExists(tx *datastore.Transaction, id string) error {
+	switch err := tx.Get(db.mainSubjectKey(id), &model.MainSubject{}); err {
+	case nil:
+		return nil
+	case datastore.ErrNoSuchEntity:
+		return &c.ErrValidation{Violations: fmt.Errorf("main subject %v does not exist", id)}
+	default:
+		return err
+	}
+}
+
+
+func (db *AppDatastore) AddConfirmation(m *model.Confirmation) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		if err := db.checkBellScheduleExists(tx, m.BellScheduleID); err != nil {
+			return err
+		}
+		if err := db.checkTermExists(tx, m.TermID); err != nil {
+			return err
+		}
+
+		key := db.confirmationKey(m.ID)
+		err := tx.Get(key, &model.Confirmation{})
+
+		switch err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			// no existing entity id, proceed with write
+			_, err = tx.Put(key, m)
+			return err
+		}
+		return err
+	})
+
+	return err
+}
+
+
+// AddConfirmationDetail attempts to add a detail to its confirmation in a transaction. The confirmation has
+// to accept details and the subjects have to exist, a second detail of the student on the same day and
+// period of the confirmation is rejected. Both fail with c.ErrValidation naming the offending ids.
+func (db *AppDatastore) AddConfirmationDetail(m *model.ConfirmationDetail) error {
+	ctx := context.Background()
+	_, err := db.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		confirmationKey, confirmation, err := db.getConfirmationAcceptingDetails(tx, m.ConfirmationID)
+		if err != nil {
+			return err
+		}
+		if err := db.checkSubjectsExist(tx, m.SubjectDetailID); err != nil {
+			return err
+		}
+		if err := db.checkDuplicateDetail(ctx, tx, m); err != nil {
+			return err
+		}
+
+		key := db.confirmationDetailKey(m.ID)
+		err = tx.Get(key, &model.ConfirmationDetail{})
+
+		switch err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			// the confirmation is written back so details added to it concurrently conflict, the
+			// retried transaction then finds the other detail when looking for duplicates
+			_, err = tx.PutMulti([]*datastore.Key{key, confirmationKey}, []interface{}{m, confirmation})
+			return err
+		}
+		return err
+	})
+
+	return err
+}
+
+
+// getConfirmationAcceptingDetails gets the confirmation of details about to be added. It fails when the
+// confirmation does not exist or its status no longer lets details be added, so a confirmation locked since
+// the caller read it is not written to.
+func (db *AppDatastore) getConfirmationAcceptingDetails(tx *datastore.Transaction, id string) (*datastore.Key, *model.Confirmation, error) {
+	key := db.confirmationKey(id)
+	confirmation := &model.Confirmation{}
+	switch err := tx.Get(key, confirmation); err {
+	case nil:
+		return key, confirmation, confirmation.CheckAcceptsDetails()
+	case datastore.ErrNoSuchEntity:
+		return nil, nil, &c.ErrValidation{Violations: fmt.Errorf("confirmation %v does not exist", id)}
+	default:
+		return nil, nil, err
+	}
+}
+
+// checkSubjectsExist fails with c.ErrValidation listing the subjects which do not exist
+func (db *AppDatastore) checkSubjectsExist(tx *datastore.Transaction, ids []string) error {
+	keys := make([]*datastore.Key, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, db.subjectKey(id))
+	}
+	found, err := db.getMultiExistingTx(tx, keys, make([]model.Subject, len(keys)))
+	if err != nil {
+		return err
+	}
+
+	missing := make([]string, 0)
+	for i, id := range ids {
+		if !found[i] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return &c.ErrValidation{Violations: fmt.Errorf("subjects %v do not exist", missing)}
+	}
+	return nil
+}
+
+// checkDuplicateDetail fails with c.ErrValidation when the confirmation already has a detail of the student
+// on the day and period of m. Details stored while days and periods were free text are only found once
+// MigrateDayPeriod has rewritten them.
+func (db *AppDatastore) checkDuplicateDetail(ctx context.Context, tx *datastore.Transaction, m *model.ConfirmationDetail) error {
+	// non-ancestor queries cannot run inside a transaction, the details found are re-read inside it
+	query := datastore.NewQuery(db.KindConfirmationDetail).
+		Filter("ConfirmationID =", m.ConfirmationID).
+		Filter("Day =", string(m.Day)).
+		Filter("Period =", int64(m.Period)).
+		KeysOnly()
+	keys, err := db.client.GetAll(ctx, query, nil)
+	if err != nil {
+		return err
+	}
+
+	details := make([]model.ConfirmationDetail, len(keys))
+	found, err := db.getMultiExistingTx(tx, keys, details)
+	if err != nil {
+		return err
+	}
+	for i, d := range details {
+		if found[i] && d.ID != m.ID && d.ConfirmationID == m.ConfirmationID && d.Day == m.Day && d.Period == m.Period &&
+			d.StudentKey() == m.StudentKey() {
+			return &c.ErrValidation{Violations: fmt.Errorf("student %v already has detail %v on %v period %d of confirmation %v",
+				m.StudentKey(), d.ID, m.Day, m.Period, m.ConfirmationID)}
+		}
+	}
+	return nil
+}
+
+
+// maxPutMulti is the largest number of entities datastore accepts in a single PutMulti
+const maxPutMulti = 500
+
+// maxDetailBatch is the number of details AddConfirmationDetails writes per transaction, their confirmation
+// is written with them and a single PutMulti accepts maxPutMulti entities
+const maxDetailBatch = maxPutMulti - 1
+
+// AddConfirmationDetails attempts to add the confirmation details to datastore in as few transactions as
+// possible. Every batch checks its details the way AddConfirmationDetail does, so a confirmation locked or a
+// duplicate added while the details are written stops the batch. The batches written before are kept.
+func (db *AppDatastore) AddConfirmationDetails(list []*model.ConfirmationDetail) error {
+	ctx := context.Background()
+
+	// every batch holds the details of a single confirmation
+	order := make([]string, 0)
+	byConfirmation := make(map[string][]*model.ConfirmationDetail)
+	for _, m := range list {
+		if _, ok := byConfirmation[m.ConfirmationID]; !ok {
+			order = append(order, m.ConfirmationID)
+		}
+		byConfirmation[m.ConfirmationID] = append(byConfirmation[m.ConfirmationID], m)
+	}
+
+	for _, confirmationID := range order {
+		details := byConfirmation[confirmationID]
+		for start := 0; start < len(details); start += maxDetailBatch {
+			end := start + maxDetailBatch
+			if end > len(details) {
+				end = len(details)
+			}
+			if err := db.addConfirmationDetailBatch(ctx, confirmationID, details[start:end]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// detailSlot is the day and period a student takes lessons in, a confirmation holds a single detail per slot
+type detailSlot struct {
+	student string
+	day     model.Day
+	period  model.Period
+}
+
+// addConfirmationDetailBatch adds details of the confirmation in a single transaction
+func (db *AppDatastore) addConfirmationDetailBatch(ctx context.Context, confirmationID string, batch []*model.ConfirmationDetail) error {
+	_, err := db.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		confirmationKey, confirmation, err := db.getConfirmationAcceptingDetails(tx, confirmationID)
+		if err != nil {
+			return err
+		}
+
+		subjectIDs := make([]string, 0)
+		seen := make(map[string]bool)
+		for _, m := range batch {
+			for _, id := range m.SubjectDetailID {
+				if !seen[id] {
+					seen[id] = true
+					subjectIDs = append(subjectIDs, id)
+				}
+			}
+		}
+		if err := db.checkSubjectsExist(tx, subjectIDs); err != nil {
+			return err
+		}
+
+		// non-ancestor queries cannot run inside a transaction. Details added concurrently write their
+		// confirmation as well, so the transaction conflicts with them and the retry finds them here.
+		stored := make([]*model.ConfirmationDetail, 0)
+		if _, err := db.client.GetAll(ctx, datastore.NewQuery(db.KindConfirmationDetail).Filter("ConfirmationID =", confirmationID), &stored); err != nil {
+			return err
+		}
+		taken := make(map[detailSlot]string, len(stored)+len(batch))
+		for _, d := range stored {
+			taken[detailSlot{d.StudentKey(), d.Day, d.Period}] = d.ID
+		}
+
+		keys := make([]*datastore.Key, 0, len(batch)+1)
+		entities := make([]interface{}, 0, len(batch)+1)
+		for _, m := range batch {
+			slot := detailSlot{m.StudentKey(), m.Day, m.Period}
+			if other, ok := taken[slot]; ok && other != m.ID {
+				return &c.ErrValidation{Violations: fmt.Errorf("student %v already has detail %v on %v period %d of confirmation %v",
+					m.StudentKey(), other, m.Day, m.Period, m.ConfirmationID)}
+			}
+			taken[slot] = m.ID
+			keys = append(keys, db.confirmationDetailKey(m.ID))
+			entities = append(entities, m)
+		}
+
+		found, err := db.getMultiExistingTx(tx, keys, make([]model.ConfirmationDetail, len(keys)))
+		if err != nil {
+			return err
+		}
+		for _, exists := range found {
+			if exists {
+				return c.ErrDBEntityAlreadyExists
+			}
+		}
+
+		_, err = tx.PutMulti(append(keys, confirmationKey), append(entities, confirmation))
+		return err
+	})
+	return err
+}
+
+
+// GetConfirmation attempts to get single confirmation from datastore by id.
+func (db *AppDatastore) GetConfirmation(id string) (*model.Confirmation, error) {
+	key := db.confirmationKey(id)
+	m := &model.Confirmation{}
+	err := db.client.Get(context.Background(), key, m)
+	switch err {
+	case nil:
+		return m, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
+
+// GetAllConfirmation attempts to get a page of confirmations from datastore.
+func (db *AppDatastore) GetAllConfirmation(page Page) ([]*model.Confirmation, string, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindConfirmation)
+	list := make([]*model.Confirmation, 0)
+	next, err := db.getPage(ctx, query, page, &list)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+
+// UpdateConfirmationStatus attempts to move a confirmation to the status in a transaction, so concurrent
+// changes cannot skip a step of the lifecycle.
+func (db *AppDatastore) UpdateConfirmationStatus(id string, status model.ConfirmationStatus, changedBy string, changedAt time.Time) (*model.Confirmation, error) {
+	m := &model.Confirmation{}
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.confirmationKey(id)
+		switch err := tx.Get(key, m); err {
+		case nil:
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
+
+		if !m.Status.CanBecome(status) {
+			return fmt.Errorf("%w: confirmation %v cannot become %v while it is %v", c.ErrConflict, id, status, m.Status)
+		}
+		m.Status = status
+		m.StatusChangedBy = changedBy
+		m.StatusChangedAt = changedAt
+		_, err := tx.Put(key, m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetAllConfirmationDetail attempts to get a page of the details of a confirmation from datastore.
+func (db *AppDatastore) GetAllConfirmationDetail(confirmationId string, page Page) ([]*model.ConfirmationDetail, string, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindConfirmationDetail).Filter("ConfirmationID =", confirmationId)
+	list := make([]*model.ConfirmationDetail, 0)
+	next, err := db.getPage(ctx, query, page, &list)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+
+
+// AddSchedule attempts to add a Schedule and its slots to the datastore.
+// A timetable has more slots than datastore accepts in a single PutMulti or transaction, so the slots are
+// written in batches first and the schedule is written last in a transaction. Until then the slots are not
+// reachable, and slots left behind by a failed add belong to a schedule which never becomes visible.
+func (db *AppDatastore) AddSchedule(s *model.Schedule, slots []*model.ScheduleSlot) error {
+	ctx := context.Background()
+	key := db.scheduleKey(s.ID)
+	switch err := db.client.Get(ctx, key, &model.Schedule{}); err {
+	case nil:
+		return c.ErrDBEntityAlreadyExists
+	case datastore.ErrNoSuchEntity:
+	default:
+		return err
+	}
+
+	for start := 0; start < len(slots); start += maxPutMulti {
+		end := start + maxPutMulti
+		if end > len(slots) {
+			end = len(slots)
+		}
+		keys := make([]*datastore.Key, 0, end-start)
+		for _, slot := range slots[start:end] {
+			keys = append(keys, db.scheduleSlotKey(slot.ID))
+		}
+		if _, err := db.client.PutMulti(ctx, keys, slots[start:end]); err != nil {
+			return err
+		}
+	}
+
+	_, err := db.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		err := tx.Get(key, &model.Schedule{})
+
+		switch err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			// no existing entity id, the slots are written so the schedule can become visible
+			_, err = tx.Put(key, s)
+			return err
+		}
+		return err
+	})
+
+	return err
+}
+
+// GetSchedule attempts to get single schedule from datastore by id.
+func (db *AppDatastore) GetSchedule(id string) (*model.Schedule, error) {
+	key := db.scheduleKey(id)
+	s := &model.Schedule{}
+	err := db.client.Get(context.Background(), key, s)
+	switch err {
+	case nil:
+		return s, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
+
+// GetAllScheduleSlot attempts to get the slots of a schedule from datastore.
+// Empty teacherID or studentID values are not filtered on.
+func (db *AppDatastore) GetAllScheduleSlot(scheduleID string, teacherID string, studentID string) ([]*model.ScheduleSlot, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindScheduleSlot).Filter("ScheduleID =", scheduleID)
+	if len(teacherID) > 0 {
+		query = query.Filter("TeacherID =", teacherID)
+	}
+	if len(studentID) > 0 {
+		// StudentIDs is a list property, equality matches any of its values
+		query = query.Filter("StudentIDs =", studentID)
+	}
+
+	list := make([]*model.ScheduleSlot, 0)
+	if _, err := db.client.GetAll(ctx, query, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetAllStudentScheduleSlot attempts to get the slots of a schedule attended by a student from datastore.
+func (db *AppDatastore) GetAllStudentScheduleSlot(scheduleID string, studentID string) ([]*model.ScheduleSlot, error) {
+	ctx := context.Background()
+
+	// StudentIDs is a list property, equality matches any of its values
+	query := datastore.NewQuery(db.KindScheduleSlot).Filter("ScheduleID =", scheduleID).Filter("StudentIDs =", studentID)
+
+	list := make([]*model.ScheduleSlot, 0)
+	if _, err := db.client.GetAll(ctx, query, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// AddBellSchedule attempts to add a BellSchedule to the datastore.
+func (db *AppDatastore) AddBellSchedule(b *model.BellSchedule) error {
+	return db.putBellSchedule(b, true)
+}
+
+// GetAllBellSchedule attempts to get a page of bell schedules from datastore.
+func (db *AppDatastore) GetAllBellSchedule(page Page) ([]*model.BellSchedule, string, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindBellSchedule)
+	list := make([]*model.BellSchedule, 0)
+	next, err := db.getPage(ctx, query, page, &list)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetBellSchedule attempts to get single bell schedule from datastore by id.
+func (db *AppDatastore) GetBellSchedule(id string) (*model.BellSchedule, error) {
+	key := db.bellScheduleKey(id)
+	b := &model.BellSchedule{}
+	err := db.client.Get(context.Background(), key, b)
+	switch err {
+	case nil:
+		return b, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
+
+// GetDefaultBellSchedule attempts to get the bell schedule marked as default.
+func (db *AppDatastore) GetDefaultBellSchedule() (*model.BellSchedule, error) {
+	query := datastore.NewQuery(db.KindBellSchedule).Filter("Default =", true).Limit(1)
+	list := make([]*model.BellSchedule, 0)
+	if _, err := db.client.GetAll(context.Background(), query, &list); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return list[0], nil
+}
+
+// UpdateBellSchedule attempts to replace an existing bell schedule.
+func (db *AppDatastore) UpdateBellSchedule(b *model.BellSchedule) error {
+	return db.putBellSchedule(b, false)
+}
+
+// putBellSchedule adds the bell schedule when create is set, otherwise replaces the existing one.
+// A default bell schedule unsets the previous default in the same transaction.
+func (db *AppDatastore) putBellSchedule(b *model.BellSchedule, create bool) error {
+	ctx := context.Background()
+
+	defaultKeys := make([]*datastore.Key, 0)
+	if b.Default {
+		query := datastore.NewQuery(db.KindBellSchedule).Filter("Default =", true).KeysOnly()
+		keys, err := db.client.GetAll(ctx, query, nil)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if k.Name != b.ID {
+				defaultKeys = append(defaultKeys, k)
+			}
+		}
+	}
+
+	_, err := db.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		key := db.bellScheduleKey(b.ID)
+		switch err := tx.Get(key, &model.BellSchedule{}); {
+		case err == nil && create:
+			return c.ErrDBEntityAlreadyExists
+		case err == datastore.ErrNoSuchEntity && !create:
+			return c.ErrDBNoSuchEntity
+		case err != nil && err != datastore.ErrNoSuchEntity:
+			return err
+		}
+
+		previous := make([]model.BellSchedule, len(defaultKeys))
+		found, err := db.getMultiExistingTx(tx, defaultKeys, previous)
+		if err != nil {
+			return err
+		}
+		for i, k := range defaultKeys {
+			if !found[i] || !previous[i].Default {
+				continue
+			}
+			previous[i].Default = false
+			if _, err := tx.Put(k, &previous[i]); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Put(key, b)
+		return err
+	})
+	return err
+}
+
+// DeleteBellSchedule attempts to remove an existing bell schedule.
+// Confirmations referring to the bell schedule block the delete with c.ErrConflict.
+func (db *AppDatastore) DeleteBellSchedule(id string) error {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindConfirmation).Filter("BellScheduleID =", id).KeysOnly()
+	confirmationKeys, err := db.client.GetAll(ctx, query, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		key := db.bellScheduleKey(id)
+		switch err := tx.Get(key, &model.BellSchedule{}); err {
+		case nil:
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
+
+		confirmations := make([]model.Confirmation, len(confirmationKeys))
+		found, err := db.getMultiExistingTx(tx, confirmationKeys, confirmations)
+		if err != nil {
+			return err
+		}
+		used := 0
+		for i := range confirmationKeys {
+			if found[i] && confirmations[i].BellScheduleID == id {
+				used++
+			}
+		}
+		if used > 0 {
+			return fmt.Errorf("%w: bell schedule %v is still used by %d confirmations", c.ErrConflict, id, used)
+		}
+
+		return tx.Delete(key)
+	})
+	return err
+}
+
+// checkBellScheduleExists returns a validation error when the bell schedule referred to does not exist,
+// an empty id refers to the default bell schedule and is not checked
+func (db *AppDatastore) checkBellScheduleExists(tx *datastore.Transaction, id string) error {
+	if len(id) == 0 {
+		return nil
+	}
+	switch err := tx.Get(db.bellScheduleKey(id), &model.BellSchedule{}); err {
+	case nil:
+		return nil
+	case datastore.ErrNoSuchEntity:
+		return &c.ErrValidation{Violations: fmt.Errorf("bell schedule %v does not exist", id)}
+	default:
+		return err
+	}
+}
+
+// AddTerm attempts to add a Term to the datastore.
+func (db *AppDatastore) AddTerm(t *model.Term) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.termKey(t.ID)
+		err := tx.Get(key, &model.Term{})
+
+		switch err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			// no existing entity id, proceed with write
+			_, err = tx.Put(key, t)
+			return err
+		}
+		return err
+	})
+
+	return err
+}
+
+// GetAllTerm attempts to get a page of terms from datastore, ordered by start date.
+func (db *AppDatastore) GetAllTerm(page Page) ([]*model.Term, string, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindTerm).Order("StartDate")
+	list := make([]*model.Term, 0)
+	next, err := db.getPage(ctx, query, page, &list)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetTerm attempts to get single term from datastore by id.
+func (db *AppDatastore) GetTerm(id string) (*model.Term, error) {
+	key := db.termKey(id)
+	t := &model.Term{}
+	err := db.client.Get(context.Background(), key, t)
+	switch err {
+	case nil:
+		return t, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
+
+// UpdateTerm attempts to replace an existing term.
+func (db *AppDatastore) UpdateTerm(t *model.Term) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.termKey(t.ID)
+		err := tx.Get(key, &model.Term{})
+		switch err {
+		case nil:
+			_, err = tx.Put(key, t)
+			return err
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		}
+		return err
+	})
+	return err
+}
+
+// DeleteTerm attempts to remove an existing term.
+// Confirmations referring to the term block the delete with c.ErrConflict.
+func (db *AppDatastore) DeleteTerm(id string) error {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindConfirmation).Filter("TermID =", id).KeysOnly()
+	confirmationKeys, err := db.client.GetAll(ctx, query, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		key := db.termKey(id)
+		switch err := tx.Get(key, &model.Term{}); err {
+		case nil:
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
+
+		confirmations := make([]model.Confirmation, len(confirmationKeys))
+		found, err := db.getMultiExistingTx(tx, confirmationKeys, confirmations)
+		if err != nil {
+			return err
+		}
+		used := 0
+		for i := range confirmationKeys {
+			if found[i] && confirmations[i].TermID == id {
+				used++
+			}
+		}
+		if used > 0 {
+			return fmt.Errorf("%w: term %v is still used by %d confirmations", c.ErrConflict, id, used)
+		}
+
+		return tx.Delete(key)
+	})
+	return err
+}
+
+// checkTermExists returns a validation error when the term referred to does not exist, confirmations
+// made before terms existed have no term and are not checked
+func (db *AppDatastore) checkTermExists(tx *datastore.Transaction, id string) error {
+	if len(id) == 0 {
+		return nil
+	}
+	switch err := tx.Get(db.termKey(id), &model.Term{}); err {
+	case nil:
+		return nil
+	case datastore.ErrNoSuchEntity:
+		return &c.ErrValidation{Violations: fmt.Errorf("term %v does not exist", id)}
+	default:
+		return err
+	}
+}
+
+// AddHoliday attempts to add a Holiday to the datastore.
+func (db *AppDatastore) AddHoliday(h *model.Holiday) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.holidayKey(h.ID)
+		err := tx.Get(key, &model.Holiday{})
+
+		switch err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			// no existing entity id, proceed with write
+			_, err = tx.Put(key, h)
+			return err
+		}
+		return err
+	})
+
+	return err
+}
+
+// GetAllHoliday attempts to get a page of holidays from datastore, ordered by start date.
+func (db *AppDatastore) GetAllHoliday(page Page) ([]*model.Holiday, string, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindHoliday).Order("StartDate")
+	list := make([]*model.Holiday, 0)
+	next, err := db.getPage(ctx, query, page, &list)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetHoliday attempts to get single holiday from datastore by id.
+func (db *AppDatastore) GetHoliday(id string) (*model.Holiday, error) {
+	key := db.holidayKey(id)
+	h := &model.Holiday{}
+	err := db.client.Get(context.Background(), key, h)
+	switch err {
+	case nil:
+		return h, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
+
+// UpdateHoliday attempts to replace an existing holiday.
+func (db *AppDatastore) UpdateHoliday(h *model.Holiday) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.holidayKey(h.ID)
+		err := tx.Get(key, &model.Holiday{})
+		switch err {
+		case nil:
+			_, err = tx.Put(key, h)
+			return err
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		}
+		return err
+	})
+	return err
+}
+
+// DeleteHoliday attempts to remove an existing holiday.
+func (db *AppDatastore) DeleteHoliday(id string) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.holidayKey(id)
+		switch err := tx.Get(key, &model.Holiday{}); err {
+		case nil:
+			return tx.Delete(key)
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
+	})
+	return err
+}
+
+// AddRoom attempts to add a Room to the datastore.
+func (db *AppDatastore) AddRoom(r *model.Room) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.roomKey(r.ID)
+		err := tx.Get(key, &model.Room{})
+
+		switch err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			// no existing entity id, proceed with write
+			_, err = tx.Put(key, r)
+			return err
+		}
+		return err
+	})
+
+	return err
+}
+
+// GetAllRoom attempts to get a page of rooms from datastore.
+func (db *AppDatastore) GetAllRoom(page Page) ([]*model.Room, string, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindRoom)
+	list := make([]*model.Room, 0)
+	next, err := db.getPage(ctx, query, page, &list)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetRoom attempts to get single room from datastore by id.
+func (db *AppDatastore) GetRoom(id string) (*model.Room, error) {
+	key := db.roomKey(id)
+	r := &model.Room{}
+	err := db.client.Get(context.Background(), key, r)
+	switch err {
+	case nil:
+		return r, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
+
+// UpdateRoom attempts to replace an existing room.
+func (db *AppDatastore) UpdateRoom(r *model.Room) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.roomKey(r.ID)
+		err := tx.Get(key, &model.Room{})
+		switch err {
+		case nil:
+			_, err = tx.Put(key, r)
+			return err
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		}
+		return err
+	})
+	return err
+}
+
+// DeleteRoom attempts to remove an existing room.
+func (db *AppDatastore) DeleteRoom(id string) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.roomKey(id)
+		switch err := tx.Get(key, &model.Room{}); err {
+		case nil:
+			return tx.Delete(key)
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
+	})
+	return err
+}
+
+// AddStudent attempts to add a Student to the datastore.
+func (db *AppDatastore) AddStudent(s *model.Student) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.studentKey(s.ID)
+		err := tx.Get(key, &model.Student{})
+
+		switch err {
+		case nil:
+			return c.ErrDBEntityAlreadyExists
+		case datastore.ErrNoSuchEntity:
+			// no existing entity id, proceed with write
+			_, err = tx.Put(key, s)
+			return err
+		}
+		return err
+	})
+
+	return err
+}
+
+// GetAllStudent attempts to get a page of students from datastore, ordered by name.
+func (db *AppDatastore) GetAllStudent(page Page) ([]*model.Student, string, error) {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindStudent).Order("Name")
+	list := make([]*model.Student, 0)
+	next, err := db.getPage(ctx, query, page, &list)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetStudent attempts to get single student from datastore by id.
+func (db *AppDatastore) GetStudent(id string) (*model.Student, error) {
+	key := db.studentKey(id)
+	s := &model.Student{}
+	err := db.client.Get(context.Background(), key, s)
+	switch err {
+	case nil:
+		return s, nil
+	case datastore.ErrNoSuchEntity:
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return nil, err
+}
+
+// UpdateStudent attempts to replace an existing student.
+func (db *AppDatastore) UpdateStudent(s *model.Student) error {
+	_, err := db.client.RunInTransaction(context.Background(), func(tx *datastore.Transaction) error {
+		key := db.studentKey(s.ID)
+		err := tx.Get(key, &model.Student{})
+		switch err {
+		case nil:
+			_, err = tx.Put(key, s)
+			return err
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		}
+		return err
+	})
+	return err
+}
+
+// DeleteStudent attempts to remove an existing student.
+// Confirmation details referring to the student block the delete with c.ErrConflict.
+func (db *AppDatastore) DeleteStudent(id string) error {
+	ctx := context.Background()
+
+	query := datastore.NewQuery(db.KindConfirmationDetail).Filter("StudentID =", id).KeysOnly()
+	detailKeys, err := db.client.GetAll(ctx, query, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		key := db.studentKey(id)
+		switch err := tx.Get(key, &model.Student{}); err {
+		case nil:
+		case datastore.ErrNoSuchEntity:
+			return c.ErrDBNoSuchEntity
+		default:
+			return err
+		}
+
+		details := make([]model.ConfirmationDetail, len(detailKeys))
+		found, err := db.getMultiExistingTx(tx, detailKeys, details)
+		if err != nil {
+			return err
+		}
+		used := 0
+		for i := range detailKeys {
+			if found[i] && details[i].StudentID == id {
+				used++
+			}
+		}
+		if used > 0 {
+			return fmt.Errorf("%w: student %v is still referred to by %d confirmation details", c.ErrConflict, id, used)
+		}
+
+		return tx.Delete(key)
+	})
+	return err
+}
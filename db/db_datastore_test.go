@@ -13,7 +13,6 @@ import (
 
 	"cloud.google.com/go/datastore"
 	"github.com/google/go-cmp/cmp"
-	"google.golang.org/api/iterator"
 
 	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
@@ -23,13 +22,14 @@ import (
 // merchantDB is the struct under test
 var merchantDB *testDBEnv
 
-// TestMain starts datastore emulator and waits for it to initialize then run unit tests
+// TestMain starts datastore emulator and waits for it to initialize then run unit tests. Without
+// DATASTORE_EMULATOR_HOST the tests needing the datastore are skipped.
 
 func TestMain(m *testing.M) {
 
 	dse, ok := os.LookupEnv("DATASTORE_EMULATOR_HOST")
 	if !ok {
-		log.Fatal("this test depends on DATASTORE_EMULATOR_HOST being set")
+		os.Exit(m.Run())
 	}
 
 	if success := checkEmulatorHealth(dse); !success {
@@ -60,6 +60,7 @@ func TestMain(m *testing.M) {
 // expected
 func TestAddMerchant(t *testing.T) {
 
+	skipWithoutEmulator(t)
 	defer merchantDB.tearDown()
 
 	mdto := &dto.NewMerchant{
@@ -99,6 +100,7 @@ func TestAddMerchant(t *testing.T) {
 
 func TestAddDuplicateMerchant(t *testing.T) {
 
+	skipWithoutEmulator(t)
 	defer merchantDB.tearDown()
 
 	mdto := &dto.NewMerchant{
@@ -141,6 +143,7 @@ func TestAddDuplicateMerchant(t *testing.T) {
 
 func TestGetUnexistingMerchant(t *testing.T) {
 
+	skipWithoutEmulator(t)
 	defer merchantDB.tearDown()
 
 	_, err := merchantDB.GetMerchant("this_does_not_exist")
@@ -155,6 +158,7 @@ func TestGetUnexistingMerchant(t *testing.T) {
 // TestUpdateMerchant
 func TestUpdateMerchant(t *testing.T) {
 
+	skipWithoutEmulator(t)
 	defer merchantDB.tearDown()
 
 	mdto := &dto.NewMerchant{
@@ -209,6 +213,7 @@ func TestUpdateMerchant(t *testing.T) {
 
 func TestUpdateMerchantFailedValidation(t *testing.T) {
 
+	skipWithoutEmulator(t)
 	defer merchantDB.tearDown()
 
 	mdto := &dto.NewMerchant{
@@ -259,6 +264,7 @@ func TestUpdateMerchantFailedValidation(t *testing.T) {
 // TestUpsertPayOutConfig
 func TestUpsertPayOutConfig(t *testing.T) {
 
+	skipWithoutEmulator(t)
 	defer merchantDB.tearDown()
 
 	mdto := &dto.NewMerchant{
@@ -336,6 +342,102 @@ func merchantComparer(x, y model.Merchant) bool {
 
 // ------------ Merchant DB Test ----------------
 
+// addConfirmationWithSubjects adds a confirmation and subjects its details can refer to
+func addConfirmationWithSubjects(t *testing.T, id string, subjectIDs ...string) {
+	if err := merchantDB.AddMainSubject(model.NewMainSubject("music", "Music")); err != nil {
+		t.Fatalf("failed to add main subject: %v", err)
+	}
+	for _, subjectID := range subjectIDs {
+		if err := merchantDB.AddSubject(model.NewSubject(subjectID, subjectID, "music", 1, nil)); err != nil {
+			t.Fatalf("failed to add subject: %v", err)
+		}
+	}
+	if err := merchantDB.AddConfirmation(model.NewConfirmation(id, "term 1", "2022-01-01", "", "")); err != nil {
+		t.Fatalf("failed to add confirmation: %v", err)
+	}
+}
+
+func TestAddConfirmationDetailsInBatches(t *testing.T) {
+
+	skipWithoutEmulator(t)
+	defer merchantDB.tearDown()
+
+	addConfirmationWithSubjects(t, "c1", "piano")
+	list := make([]*model.ConfirmationDetail, 0, maxPutMulti+1)
+	for i := 0; i < maxPutMulti+1; i++ {
+		list = append(list, model.NewConfirmationDetail(fmt.Sprintf("d%d", i), "c1", []string{"piano"}, "", fmt.Sprintf("student%d", i), "P.4", 1, model.Monday))
+	}
+	if err := merchantDB.AddConfirmationDetails(list); err != nil {
+		t.Fatalf("failed to add confirmation details: %v", err)
+	}
+
+	count := 0
+	page := Page{Size: maxPutMulti}
+	for {
+		details, next, err := merchantDB.GetAllConfirmationDetail("c1", page)
+		if err != nil {
+			t.Fatalf("failed to get confirmation details: %v", err)
+		}
+		count += len(details)
+		if len(next) == 0 {
+			break
+		}
+		page.Token = next
+	}
+	if count != len(list) {
+		t.Errorf("expecting %d confirmation details, got %d", len(list), count)
+	}
+}
+
+func TestAddScheduleInBatches(t *testing.T) {
+
+	skipWithoutEmulator(t)
+	defer merchantDB.tearDown()
+
+	slots := make([]*model.ScheduleSlot, 0, maxPutMulti+1)
+	for i := 0; i < maxPutMulti+1; i++ {
+		slots = append(slots, &model.ScheduleSlot{ID: fmt.Sprintf("slot%d", i), ScheduleID: "s1", TeacherID: fmt.Sprintf("t%d", i%3),
+			SubjectID: "piano", Day: model.Monday, Period: 1, StudentNames: []string{"alice"}})
+	}
+	if err := merchantDB.AddSchedule(model.NewSchedule("s1", "c1", "", "", nil), slots); err != nil {
+		t.Fatalf("failed to add schedule: %v", err)
+	}
+	if _, err := merchantDB.GetSchedule("s1"); err != nil {
+		t.Errorf("failed to get schedule: %v", err)
+	}
+
+	list, err := merchantDB.GetAllScheduleSlot("s1", "", "")
+	if err != nil {
+		t.Fatalf("failed to get schedule slots: %v", err)
+	}
+	if len(list) != len(slots) {
+		t.Errorf("expecting %d schedule slots, got %d", len(slots), len(list))
+	}
+
+	if err := merchantDB.AddSchedule(model.NewSchedule("s1", "c1", "", "", nil), nil); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate schedule to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+}
+
+func TestPageByID(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e"}
+
+	start, end, next, err := pageByID(ids, Page{Size: 2})
+	if err != nil || start != 0 || end != 2 || len(next) == 0 {
+		t.Errorf("unexpected first page: %v %v %q %v", start, end, next, err)
+	}
+
+	start, end, next, err = pageByID(ids, Page{Size: 3, Token: next})
+	if err != nil || start != 2 || end != 5 || len(next) != 0 {
+		t.Errorf("unexpected last page: %v %v %q %v", start, end, next, err)
+	}
+
+	var errValidation *c.ErrValidation
+	if _, _, _, err := pageByID(ids, Page{Size: 2, Token: "!"}); !errors.As(err, &errValidation) {
+		t.Errorf("expecting invalid page token to fail with c.ErrValidation, got %v", err)
+	}
+}
+
 // testDBEnv is a wrapper for db.MerchantDB but it also has a direct access to *datastore.Client.
 // This allows us to run extra queries outside the scope of the app for testing purposes
 type testDBEnv struct {
@@ -348,32 +450,36 @@ func (tdb *testDBEnv) setUpMerchant(mdto *dto.NewMerchant) (*model.Merchant, err
 	return m, err
 }
 
-// tearDown deletes all Merchant entity from the datastore emulator
+// tearDown deletes all entities of the kinds under test from the datastore emulator
 func (tdb *testDBEnv) tearDown() error {
 
-	// query all
-	q := datastore.NewQuery(tdb.KindMerchant)
-
-	keys := make([]*datastore.Key, 0)
-	t := tdb.client.Run(context.Background(), q)
-	for {
-		var m model.Merchant
-		key, err := t.Next(&m)
-		if err == iterator.Done {
-			break
-		}
+	kinds := []string{tdb.KindMerchant, tdb.KindRole, tdb.KindKym, tdb.KindMainSubject, tdb.KindSubject, tdb.KindTeacher,
+		tdb.KindTeacherResponsibility, tdb.KindConfirmation, tdb.KindBellSchedule, tdb.KindTerm, tdb.KindHoliday, tdb.KindRoom,
+		tdb.KindStudent, tdb.KindConfirmationDetail, tdb.KindSchedule, tdb.KindScheduleSlot}
+	for _, kind := range kinds {
+		// query all
+		keys, err := tdb.client.GetAll(context.Background(), datastore.NewQuery(kind).KeysOnly(), nil)
 		if err != nil {
 			return err
 		}
-		keys = append(keys, key)
-	}
 
-	// then we delete all
-	return tdb.client.DeleteMulti(context.Background(), keys)
+		// then we delete all
+		if err := tdb.client.DeleteMulti(context.Background(), keys); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ------------ Datastore Emulator ----------------
 
+// skipWithoutEmulator skips the test when TestMain found no emulator to connect to
+func skipWithoutEmulator(t *testing.T) {
+	if merchantDB == nil {
+		t.Skip("this test depends on DATASTORE_EMULATOR_HOST being set")
+	}
+}
+
 // checkEmulatorHealth checks health of the emulator running needed for this test
 func checkEmulatorHealth(dshost string) bool {
 	for retries := 5; retries > 0; retries-- {
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+
+	"cloud.google.com/go/datastore"
+	"google.golang.org/api/iterator"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// DayPeriodMigration reports the result of MigrateDayPeriod
+type DayPeriodMigration struct {
+	// Rewritten counts the entities written back for each kind
+	Rewritten map[string]int
+	// Invalid lists the keys of entities holding a day or period that could not be converted,
+	// they are written back as well and have to be corrected by hand
+	Invalid []*datastore.Key
+}
+
+// MigrateDayPeriod rewrites the entities stored while days and periods were free text, so they hold a
+// model.Day and a model.Period. The conversion itself happens when the entities are loaded, see
+// model.ParseDay and model.ParsePeriod. Nothing is written when dryRun is set.
+func (db *AppDatastore) MigrateDayPeriod(dryRun bool) (*DayPeriodMigration, error) {
+	ctx := context.Background()
+	res := &DayPeriodMigration{Rewritten: make(map[string]int), Invalid: make([]*datastore.Key, 0)}
+
+	kinds := []struct {
+		kind    string
+		new     func() interface{}
+		invalid func(e interface{}) bool
+	}{
+		{
+			kind: db.KindConfirmationDetail,
+			new:  func() interface{} { return &model.ConfirmationDetail{} },
+			invalid: func(e interface{}) bool {
+				cd := e.(*model.ConfirmationDetail)
+				return !validDayPeriod(cd.Day, cd.Period)
+			},
+		},
+		{
+			kind: db.KindScheduleSlot,
+			new:  func() interface{} { return &model.ScheduleSlot{} },
+			invalid: func(e interface{}) bool {
+				s := e.(*model.ScheduleSlot)
+				return !validDayPeriod(s.Day, s.Period)
+			},
+		},
+		{
+			kind: db.KindSchedule,
+			new:  func() interface{} { return &model.Schedule{} },
+			invalid: func(e interface{}) bool {
+				for _, l := range e.(*model.Schedule).Unscheduled {
+					if !validDayPeriod(l.Day, l.Period) {
+						return true
+					}
+				}
+				return false
+			},
+		},
+		{
+			kind: db.KindTeacher,
+			new:  func() interface{} { return &model.Teacher{} },
+			invalid: func(e interface{}) bool {
+				for _, w := range e.(*model.Teacher).Availability {
+					if !validDayPeriod(w.Day, w.StartPeriod) {
+						return true
+					}
+				}
+				return false
+			},
+		},
+	}
+
+	for _, k := range kinds {
+		keys := make([]*datastore.Key, 0, maxPutMulti)
+		entities := make([]interface{}, 0, maxPutMulti)
+		flush := func() error {
+			if !dryRun && len(keys) > 0 {
+				if _, err := db.client.PutMulti(ctx, keys, entities); err != nil {
+					return err
+				}
+			}
+			res.Rewritten[k.kind] += len(keys)
+			keys, entities = keys[:0], entities[:0]
+			return nil
+		}
+
+		it := db.client.Run(ctx, datastore.NewQuery(k.kind))
+		for {
+			e := k.new()
+			key, err := it.Next(e)
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return res, err
+			}
+			if k.invalid(e) {
+				res.Invalid = append(res.Invalid, key)
+			}
+			keys = append(keys, key)
+			entities = append(entities, e)
+			if len(keys) == maxPutMulti {
+				if err := flush(); err != nil {
+					return res, err
+				}
+			}
+		}
+		if err := flush(); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func validDayPeriod(day model.Day, period model.Period) bool {
+	return day.Valid() && period >= 1
+}
@@ -0,0 +1,497 @@
+// Package dbtest is a conformance suite for the storage backends of the db package. Every backend runs
+// it from its own tests, so they keep behaving the same towards the services.
+package dbtest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// Run runs the suite against the backend. newStore is called by every test and has to return a store
+// without any entities, tests are not run in parallel so a backend may empty and return the same store.
+func Run(t *testing.T, newStore func(t *testing.T) db.Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s db.Store)
+	}{
+		{"Merchant", testMerchant},
+		{"Role", testRole},
+		{"Kym", testKym},
+		{"Teacher", testTeacher},
+		{"TeacherResponsibilityOrder", testTeacherResponsibilityOrder},
+		{"MainSubject", testMainSubject},
+		{"Subject", testSubject},
+		{"Confirmation", testConfirmation},
+		{"Term", testTerm},
+		{"Holiday", testHoliday},
+		{"Room", testRoom},
+		{"Student", testStudent},
+		{"Schedule", testSchedule},
+		{"MainSubjectReferences", testMainSubjectReferences},
+		{"TeacherResponsibility", testTeacherResponsibility},
+		{"ConfirmationDetail", testConfirmationDetail},
+		{"ConfirmationStatus", testConfirmationStatus},
+		{"ConfirmationDetails", testConfirmationDetails},
+		{"SubjectDetailReferences", testSubjectDetailReferences},
+		{"BellScheduleDefault", testBellScheduleDefault},
+		{"StudentPages", testStudentPages},
+		{"SubjectPages", testSubjectPages},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStore(t))
+		})
+	}
+}
+
+func testMainSubjectReferences(t *testing.T, s db.Store) {
+	var errValidation *c.ErrValidation
+	if err := s.AddSubject(model.NewSubject("piano", "Piano", "music", 1, nil)); !errors.As(err, &errValidation) {
+		t.Errorf("expecting subject with unknown main subject to fail with c.ErrValidation, got %v", err)
+	}
+	if err := s.AddTeacher(model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{}, "music", nil, nil)); !errors.As(err, &errValidation) {
+		t.Errorf("expecting teacher with unknown main subject to fail with c.ErrValidation, got %v", err)
+	}
+
+	addMainSubjects(t, s, "music")
+	addSubjects(t, s, "music", "piano")
+	addTeachers(t, s, model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{}, "music", nil, nil))
+	if err := s.AddTeacherResponsibility(model.NewTeacherResponsibility("t1-piano", "t1", "piano")); err != nil {
+		t.Fatalf("failed to add teacher responsibility: %v", err)
+	}
+
+	if err := s.DeleteSubject("piano", false); !errors.Is(err, c.ErrConflict) {
+		t.Errorf("expecting delete of assigned subject to fail with c.ErrConflict, got %v", err)
+	}
+	if err := s.DeleteMainSubject("music", false); !errors.Is(err, c.ErrConflict) {
+		t.Errorf("expecting delete of used main subject to fail with c.ErrConflict, got %v", err)
+	}
+	if err := s.DeleteMainSubject("music", true); err != nil {
+		t.Fatalf("expecting cascading delete to succeed: %v", err)
+	}
+	if _, err := s.GetSubject("piano"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting subject to be deleted with its main subject, got %v", err)
+	}
+	if _, err := s.GetTeacher("t1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting teacher to be deleted with its main subject, got %v", err)
+	}
+	list, _, err := s.GetAllTeacherResponsibility("", "piano", db.Page{Size: 10})
+	if err != nil || len(list) != 0 {
+		t.Errorf("expecting responsibilities to be deleted with their subject, got %v %v", list, err)
+	}
+	if err := s.DeleteMainSubject("music", false); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting delete of missing main subject to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testSubjectDetailReferences(t *testing.T, s db.Store) {
+	addMainSubjects(t, s, "music")
+	addSubjects(t, s, "music", "piano", "violin")
+	addConfirmations(t, s, model.NewConfirmation("c1", "term 1", "2022-01-01", "", ""))
+	if err := s.AddConfirmationDetail(model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "", "alice", "P.4", 1, model.Monday)); err != nil {
+		t.Fatalf("failed to add confirmation detail: %v", err)
+	}
+
+	if err := s.DeleteSubject("piano", true); !errors.Is(err, c.ErrConflict) {
+		t.Errorf("expecting cascading delete of a subject asked for by a detail to fail with c.ErrConflict, got %v", err)
+	}
+	if err := s.DeleteMainSubject("music", true); !errors.Is(err, c.ErrConflict) {
+		t.Errorf("expecting cascading delete of a main subject asked for by a detail to fail with c.ErrConflict, got %v", err)
+	}
+	if _, err := s.GetSubject("piano"); err != nil {
+		t.Errorf("expecting the subject to be kept, got %v", err)
+	}
+	if err := s.DeleteSubject("violin", false); err != nil {
+		t.Errorf("failed to delete a subject no detail asks for: %v", err)
+	}
+}
+
+func testTeacherResponsibility(t *testing.T, s db.Store) {
+	addMainSubjects(t, s, "music", "art")
+	addSubjects(t, s, "music", "piano", "violin")
+	addSubjects(t, s, "art", "drawing", "pottery")
+	addTeachers(t, s,
+		model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{}, "music", nil, nil),
+		model.NewTeacher("t2", "b", "b", "b", "0", model.TeacherCapacity{}, "art", nil, nil))
+
+	if err := s.AddTeacherResponsibility(model.NewTeacherResponsibility("t2-piano", "t2", "piano")); err != nil {
+		t.Fatalf("failed to add teacher responsibility: %v", err)
+	}
+	if err := s.AddTeacherResponsibility(model.NewTeacherResponsibility("t2-piano", "t2", "piano")); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate responsibility to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+	if err := s.AddTeacherResponsibility(model.NewTeacherResponsibility("t3-piano", "t3", "piano")); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting responsibility of missing teacher to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	var errValidation *c.ErrValidation
+	if err := s.AddTeacherResponsibility(model.NewTeacherResponsibility("t2-cello", "t2", "cello")); !errors.As(err, &errValidation) {
+		t.Errorf("expecting responsibility for missing subject to fail with c.ErrValidation, got %v", err)
+	}
+
+	teachers, _, err := s.GetAllTeacherBySubject("piano", db.Page{Size: 10})
+	if err != nil {
+		t.Fatalf("failed to get teachers by subject: %v", err)
+	}
+	checkIDs(t, "teachers of piano", teacherIDs(teachers), "t1", "t2")
+	subjects, _, err := s.GetAllSubjectByTeacher("t2", db.Page{Size: 10})
+	if err != nil {
+		t.Fatalf("failed to get subjects by teacher: %v", err)
+	}
+	checkIDs(t, "subjects of t2", subjectIDs(subjects), "drawing", "piano", "pottery")
+
+	if err := s.ReplaceTeacherResponsibility("t2", []string{"violin", "cello", "harp"}); !errors.As(err, &errValidation) ||
+		!strings.Contains(err.Error(), "cello") || !strings.Contains(err.Error(), "harp") {
+		t.Errorf("expecting replace with missing subjects to fail with c.ErrValidation naming them, got %v", err)
+	}
+	if err := s.ReplaceTeacherResponsibility("t3", []string{"violin"}); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting replace of missing teacher to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.ReplaceTeacherResponsibility("t2", []string{"violin", "violin"}); err != nil {
+		t.Fatalf("failed to replace teacher responsibility with a repeated subject: %v", err)
+	}
+	list, _, err := s.GetAllTeacherResponsibility("t2", "", db.Page{Size: 10})
+	if err != nil || len(list) != 1 || list[0].SubjectId != "violin" {
+		t.Errorf("expecting t2 to only teach violin, got %v %v", list, err)
+	}
+
+	if err := s.DeleteTeacher("t2"); err != nil {
+		t.Fatalf("failed to delete teacher: %v", err)
+	}
+	list, _, err = s.GetAllTeacherResponsibility("", "violin", db.Page{Size: 10})
+	if err != nil || len(list) != 0 {
+		t.Errorf("expecting responsibilities to be deleted with their teacher, got %v %v", list, err)
+	}
+	if err := s.DeleteTeacher("t2"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting delete of missing teacher to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testConfirmationDetail(t *testing.T, s db.Store) {
+	addMainSubjects(t, s, "music")
+	addSubjects(t, s, "music", "piano", "violin")
+	addConfirmations(t, s, model.NewConfirmation("c1", "term 1", "2022-01-01", "", ""))
+	if err := s.AddConfirmationDetail(model.NewConfirmationDetail("d1", "c1", []string{"piano", "violin"}, "s1", "alice", "P.4", 1, model.Monday)); err != nil {
+		t.Fatalf("failed to add confirmation detail: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		detail *model.ConfirmationDetail
+		expIDs []string
+	}{
+		{
+			name:   "unknownConfirmation",
+			detail: model.NewConfirmationDetail("d2", "c2", []string{"piano"}, "s2", "bob", "P.4", 1, model.Monday),
+			expIDs: []string{"c2"},
+		},
+		{
+			name:   "unknownSubjects",
+			detail: model.NewConfirmationDetail("d2", "c1", []string{"piano", "cello", "harp"}, "s2", "bob", "P.4", 1, model.Monday),
+			expIDs: []string{"cello", "harp"},
+		},
+		{
+			name:   "duplicateStudentDayPeriod",
+			detail: model.NewConfirmationDetail("d2", "c1", []string{"violin"}, "s1", "alice", "P.4", 1, model.Monday),
+			expIDs: []string{"s1", "d1"},
+		},
+	}
+	for _, tt := range tests {
+		err := s.AddConfirmationDetail(tt.detail)
+		var errValidation *c.ErrValidation
+		if !errors.As(err, &errValidation) {
+			t.Errorf("%v: expecting c.ErrValidation, got %v", tt.name, err)
+			continue
+		}
+		for _, id := range tt.expIDs {
+			if !strings.Contains(err.Error(), id) {
+				t.Errorf("%v: expecting %q to name %v", tt.name, err, id)
+			}
+		}
+	}
+
+	if err := s.AddConfirmationDetail(model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "s1", "alice", "P.4", 2, model.Monday)); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate confirmation detail to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+	// the same student on another period, and another student on the same period, are not duplicates
+	if err := s.AddConfirmationDetail(model.NewConfirmationDetail("d3", "c1", []string{"piano"}, "s1", "alice", "P.4", 2, model.Monday)); err != nil {
+		t.Errorf("failed to add confirmation detail on another period: %v", err)
+	}
+	if err := s.AddConfirmationDetail(model.NewConfirmationDetail("d4", "c1", []string{"piano"}, "s2", "bob", "P.4", 1, model.Monday)); err != nil {
+		t.Errorf("failed to add confirmation detail of another student: %v", err)
+	}
+
+	list, _, err := s.GetAllConfirmationDetail("c1", db.Page{Size: 10})
+	if err != nil {
+		t.Fatalf("failed to get confirmation details: %v", err)
+	}
+	ids := make([]string, 0, len(list))
+	for _, d := range list {
+		ids = append(ids, d.ID)
+	}
+	checkIDs(t, "confirmation details", ids, "d1", "d3", "d4")
+}
+
+func testConfirmationStatus(t *testing.T, s db.Store) {
+	addConfirmations(t, s, model.NewConfirmation("c1", "term 1", "2022-01-01", "", ""))
+	if _, err := s.UpdateConfirmationStatus("c1", model.ConfirmationLocked, "a@school.ac.th", time.Now()); !errors.Is(err, c.ErrConflict) {
+		t.Errorf("expecting a draft to be locked to fail with c.ErrConflict, got %v", err)
+	}
+
+	changedAt := time.Date(2022, 5, 1, 9, 30, 0, 0, time.UTC)
+	for _, status := range []model.ConfirmationStatus{model.ConfirmationSubmitted, model.ConfirmationLocked} {
+		if _, err := s.UpdateConfirmationStatus("c1", status, "a@school.ac.th", changedAt); err != nil {
+			t.Fatalf("failed to change confirmation status to %v: %v", status, err)
+		}
+	}
+	confirmation, err := s.GetConfirmation("c1")
+	if err != nil {
+		t.Fatalf("failed to get confirmation: %v", err)
+	}
+	if confirmation.Status != model.ConfirmationLocked || confirmation.StatusChangedBy != "a@school.ac.th" || !confirmation.StatusChangedAt.Equal(changedAt) {
+		t.Errorf("unexpected status change: %v by %v at %v", confirmation.Status, confirmation.StatusChangedBy, confirmation.StatusChangedAt)
+	}
+
+	err = s.AddConfirmationDetail(model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "", "alice", "P.4", 1, model.Monday))
+	if !errors.Is(err, c.ErrConflict) {
+		t.Errorf("expecting a detail of a locked confirmation to fail with c.ErrConflict, got %v", err)
+	}
+	if _, err := s.UpdateConfirmationStatus("c2", model.ConfirmationSubmitted, "", time.Now()); !errors.Is(err, c.ErrDBNoSuchEntity) {
+		t.Errorf("expecting status of missing confirmation to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testConfirmationDetails(t *testing.T, s db.Store) {
+	addMainSubjects(t, s, "music")
+	addSubjects(t, s, "music", "piano")
+	addConfirmations(t, s, model.NewConfirmation("c1", "term 1", "2022-01-01", "", ""))
+	if err := s.AddConfirmationDetail(model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "s1", "alice", "P.4", 1, model.Monday)); err != nil {
+		t.Fatalf("failed to add confirmation detail: %v", err)
+	}
+
+	var errValidation *c.ErrValidation
+	tests := []struct {
+		name    string
+		details []*model.ConfirmationDetail
+		check   func(err error) bool
+	}{
+		{
+			name:    "storedDuplicate",
+			details: []*model.ConfirmationDetail{model.NewConfirmationDetail("d2", "c1", []string{"piano"}, "s1", "alice", "P.4", 1, model.Monday)},
+			check:   func(err error) bool { return errors.As(err, &errValidation) },
+		},
+		{
+			name: "duplicateInList",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d2", "c1", []string{"piano"}, "s2", "bob", "P.4", 1, model.Monday),
+				model.NewConfirmationDetail("d3", "c1", []string{"piano"}, "s2", "bob", "P.4", 1, model.Monday),
+			},
+			check: func(err error) bool { return errors.As(err, &errValidation) },
+		},
+		{
+			name:    "unknownSubject",
+			details: []*model.ConfirmationDetail{model.NewConfirmationDetail("d2", "c1", []string{"cello"}, "s2", "bob", "P.4", 1, model.Monday)},
+			check:   func(err error) bool { return errors.As(err, &errValidation) },
+		},
+		{
+			name:    "existingID",
+			details: []*model.ConfirmationDetail{model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "s2", "bob", "P.4", 2, model.Monday)},
+			check:   func(err error) bool { return err == c.ErrDBEntityAlreadyExists },
+		},
+	}
+	for _, tt := range tests {
+		if err := s.AddConfirmationDetails(tt.details); !tt.check(err) {
+			t.Errorf("%v: unexpected error %v", tt.name, err)
+		}
+	}
+	if d, _, err := s.GetAllConfirmationDetail("c1", db.Page{Size: 10}); err != nil || len(d) != 1 || d[0].Period != 1 {
+		t.Errorf("expecting the rejected details to not overwrite or add to the stored one, got %v %v", d, err)
+	}
+
+	for _, status := range []model.ConfirmationStatus{model.ConfirmationSubmitted, model.ConfirmationLocked} {
+		if _, err := s.UpdateConfirmationStatus("c1", status, "a@school.ac.th", time.Now()); err != nil {
+			t.Fatalf("failed to change confirmation status to %v: %v", status, err)
+		}
+	}
+	err := s.AddConfirmationDetails([]*model.ConfirmationDetail{model.NewConfirmationDetail("d4", "c1", []string{"piano"}, "s3", "carol", "P.4", 1, model.Monday)})
+	if !errors.Is(err, c.ErrConflict) {
+		t.Errorf("expecting details of a locked confirmation to fail with c.ErrConflict, got %v", err)
+	}
+}
+
+func testBellScheduleDefault(t *testing.T, s db.Store) {
+	periods := []model.BellPeriod{{Period: 1, Name: "Period 1", Start: "08:30", End: "09:20"}}
+	if _, err := s.GetDefaultBellSchedule(); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing default bell schedule to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.AddBellSchedule(model.NewBellSchedule("regular", "Regular day", true, periods)); err != nil {
+		t.Fatalf("failed to add bell schedule: %v", err)
+	}
+	if err := s.AddBellSchedule(model.NewBellSchedule("exam", "Exam day", true, periods)); err != nil {
+		t.Fatalf("failed to add bell schedule: %v", err)
+	}
+
+	b, err := s.GetDefaultBellSchedule()
+	if err != nil || b.ID != "exam" {
+		t.Errorf("expecting the last default bell schedule to be the default, got %v %v", b, err)
+	}
+	if b, err := s.GetBellSchedule("regular"); err != nil || b.Default {
+		t.Errorf("expecting the previous default to be unset, got %v %v", b, err)
+	}
+
+	var errValidation *c.ErrValidation
+	if err := s.AddConfirmation(model.NewConfirmation("c0", "term 1", "2022-01-01", "unknown", "")); !errors.As(err, &errValidation) {
+		t.Errorf("expecting confirmation with unknown bell schedule to fail with c.ErrValidation, got %v", err)
+	}
+	addConfirmations(t, s, model.NewConfirmation("c1", "term 1", "2022-01-01", "exam", ""))
+	if err := s.DeleteBellSchedule("exam"); !errors.Is(err, c.ErrConflict) {
+		t.Errorf("expecting delete of used bell schedule to fail with c.ErrConflict, got %v", err)
+	}
+	if err := s.DeleteBellSchedule("regular"); err != nil {
+		t.Errorf("expecting delete of unused bell schedule to succeed: %v", err)
+	}
+	if err := s.DeleteBellSchedule("regular"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting delete of missing bell schedule to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testStudentPages(t *testing.T, s db.Store) {
+	for _, student := range []*model.Student{
+		model.NewStudent("s1", "carol", "", "", "", "", ""),
+		model.NewStudent("s2", "alice", "", "", "", "", ""),
+		model.NewStudent("s3", "bob", "", "", "", "", ""),
+		model.NewStudent("s4", "dave", "", "", "", "", ""),
+		model.NewStudent("s5", "erin", "", "", "", "", ""),
+	} {
+		if err := s.AddStudent(student); err != nil {
+			t.Fatalf("failed to add student: %v", err)
+		}
+	}
+
+	names := make([]string, 0)
+	page := db.Page{Size: 2}
+	for pages := 1; ; pages++ {
+		list, next, err := s.GetAllStudent(page)
+		if err != nil {
+			t.Fatalf("failed to get page %v: %v", pages, err)
+		}
+		for _, student := range list {
+			names = append(names, student.Name)
+		}
+		if len(next) == 0 {
+			if pages != 3 {
+				t.Errorf("expecting 3 pages, got %v", pages)
+			}
+			break
+		}
+		page.Token = next
+	}
+	checkIDs(t, "student names", names, "alice", "bob", "carol", "dave", "erin")
+
+	var errValidation *c.ErrValidation
+	if _, _, err := s.GetAllStudent(db.Page{Size: 2, Token: "not a token"}); !errors.As(err, &errValidation) {
+		t.Errorf("expecting invalid page token to fail with c.ErrValidation, got %v", err)
+	}
+}
+
+func testSubjectPages(t *testing.T, s db.Store) {
+	addMainSubjects(t, s, "music")
+	addSubjects(t, s, "music", "a", "b", "c", "d", "e")
+
+	ids := make([]string, 0)
+	page := db.Page{Size: 2}
+	for pages := 1; ; pages++ {
+		list, next, err := s.GetAllSubject(db.SubjectQuery{}, page)
+		if err != nil {
+			t.Fatalf("failed to get page %v: %v", pages, err)
+		}
+		ids = append(ids, subjectIDs(list)...)
+		if len(next) == 0 {
+			if pages != 3 {
+				t.Errorf("expecting 3 pages, got %v", pages)
+			}
+			break
+		}
+		page.Token = next
+	}
+	checkIDs(t, "paged subjects", ids, "a", "b", "c", "d", "e")
+
+	var errValidation *c.ErrValidation
+	if _, _, err := s.GetAllSubject(db.SubjectQuery{}, db.Page{Size: 2, Token: "not a cursor"}); !errors.As(err, &errValidation) {
+		t.Errorf("expecting invalid page token to fail with c.ErrValidation, got %v", err)
+	}
+}
+
+// ----------- Helper functions ----------------
+
+func addMainSubjects(t *testing.T, s db.Store, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := s.AddMainSubject(model.NewMainSubject(id, id)); err != nil {
+			t.Fatalf("failed to add main subject %v: %v", id, err)
+		}
+	}
+}
+
+func addSubjects(t *testing.T, s db.Store, mainSubjectID string, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := s.AddSubject(model.NewSubject(id, id, mainSubjectID, 1, nil)); err != nil {
+			t.Fatalf("failed to add subject %v: %v", id, err)
+		}
+	}
+}
+
+func addTeachers(t *testing.T, s db.Store, teachers ...*model.Teacher) {
+	t.Helper()
+	for _, teacher := range teachers {
+		if err := s.AddTeacher(teacher); err != nil {
+			t.Fatalf("failed to add teacher %v: %v", teacher.ID, err)
+		}
+	}
+}
+
+func addConfirmations(t *testing.T, s db.Store, confirmations ...*model.Confirmation) {
+	t.Helper()
+	for _, m := range confirmations {
+		if err := s.AddConfirmation(m); err != nil {
+			t.Fatalf("failed to add confirmation %v: %v", m.ID, err)
+		}
+	}
+}
+
+func teacherIDs(list []*model.Teacher) []string {
+	ids := make([]string, 0, len(list))
+	for _, teacher := range list {
+		ids = append(ids, teacher.ID)
+	}
+	return ids
+}
+
+func subjectIDs(list []*model.Subject) []string {
+	ids := make([]string, 0, len(list))
+	for _, subject := range list {
+		ids = append(ids, subject.ID)
+	}
+	return ids
+}
+
+// checkIDs fails the test unless got holds the wanted ids in that order
+func checkIDs(t *testing.T, what string, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("unexpected %v: got %v want %v", what, got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("unexpected %v: got %v want %v", what, got, want)
+			return
+		}
+	}
+}
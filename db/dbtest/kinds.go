@@ -0,0 +1,697 @@
+package dbtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// The tests below check every kind is added, read, updated and deleted the same way by each backend:
+// adding a taken id fails with c.ErrDBEntityAlreadyExists, and reading, updating or deleting an id
+// which does not exist fails with c.ErrDBNoSuchEntity. Lists are checked for their filters and order.
+
+// created is the timestamp of the entities whose order depends on it, backends store timestamps with
+// different precision so it has none below a second
+var created = time.Date(2022, 5, 16, 9, 0, 0, 0, time.UTC)
+
+func testMerchant(t *testing.T, s db.Store) {
+	if _, err := s.GetMerchant("org123"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing merchant to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	address := model.Address{City: "bkk", Country: "thailand", Zipcode: "12345"}
+	m := model.NewMerchant(address, "0123456789", "test@example.com", "test-organisation", "org123", "logo", c.CurrencyCodeTHB, []string{c.PaymentMethodCreditCard})
+	if err := s.UpdateMerchant(m); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting update of missing merchant to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.AddMerchant(m); err != nil {
+		t.Fatalf("failed to add merchant: %v", err)
+	}
+	if err := s.AddMerchant(m); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate merchant to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	poc := &model.PayOutConfig{
+		BankAccount:  model.BankAccount{AccountName: "testaccount", AccountNumber: "12345", BankName: "greenbank"},
+		CurrencyCode: c.CurrencyCodeTHB,
+		Schedule:     model.PayOutConfigScheduleWeekly,
+	}
+	if err := s.UpsertPayOutConfig("org456", poc); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting pay out config of missing merchant to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.UpsertPayOutConfig("org123", poc); err != nil {
+		t.Fatalf("failed to upsert pay out config: %v", err)
+	}
+
+	// the pay out config is kept by updates of the merchant
+	m.Email = "changed@example.com"
+	if err := s.UpdateMerchant(m); err != nil {
+		t.Fatalf("failed to update merchant: %v", err)
+	}
+	got, err := s.GetMerchant("org123")
+	if err != nil {
+		t.Fatalf("failed to get merchant: %v", err)
+	}
+	if got.Email != "changed@example.com" || got.FullName != "test-organisation" || got.Address != address {
+		t.Errorf("unexpected merchant %+v", got)
+	}
+	if got.PayOutConfig == nil || *got.PayOutConfig != *poc {
+		t.Errorf("unexpected pay out config %+v", got.PayOutConfig)
+	}
+
+	m.FullName = "renamed"
+	var errValidation *c.ErrValidation
+	if err := s.UpdateMerchant(m); !errors.As(err, &errValidation) {
+		t.Errorf("expecting update of the merchant's name to fail with c.ErrValidation, got %v", err)
+	}
+}
+
+func testRole(t *testing.T, s db.Store) {
+	if _, err := s.GetRole("org123", "user1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing role to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	role := &model.Role{UserID: "user1", UserEmail: "user1@example.com", OrganisationID: "org123",
+		RoleTypes: []model.RoleType{model.RoleTypeOwner, model.RoleTypeEditor}}
+	if err := s.AddRole(role); err != nil {
+		t.Fatalf("failed to add role: %v", err)
+	}
+	if err := s.AddRole(role); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate role to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	got, err := s.GetRole("org123", "user1")
+	if err != nil {
+		t.Fatalf("failed to get role: %v", err)
+	}
+	if got.UserEmail != "user1@example.com" || len(got.RoleTypes) != 2 || got.RoleTypes[0] != model.RoleTypeOwner || got.RoleTypes[1] != model.RoleTypeEditor {
+		t.Errorf("unexpected role %+v", got)
+	}
+	if _, err := s.GetRole("org123", "user2"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting role of another user to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if _, err := s.GetRole("org456", "user1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting role in another organisation to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testKym(t *testing.T, s db.Store) {
+	if _, err := s.GetKym("k1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing kym to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	kyms := []struct {
+		id      string
+		status  string
+		created time.Time
+	}{
+		{"k1", c.KymStatusPending, created},
+		{"k2", c.KymStatusApproved, created.Add(time.Hour)},
+		{"k3", c.KymStatusPending, created.Add(2 * time.Hour)},
+	}
+	for _, k := range kyms {
+		kym := &model.Kym{ID: k.id, OrganisationID: "org123", Status: k.status, DatetimeCreated: k.created}
+		if err := s.AddKym(kym); err != nil {
+			t.Fatalf("failed to add kym %v: %v", k.id, err)
+		}
+	}
+	if err := s.AddKym(&model.Kym{ID: "k1", Status: c.KymStatusPending, DatetimeCreated: created}); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate kym to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	tests := []struct {
+		status string
+		want   []string
+	}{
+		{"", []string{"k3", "k2", "k1"}},
+		{c.KymStatusPending, []string{"k3", "k1"}},
+		{c.KymStatusRejected, []string{}},
+	}
+	for _, tt := range tests {
+		list, _, err := s.GetAllKym(tt.status, db.Page{Size: 10})
+		if err != nil {
+			t.Fatalf("failed to get kym with status %q: %v", tt.status, err)
+		}
+		ids := make([]string, 0, len(list))
+		for _, kym := range list {
+			ids = append(ids, kym.ID)
+		}
+		checkIDs(t, "kym with status "+tt.status, ids, tt.want...)
+	}
+	var errValidation *c.ErrValidation
+	if _, _, err := s.GetAllKym("unknown", db.Page{Size: 10}); !errors.As(err, &errValidation) {
+		t.Errorf("expecting kym with unknown status to fail with c.ErrValidation, got %v", err)
+	}
+
+	if err := s.UpdateKymStatus(&model.Kym{ID: "k4"}, c.KymStatusApproved, ""); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting status of missing kym to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	kym, err := s.GetKym("k1")
+	if err != nil {
+		t.Fatalf("failed to get kym: %v", err)
+	}
+	if err := s.UpdateKymStatus(kym, c.KymStatusRejected, "blurry documents"); err != nil {
+		t.Fatalf("failed to update kym status: %v", err)
+	}
+	if kym, err := s.GetKym("k1"); err != nil || kym.Status != c.KymStatusRejected || kym.Notes != "blurry documents" {
+		t.Errorf("unexpected kym after status update %+v %v", kym, err)
+	}
+}
+
+func testTeacher(t *testing.T, s db.Store) {
+	addMainSubjects(t, s, "music", "art")
+	if _, err := s.GetTeacher("t1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing teacher to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	addTeachers(t, s,
+		model.NewTeacher("t1", "Somchai", "Chai", "Dee", "0", model.TeacherCapacity{}, "music", nil, nil),
+		model.NewTeacher("t2", "Anong", "Nong", "Chaiyo", "0", model.TeacherCapacity{}, "music", nil, nil),
+		model.NewTeacher("t3", "Chalerm", "Lerm", "Suk", "0", model.TeacherCapacity{}, "art", nil, nil))
+	if err := s.AddTeacher(model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{}, "art", nil, nil)); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate teacher to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	tests := []struct {
+		query db.TeacherQuery
+		want  []string
+	}{
+		{db.TeacherQuery{}, []string{"t1", "t2", "t3"}},
+		{db.TeacherQuery{MainSubjectID: "music"}, []string{"t1", "t2"}},
+		{db.TeacherQuery{Q: " CHA"}, []string{"t1", "t2", "t3"}},
+		{db.TeacherQuery{Q: "nong"}, []string{"t2"}},
+		{db.TeacherQuery{Q: "cha", MainSubjectID: "art"}, []string{"t3"}},
+		{db.TeacherQuery{Q: "xyz"}, []string{}},
+		{db.TeacherQuery{Sort: db.TeacherSortFirstName}, []string{"t2", "t3", "t1"}},
+		{db.TeacherQuery{Sort: db.TeacherSortNickName}, []string{"t1", "t3", "t2"}},
+		{db.TeacherQuery{Sort: db.TeacherSortLastName}, []string{"t2", "t1", "t3"}},
+	}
+	for _, tt := range tests {
+		list, _, err := s.GetAllTeacher(tt.query, db.Page{Size: 10})
+		if err != nil {
+			t.Fatalf("%+v: failed to get teachers: %v", tt.query, err)
+		}
+		checkIDs(t, "teachers", teacherIDs(list), tt.want...)
+	}
+
+	teacher := model.NewTeacher("t4", "a", "a", "a", "0", model.TeacherCapacity{}, "music", nil, nil)
+	if err := s.UpdateTeacher(teacher); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting update of missing teacher to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	teacher.ID = "t3"
+	teacher.MainSubjectID = "drama"
+	var errValidation *c.ErrValidation
+	if err := s.UpdateTeacher(teacher); !errors.As(err, &errValidation) {
+		t.Errorf("expecting teacher with unknown main subject to fail with c.ErrValidation, got %v", err)
+	}
+	teacher.MainSubjectID = "music"
+	if err := s.UpdateTeacher(teacher); err != nil {
+		t.Fatalf("failed to update teacher: %v", err)
+	}
+	if got, err := s.GetTeacher("t3"); err != nil || got.FirstName != "a" || got.MainSubjectID != "music" {
+		t.Errorf("unexpected teacher after update %+v %v", got, err)
+	}
+
+	availability := []model.AvailabilityWindow{{Day: model.Monday, StartPeriod: 1, EndPeriod: 4}}
+	exceptions := []model.AvailabilityException{{StartDate: created, EndDate: created.AddDate(0, 0, 1), Reason: "seminar"}}
+	if err := s.UpdateTeacherAvailability("t4", availability, exceptions); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting availability of missing teacher to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.UpdateTeacherAvailability("t1", availability, exceptions); err != nil {
+		t.Fatalf("failed to update teacher availability: %v", err)
+	}
+	got, err := s.GetTeacher("t1")
+	if err != nil {
+		t.Fatalf("failed to get teacher: %v", err)
+	}
+	if len(got.Availability) != 1 || got.Availability[0] != availability[0] ||
+		len(got.AvailabilityExceptions) != 1 || got.AvailabilityExceptions[0].Reason != "seminar" ||
+		!got.AvailabilityExceptions[0].StartDate.Equal(created) || got.FirstName != "Somchai" {
+		t.Errorf("unexpected teacher after availability update %+v", got)
+	}
+}
+
+func testTeacherResponsibilityOrder(t *testing.T, s db.Store) {
+	addMainSubjects(t, s, "music")
+	addSubjects(t, s, "music", "piano", "violin")
+	addTeachers(t, s,
+		model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{}, "music", nil, nil),
+		model.NewTeacher("t2", "b", "b", "b", "0", model.TeacherCapacity{}, "music", nil, nil))
+	for _, tr := range []*model.TeacherResponsibility{
+		model.NewTeacherResponsibility("t2-violin", "t2", "violin"),
+		model.NewTeacherResponsibility("t1-violin", "t1", "violin"),
+		model.NewTeacherResponsibility("t2-piano", "t2", "piano"),
+	} {
+		if err := s.AddTeacherResponsibility(tr); err != nil {
+			t.Fatalf("failed to add teacher responsibility: %v", err)
+		}
+	}
+
+	tests := []struct {
+		teacherID string
+		subjectID string
+		want      []string
+	}{
+		{"", "", []string{"t1/violin", "t2/piano", "t2/violin"}},
+		{"t2", "", []string{"t2/piano", "t2/violin"}},
+		{"", "violin", []string{"t1/violin", "t2/violin"}},
+		{"t1", "piano", []string{}},
+	}
+	for _, tt := range tests {
+		list, _, err := s.GetAllTeacherResponsibility(tt.teacherID, tt.subjectID, db.Page{Size: 10})
+		if err != nil {
+			t.Fatalf("failed to get teacher responsibilities: %v", err)
+		}
+		got := make([]string, 0, len(list))
+		for _, tr := range list {
+			got = append(got, tr.TeacherId+"/"+tr.SubjectId)
+		}
+		checkIDs(t, "teacher responsibilities of "+tt.teacherID+"/"+tt.subjectID, got, tt.want...)
+	}
+
+	if err := s.DeleteTeacherResponsibility("t1", "violin"); err != nil {
+		t.Fatalf("failed to delete teacher responsibility: %v", err)
+	}
+	if err := s.DeleteTeacherResponsibility("t1", "violin"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting delete of missing teacher responsibility to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+
+	if _, _, err := s.GetAllTeacherBySubject("cello", db.Page{Size: 10}); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting teachers of a missing subject to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if _, _, err := s.GetAllSubjectByTeacher("t3", db.Page{Size: 10}); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting subjects of a missing teacher to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testMainSubject(t *testing.T, s db.Store) {
+	if _, err := s.GetMainSubject("music"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing main subject to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.UpdateMainSubject(model.NewMainSubject("music", "Music")); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting update of missing main subject to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	addMainSubjects(t, s, "music", "art", "science")
+	if err := s.AddMainSubject(model.NewMainSubject("music", "Music")); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate main subject to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	if err := s.UpdateMainSubject(model.NewMainSubject("music", "Music and Singing")); err != nil {
+		t.Fatalf("failed to update main subject: %v", err)
+	}
+	if m, err := s.GetMainSubject("music"); err != nil || m.MainSubjectName != "Music and Singing" {
+		t.Errorf("unexpected main subject after update %+v %v", m, err)
+	}
+
+	ids := make([]string, 0)
+	page := db.Page{Size: 2}
+	for {
+		list, next, err := s.GetAllMainSubject(page)
+		if err != nil {
+			t.Fatalf("failed to get main subjects: %v", err)
+		}
+		for _, m := range list {
+			ids = append(ids, m.ID)
+		}
+		if len(next) == 0 {
+			break
+		}
+		page.Token = next
+	}
+	checkIDs(t, "main subjects", ids, "art", "music", "science")
+
+	if err := s.DeleteMainSubject("art", false); err != nil {
+		t.Fatalf("failed to delete main subject: %v", err)
+	}
+	if _, err := s.GetMainSubject("art"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting deleted main subject to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testSubject(t *testing.T, s db.Store) {
+	addMainSubjects(t, s, "music", "art")
+	if _, err := s.GetSubject("piano"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing subject to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	for _, subject := range []*model.Subject{
+		model.NewSubject("piano", "Piano", "music", 4, nil),
+		model.NewSubject("violin", "Violin", "music", 2, nil),
+		model.NewSubject("drawing", "Drawing", "art", 6, nil),
+		model.NewSubject("choir", "Choir", "music", 8, nil),
+	} {
+		if err := s.AddSubject(subject); err != nil {
+			t.Fatalf("failed to add subject %v: %v", subject.ID, err)
+		}
+	}
+	if err := s.AddSubject(model.NewSubject("piano", "Piano", "music", 1, nil)); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate subject to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	tests := []struct {
+		query db.SubjectQuery
+		want  []string
+	}{
+		{db.SubjectQuery{}, []string{"choir", "drawing", "piano", "violin"}},
+		{db.SubjectQuery{MainSubjectID: "music"}, []string{"choir", "piano", "violin"}},
+		{db.SubjectQuery{MinStudentsGte: 4}, []string{"piano", "drawing", "choir"}},
+		{db.SubjectQuery{MainSubjectID: "music", MinStudentsGte: 3}, []string{"piano", "choir"}},
+		{db.SubjectQuery{MainSubjectID: "science"}, []string{}},
+	}
+	for _, tt := range tests {
+		list, _, err := s.GetAllSubject(tt.query, db.Page{Size: 10})
+		if err != nil {
+			t.Fatalf("%+v: failed to get subjects: %v", tt.query, err)
+		}
+		checkIDs(t, "subjects", subjectIDs(list), tt.want...)
+	}
+
+	if err := s.UpdateSubject(model.NewSubject("cello", "Cello", "music", 1, nil)); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting update of missing subject to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	var errValidation *c.ErrValidation
+	if err := s.UpdateSubject(model.NewSubject("piano", "Piano", "drama", 1, nil)); !errors.As(err, &errValidation) {
+		t.Errorf("expecting subject with unknown main subject to fail with c.ErrValidation, got %v", err)
+	}
+	if err := s.UpdateSubject(model.NewSubject("piano", "Grand piano", "music", 5, nil)); err != nil {
+		t.Fatalf("failed to update subject: %v", err)
+	}
+	if got, err := s.GetSubject("piano"); err != nil || got.SubjectName != "Grand piano" || got.MinOfStudent != 5 {
+		t.Errorf("unexpected subject after update %+v %v", got, err)
+	}
+
+	if err := s.DeleteSubject("violin", false); err != nil {
+		t.Fatalf("failed to delete subject: %v", err)
+	}
+	if err := s.DeleteSubject("violin", false); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting delete of missing subject to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testConfirmation(t *testing.T, s db.Store) {
+	if _, err := s.GetConfirmation("c1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing confirmation to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.AddTerm(model.NewTerm("term1", "Term 1/2022", created, created.AddDate(0, 4, 0))); err != nil {
+		t.Fatalf("failed to add term: %v", err)
+	}
+	addConfirmations(t, s,
+		model.NewConfirmation("c2", "term 2", "2022-05-01", "", "term1"),
+		model.NewConfirmation("c1", "term 1", "2022-01-01", "", ""),
+		model.NewConfirmation("c3", "term 3", "2022-09-01", "", ""))
+	if err := s.AddConfirmation(model.NewConfirmation("c1", "term 1", "2022-01-01", "", "")); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate confirmation to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+	var errValidation *c.ErrValidation
+	if err := s.AddConfirmation(model.NewConfirmation("c4", "term 4", "2022-01-01", "", "term9")); !errors.As(err, &errValidation) {
+		t.Errorf("expecting confirmation with unknown term to fail with c.ErrValidation, got %v", err)
+	}
+
+	got, err := s.GetConfirmation("c2")
+	if err != nil || got.ConfirmationName != "term 2" || got.TermID != "term1" || got.Status != model.ConfirmationDraft {
+		t.Errorf("unexpected confirmation %+v %v", got, err)
+	}
+
+	ids := make([]string, 0)
+	page := db.Page{Size: 2}
+	for {
+		list, next, err := s.GetAllConfirmation(page)
+		if err != nil {
+			t.Fatalf("failed to get confirmations: %v", err)
+		}
+		for _, m := range list {
+			ids = append(ids, m.ID)
+		}
+		if len(next) == 0 {
+			break
+		}
+		page.Token = next
+	}
+	checkIDs(t, "confirmations", ids, "c1", "c2", "c3")
+
+	addMainSubjects(t, s, "music")
+	addSubjects(t, s, "music", "piano")
+	list := make([]*model.ConfirmationDetail, 0, 5)
+	for _, id := range []string{"d3", "d1", "d5", "d2", "d4"} {
+		list = append(list, model.NewConfirmationDetail(id, "c3", []string{"piano"}, "", id, "P.4", 1, model.Monday))
+	}
+	if err := s.AddConfirmationDetails(list); err != nil {
+		t.Fatalf("failed to add confirmation details: %v", err)
+	}
+	ids = make([]string, 0)
+	page = db.Page{Size: 2}
+	for {
+		details, next, err := s.GetAllConfirmationDetail("c3", page)
+		if err != nil {
+			t.Fatalf("failed to get confirmation details: %v", err)
+		}
+		for _, d := range details {
+			ids = append(ids, d.ID)
+		}
+		if len(next) == 0 {
+			break
+		}
+		page.Token = next
+	}
+	checkIDs(t, "confirmation details", ids, "d1", "d2", "d3", "d4", "d5")
+	if details, _, err := s.GetAllConfirmationDetail("c1", db.Page{Size: 10}); err != nil || len(details) != 0 {
+		t.Errorf("expecting no details of another confirmation, got %v %v", details, err)
+	}
+}
+
+func testTerm(t *testing.T, s db.Store) {
+	if _, err := s.GetTerm("t1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing term to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.UpdateTerm(model.NewTerm("t1", "Term 1/2022", created, created)); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting update of missing term to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	for _, term := range []*model.Term{
+		model.NewTerm("t1", "Term 2/2022", created.AddDate(0, 5, 0), created.AddDate(0, 9, 0)),
+		model.NewTerm("t2", "Term 1/2022", created, created.AddDate(0, 4, 0)),
+		model.NewTerm("t3", "Term 1/2023", created.AddDate(1, 0, 0), created.AddDate(1, 4, 0)),
+	} {
+		if err := s.AddTerm(term); err != nil {
+			t.Fatalf("failed to add term %v: %v", term.ID, err)
+		}
+	}
+	if err := s.AddTerm(model.NewTerm("t1", "Term 2/2022", created, created)); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate term to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	list, _, err := s.GetAllTerm(db.Page{Size: 10})
+	if err != nil {
+		t.Fatalf("failed to get terms: %v", err)
+	}
+	ids := make([]string, 0, len(list))
+	for _, term := range list {
+		ids = append(ids, term.ID)
+	}
+	checkIDs(t, "terms", ids, "t2", "t1", "t3")
+
+	if err := s.UpdateTerm(model.NewTerm("t3", "Term 1/2023", created.AddDate(-1, 0, 0), created)); err != nil {
+		t.Fatalf("failed to update term: %v", err)
+	}
+	if got, err := s.GetTerm("t3"); err != nil || !got.StartDate.Equal(model.DateOf(created.AddDate(-1, 0, 0))) {
+		t.Errorf("unexpected term after update %+v %v", got, err)
+	}
+	if list, _, err := s.GetAllTerm(db.Page{Size: 1}); err != nil || len(list) != 1 || list[0].ID != "t3" {
+		t.Errorf("expecting the updated term to be listed first, got %v %v", list, err)
+	}
+
+	if err := s.DeleteTerm("t3"); err != nil {
+		t.Fatalf("failed to delete term: %v", err)
+	}
+	if err := s.DeleteTerm("t3"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting delete of missing term to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testHoliday(t *testing.T, s db.Store) {
+	if _, err := s.GetHoliday("h1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing holiday to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.UpdateHoliday(model.NewHoliday("h1", "Visakha Bucha", created, created)); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting update of missing holiday to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	for _, h := range []*model.Holiday{
+		model.NewHoliday("h1", "Queen's Birthday", created.AddDate(0, 3, 0), created.AddDate(0, 3, 0)),
+		model.NewHoliday("h2", "Visakha Bucha", created, created),
+		model.NewHoliday("h3", "Sports day", created.AddDate(0, 1, 0), created.AddDate(0, 1, 1)),
+	} {
+		if err := s.AddHoliday(h); err != nil {
+			t.Fatalf("failed to add holiday %v: %v", h.ID, err)
+		}
+	}
+	if err := s.AddHoliday(model.NewHoliday("h1", "Queen's Birthday", created, created)); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate holiday to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	list, _, err := s.GetAllHoliday(db.Page{Size: 10})
+	if err != nil {
+		t.Fatalf("failed to get holidays: %v", err)
+	}
+	ids := make([]string, 0, len(list))
+	for _, h := range list {
+		ids = append(ids, h.ID)
+	}
+	checkIDs(t, "holidays", ids, "h2", "h3", "h1")
+
+	if err := s.UpdateHoliday(model.NewHoliday("h3", "Sports days", created.AddDate(0, 1, 0), created.AddDate(0, 1, 2))); err != nil {
+		t.Fatalf("failed to update holiday: %v", err)
+	}
+	if got, err := s.GetHoliday("h3"); err != nil || got.Name != "Sports days" {
+		t.Errorf("unexpected holiday after update %+v %v", got, err)
+	}
+
+	if err := s.DeleteHoliday("h3"); err != nil {
+		t.Fatalf("failed to delete holiday: %v", err)
+	}
+	if err := s.DeleteHoliday("h3"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting delete of missing holiday to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testRoom(t *testing.T, s db.Store) {
+	if _, err := s.GetRoom("r1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing room to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.UpdateRoom(model.NewRoom("r1", "Music room", 10, nil)); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting update of missing room to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	for _, r := range []*model.Room{
+		model.NewRoom("r2", "Lab", 30, []string{"lab"}),
+		model.NewRoom("r1", "Music room", 10, []string{"piano"}),
+		model.NewRoom("r3", "Hall", 200, nil),
+	} {
+		if err := s.AddRoom(r); err != nil {
+			t.Fatalf("failed to add room %v: %v", r.ID, err)
+		}
+	}
+	if err := s.AddRoom(model.NewRoom("r1", "Music room", 10, nil)); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate room to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	list, _, err := s.GetAllRoom(db.Page{Size: 10})
+	if err != nil {
+		t.Fatalf("failed to get rooms: %v", err)
+	}
+	ids := make([]string, 0, len(list))
+	for _, r := range list {
+		ids = append(ids, r.ID)
+	}
+	checkIDs(t, "rooms", ids, "r1", "r2", "r3")
+
+	if err := s.UpdateRoom(model.NewRoom("r1", "Music room", 12, []string{"piano", "drums"})); err != nil {
+		t.Fatalf("failed to update room: %v", err)
+	}
+	if got, err := s.GetRoom("r1"); err != nil || got.Capacity != 12 || !got.HasFeatures([]string{"drums", "piano"}) {
+		t.Errorf("unexpected room after update %+v %v", got, err)
+	}
+
+	if err := s.DeleteRoom("r1"); err != nil {
+		t.Fatalf("failed to delete room: %v", err)
+	}
+	if err := s.DeleteRoom("r1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting delete of missing room to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testStudent(t *testing.T, s db.Store) {
+	if _, err := s.GetStudent("s1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing student to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.UpdateStudent(model.NewStudent("s1", "alice", "P.4", "", "", "", "")); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting update of missing student to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+	if err := s.AddStudent(model.NewStudent("s1", "alice", "P.4", "", "", "", "")); err != nil {
+		t.Fatalf("failed to add student: %v", err)
+	}
+	if err := s.AddStudent(model.NewStudent("s1", "bob", "P.5", "", "", "", "")); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate student to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	if err := s.UpdateStudent(model.NewStudent("s1", "alice", "P.5", "carol", "0812345678", "", "")); err != nil {
+		t.Fatalf("failed to update student: %v", err)
+	}
+	if got, err := s.GetStudent("s1"); err != nil || got.Name != "alice" || got.Level != "P.5" || got.GuardianName != "carol" {
+		t.Errorf("unexpected student after update %+v %v", got, err)
+	}
+
+	if err := s.DeleteStudent("s1"); err != nil {
+		t.Fatalf("failed to delete student: %v", err)
+	}
+	if err := s.DeleteStudent("s1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting delete of missing student to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+}
+
+func testSchedule(t *testing.T, s db.Store) {
+	if _, err := s.GetSchedule("sc1"); err != c.ErrDBNoSuchEntity {
+		t.Errorf("expecting missing schedule to fail with c.ErrDBNoSuchEntity, got %v", err)
+	}
+
+	older := model.NewSchedule("sc2", "c1", "", "", nil)
+	older.Created = created
+	newer := model.NewSchedule("sc1", "c1", "", "", nil)
+	newer.Created = created.Add(time.Hour)
+	slots := []*model.ScheduleSlot{
+		// s3 is another student called alice
+		{ID: "sc1-3", ScheduleID: "sc1", TeacherID: "t1", SubjectID: "piano", Day: model.Tuesday, Period: 1,
+			StudentNames: []string{"bob", "alice"}, StudentIDs: []string{"s2", "s3"}},
+		{ID: "sc1-1", ScheduleID: "sc1", TeacherID: "t1", SubjectID: "piano", Day: model.Monday, Period: 1,
+			StudentNames: []string{"alice", "bob"}, StudentIDs: []string{"s1", "s2"}},
+		{ID: "sc1-2", ScheduleID: "sc1", TeacherID: "t2", SubjectID: "violin", Day: model.Monday, Period: 2,
+			StudentNames: []string{"alice"}, StudentIDs: []string{"s1"}},
+	}
+	if err := s.AddSchedule(newer, slots); err != nil {
+		t.Fatalf("failed to add schedule: %v", err)
+	}
+	olderSlots := []*model.ScheduleSlot{{ID: "sc2-1", ScheduleID: "sc2", TeacherID: "t1", SubjectID: "piano",
+		Day: model.Monday, Period: 1, StudentNames: []string{"alice"}, StudentIDs: []string{"s1"}}}
+	if err := s.AddSchedule(older, olderSlots); err != nil {
+		t.Fatalf("failed to add schedule: %v", err)
+	}
+	if err := s.AddSchedule(older, nil); err != c.ErrDBEntityAlreadyExists {
+		t.Errorf("expecting duplicate schedule to fail with c.ErrDBEntityAlreadyExists, got %v", err)
+	}
+
+	if got, err := s.GetSchedule("sc2"); err != nil || got.ConfirmationID != "c1" || !got.Created.Equal(created) {
+		t.Errorf("unexpected schedule %+v %v", got, err)
+	}
+
+	tests := []struct {
+		teacherID string
+		studentID string
+		want      []string
+	}{
+		{"", "", []string{"sc1-1", "sc1-2", "sc1-3"}},
+		{"t1", "", []string{"sc1-1", "sc1-3"}},
+		{"", "s1", []string{"sc1-1", "sc1-2"}},
+		{"", "s3", []string{"sc1-3"}},
+		{"", "alice", []string{}},
+		{"t2", "s2", []string{}},
+	}
+	for _, tt := range tests {
+		list, err := s.GetAllScheduleSlot("sc1", tt.teacherID, tt.studentID)
+		if err != nil {
+			t.Fatalf("failed to get schedule slots: %v", err)
+		}
+		checkIDs(t, "schedule slots of "+tt.teacherID+"/"+tt.studentID, slotIDs(list), tt.want...)
+	}
+	list, err := s.GetAllStudentScheduleSlot("sc1", "s2")
+	if err != nil {
+		t.Fatalf("failed to get student schedule slots: %v", err)
+	}
+	checkIDs(t, "schedule slots of s2", slotIDs(list), "sc1-1", "sc1-3")
+	if len(list) > 0 && (list[0].Day != model.Monday || list[0].Period != 1 || list[0].SubjectID != "piano") {
+		t.Errorf("unexpected schedule slot %+v", list[0])
+	}
+}
+
+func slotIDs(list []*model.ScheduleSlot) []string {
+	ids := make([]string, 0, len(list))
+	for _, slot := range list {
+		ids = append(ids, slot.ID)
+	}
+	return ids
+}
@@ -0,0 +1,10 @@
+package db
+
+import "testing"
+
+// EmptyTestDatastore empties the emulator and returns the datastore under test, for the tests of package
+// db_test. The test is skipped without an emulator.
+func EmptyTestDatastore(t *testing.T) (*AppDatastore, error) {
+	skipWithoutEmulator(t)
+	return merchantDB.AppDatastore, merchantDB.tearDown()
+}
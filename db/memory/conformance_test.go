@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db/dbtest"
+)
+
+func TestConformance(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) db.Store {
+		return NewDB()
+	})
+}
@@ -0,0 +1,1422 @@
+// Package memory keeps the application data in memory. It implements the interfaces of the db package
+// with the same conflict and not-found semantics as db.AppDatastore, so the server and its tests run
+// without Google Cloud. Nothing is persisted, the data is gone once the process exits.
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/datastore"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// the kinds match those of db.AppDatastore
+const (
+	kindMerchant              = "Merchant"
+	kindRole                  = "Role"
+	kindKym                   = "Kym"
+	kindTeacher               = "Teacher"
+	kindMainSubject           = "MainSubject"
+	kindSubject               = "Subject"
+	kindConfirmation          = "Confirmation"
+	kindConfirmationDetail    = "ConfirmationDetail"
+	kindTeacherResponsibility = "TeacherResponsibility"
+	kindSchedule              = "Schedule"
+	kindScheduleSlot          = "ScheduleSlot"
+	kindBellSchedule          = "BellSchedule"
+	kindTerm                  = "Term"
+	kindHoliday               = "Holiday"
+	kindRoom                  = "Room"
+	kindStudent               = "Student"
+)
+
+// DB is an in-memory implementation of db.Store.
+// Entities are kept as the datastore properties they save to and loaded again on every read, so callers
+// never share data with the store and the models read back the way they do from datastore. A single lock
+// serializes the writes, which makes every method behave like a datastore transaction.
+type DB struct {
+	mu sync.RWMutex
+	// entities holds the properties of each entity by kind and key name
+	entities map[string]map[string][]datastore.Property
+}
+
+var _ db.Store = (*DB)(nil)
+
+// NewDB creates an empty in-memory store
+func NewDB() *DB {
+	return &DB{entities: make(map[string]map[string][]datastore.Property)}
+}
+
+// Close releases nothing, it lets DB be used where a datastore connection is expected
+func (mdb *DB) Close() error {
+	return nil
+}
+
+// ----- Entity access, the callers hold the lock -------------------------------
+
+// get loads the entity into dst, failing with c.ErrDBNoSuchEntity when it does not exist
+func (mdb *DB) get(kind, name string, dst interface{}) error {
+	ps, ok := mdb.entities[kind][name]
+	if !ok {
+		return c.ErrDBNoSuchEntity
+	}
+	return load(dst, ps)
+}
+
+// exists reports whether the entity is stored
+func (mdb *DB) exists(kind, name string) bool {
+	_, ok := mdb.entities[kind][name]
+	return ok
+}
+
+// put stores src under the name, replacing the entity stored before
+func (mdb *DB) put(kind, name string, src interface{}) error {
+	ps, err := save(src)
+	if err != nil {
+		return err
+	}
+	if mdb.entities[kind] == nil {
+		mdb.entities[kind] = make(map[string][]datastore.Property)
+	}
+	mdb.entities[kind][name] = ps
+	return nil
+}
+
+// remove removes the entities, names which do not exist are ignored
+func (mdb *DB) remove(kind string, names ...string) {
+	for _, name := range names {
+		delete(mdb.entities[kind], name)
+	}
+}
+
+// getAll loads every entity of the kind into dst, which must be a pointer to a slice of entity pointers.
+// The entities are ordered by key name like a datastore query without sort orders.
+func (mdb *DB) getAll(kind string, dst interface{}) error {
+	names := make([]string, 0, len(mdb.entities[kind]))
+	for name := range mdb.entities[kind] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := reflect.ValueOf(dst).Elem()
+	entityType := list.Type().Elem().Elem()
+	for _, name := range names {
+		entity := reflect.New(entityType)
+		if err := load(entity.Interface(), mdb.entities[kind][name]); err != nil {
+			return err
+		}
+		list.Set(reflect.Append(list, entity))
+	}
+	return nil
+}
+
+// save gets the properties of src the way datastore does, through its Save method when it has one
+func save(src interface{}) ([]datastore.Property, error) {
+	if pls, ok := src.(datastore.PropertyLoadSaver); ok {
+		return pls.Save()
+	}
+	return datastore.SaveStruct(src)
+}
+
+// load sets dst from a copy of the properties, Load methods are free to modify the properties they are given
+func load(dst interface{}, ps []datastore.Property) error {
+	ps = copyProperties(ps)
+	if pls, ok := dst.(datastore.PropertyLoadSaver); ok {
+		return pls.Load(ps)
+	}
+	return datastore.LoadStruct(dst, ps)
+}
+
+// copyProperties deep copies the list values and nested entities of the properties
+func copyProperties(ps []datastore.Property) []datastore.Property {
+	res := make([]datastore.Property, len(ps))
+	for i, p := range ps {
+		p.Value = copyValue(p.Value)
+		res[i] = p
+	}
+	return res
+}
+
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, e := range v {
+			res[i] = copyValue(e)
+		}
+		return res
+	case *datastore.Entity:
+		if v == nil {
+			return v
+		}
+		return &datastore.Entity{Key: v.Key, Properties: copyProperties(v.Properties)}
+	case []byte:
+		return append([]byte(nil), v...)
+	}
+	return v
+}
+
+// ----- Paging -------------------------------------------------------------------
+
+// getPage cuts a single page out of list, a pointer to a slice holding the whole result of a query in order.
+// The returned token is the offset of the next page and is empty on the last page.
+func getPage(list interface{}, page db.Page) (string, error) {
+	start, err := db.ParseOffsetToken(page.Token)
+	if err != nil {
+		return "", err
+	}
+
+	v := reflect.ValueOf(list).Elem()
+	if start > v.Len() {
+		start = v.Len()
+	}
+	end := start + page.Size
+	if end >= v.Len() {
+		v.Set(v.Slice(start, v.Len()))
+		return "", nil
+	}
+	v.Set(v.Slice(start, end))
+	return db.OffsetToken(end), nil
+}
+
+// add stores a new entity, failing with c.ErrDBEntityAlreadyExists when the name is taken
+func (mdb *DB) add(kind, name string, src interface{}) error {
+	if mdb.exists(kind, name) {
+		return c.ErrDBEntityAlreadyExists
+	}
+	return mdb.put(kind, name, src)
+}
+
+// replace stores the entity over an existing one, failing with c.ErrDBNoSuchEntity when there is none
+func (mdb *DB) replace(kind, name string, src interface{}) error {
+	if !mdb.exists(kind, name) {
+		return c.ErrDBNoSuchEntity
+	}
+	return mdb.put(kind, name, src)
+}
+
+// lockedAdd is add under the write lock
+func (mdb *DB) lockedAdd(kind, name string, src interface{}) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+	return mdb.add(kind, name, src)
+}
+
+// lockedReplace is replace under the write lock
+func (mdb *DB) lockedReplace(kind, name string, src interface{}) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+	return mdb.replace(kind, name, src)
+}
+
+// lockedGet is get under the read lock
+func (mdb *DB) lockedGet(kind, name string, dst interface{}) error {
+	mdb.mu.RLock()
+	defer mdb.mu.RUnlock()
+	return mdb.get(kind, name, dst)
+}
+
+// lockedGetAll is getAll under the read lock
+func (mdb *DB) lockedGetAll(kind string, dst interface{}) error {
+	mdb.mu.RLock()
+	defer mdb.mu.RUnlock()
+	return mdb.getAll(kind, dst)
+}
+
+// lockedDelete removes an existing entity under the write lock, failing with c.ErrDBNoSuchEntity when there is none
+func (mdb *DB) lockedDelete(kind, name string) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+	if !mdb.exists(kind, name) {
+		return c.ErrDBNoSuchEntity
+	}
+	mdb.remove(kind, name)
+	return nil
+}
+
+// ----- Merchants, roles and kym -------------------------------------------------
+
+// GetMerchant returns the Merchant given the ID
+func (mdb *DB) GetMerchant(merchantID string) (*model.Merchant, error) {
+	m := &model.Merchant{}
+	if err := mdb.lockedGet(kindMerchant, merchantID, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AddMerchant adds a new Merchant, merchantID == organisationID so organisations are not added twice
+func (mdb *DB) AddMerchant(m *model.Merchant) error {
+	return mdb.lockedAdd(kindMerchant, m.MerchantID, m)
+}
+
+// UpdateMerchant updates the existing Merchant with the new properties
+func (mdb *DB) UpdateMerchant(m *model.Merchant) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	old := &model.Merchant{}
+	if err := mdb.get(kindMerchant, m.MerchantID, old); err != nil {
+		return err
+	}
+	if err := old.CheckUpdate(m); err != nil {
+		return err
+	}
+
+	// preserve other fields and update the last updated timestamp
+	m.Updated = time.Now()
+	m.Created = old.Created
+	m.PayOutConfig = old.PayOutConfig
+	return mdb.put(kindMerchant, m.MerchantID, m)
+}
+
+// UpsertPayOutConfig Merchant's PayOutConfig to be updated or inserted
+func (mdb *DB) UpsertPayOutConfig(merchantID string, poc *model.PayOutConfig) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	old := &model.Merchant{}
+	if err := mdb.get(kindMerchant, merchantID, old); err != nil {
+		return err
+	}
+	old.PayOutConfig = poc
+	old.Updated = time.Now()
+	return mdb.put(kindMerchant, merchantID, old)
+}
+
+// GetRole retrieves an organisation role by ID
+func (mdb *DB) GetRole(organisationID, userID string) (*model.Role, error) {
+	role := &model.Role{}
+	if err := mdb.lockedGet(kindRole, roleName(organisationID, userID), role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// AddRole stores a role, a user has a single role in each organisation
+func (mdb *DB) AddRole(role *model.Role) error {
+	return mdb.lockedAdd(kindRole, roleName(role.OrganisationID, role.UserID), role)
+}
+
+func roleName(organisationID, userID string) string {
+	return fmt.Sprintf("%v-%v", organisationID, userID)
+}
+
+// AddKym adds a new Kym
+func (mdb *DB) AddKym(kym *model.Kym) error {
+	return mdb.lockedAdd(kindKym, kym.ID, kym)
+}
+
+// GetAllKym gets a page of kym with the status, newest first. An empty status is not filtered on.
+func (mdb *DB) GetAllKym(status string, page db.Page) ([]*model.Kym, string, error) {
+	if len(status) > 0 && !c.IsValidKymStatus(status) {
+		return nil, "", &c.ErrValidation{Violations: fmt.Errorf("filter is not allowed with status : %v", status)}
+	}
+
+	all := make([]*model.Kym, 0)
+	if err := mdb.lockedGetAll(kindKym, &all); err != nil {
+		return nil, "", err
+	}
+	list := make([]*model.Kym, 0, len(all))
+	for _, k := range all {
+		if len(status) == 0 || k.Status == status {
+			list = append(list, k)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].DatetimeCreated.After(list[j].DatetimeCreated) })
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetKym gets a single kym by id
+func (mdb *DB) GetKym(id string) (*model.Kym, error) {
+	kym := &model.Kym{}
+	if err := mdb.lockedGet(kindKym, id, kym); err != nil {
+		return nil, err
+	}
+	return kym, nil
+}
+
+// UpdateKymStatus sets the status and notes of an existing kym
+func (mdb *DB) UpdateKymStatus(kym *model.Kym, status string, notes string) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if !mdb.exists(kindKym, kym.ID) {
+		return c.ErrDBNoSuchEntity
+	}
+	kym.Status = status
+	kym.Notes = notes
+	return mdb.put(kindKym, kym.ID, kym)
+}
+
+// ----- Teachers and responsibilities --------------------------------------------
+
+// AddTeacher adds a new Teacher, its main subject has to exist
+func (mdb *DB) AddTeacher(m *model.Teacher) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if err := mdb.checkMainSubjectExists(m.MainSubjectID); err != nil {
+		return err
+	}
+	return mdb.add(kindTeacher, m.ID, m)
+}
+
+// GetAllTeacher gets a page of the teachers matching tq
+func (mdb *DB) GetAllTeacher(tq db.TeacherQuery, page db.Page) ([]*model.Teacher, string, error) {
+	all := make([]*model.Teacher, 0)
+	if err := mdb.lockedGetAll(kindTeacher, &all); err != nil {
+		return nil, "", err
+	}
+
+	q := strings.ToLower(strings.TrimSpace(tq.Q))
+	list := make([]*model.Teacher, 0, len(all))
+	for _, t := range all {
+		if len(tq.MainSubjectID) > 0 && t.MainSubjectID != tq.MainSubjectID {
+			continue
+		}
+		if len(q) > 0 && !hasNamePrefix(q, t.FirstName, t.NickName, t.LastName) {
+			continue
+		}
+		list = append(list, t)
+	}
+
+	switch tq.Sort {
+	case db.TeacherSortFirstName:
+		sort.SliceStable(list, func(i, j int) bool { return list[i].FirstName < list[j].FirstName })
+	case db.TeacherSortNickName:
+		sort.SliceStable(list, func(i, j int) bool { return list[i].NickName < list[j].NickName })
+	case db.TeacherSortLastName:
+		sort.SliceStable(list, func(i, j int) bool { return list[i].LastName < list[j].LastName })
+	}
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// hasNamePrefix reports whether any of the names starts with the lowercase prefix, ignoring case,
+// like the model.TeacherNamePrefixes datastore searches on
+func hasNamePrefix(prefix string, names ...string) bool {
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(name)), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTeacher gets a single teacher by id
+func (mdb *DB) GetTeacher(id string) (*model.Teacher, error) {
+	t := &model.Teacher{}
+	if err := mdb.lockedGet(kindTeacher, id, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UpdateTeacherAvailability replaces the availability of an existing teacher
+func (mdb *DB) UpdateTeacherAvailability(id string, availability []model.AvailabilityWindow, exceptions []model.AvailabilityException) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	old := &model.Teacher{}
+	if err := mdb.get(kindTeacher, id, old); err != nil {
+		return err
+	}
+	old.Availability = availability
+	old.AvailabilityExceptions = exceptions
+	return mdb.put(kindTeacher, id, old)
+}
+
+// UpdateTeacher replaces an existing teacher, its main subject has to exist
+func (mdb *DB) UpdateTeacher(m *model.Teacher) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if err := mdb.checkMainSubjectExists(m.MainSubjectID); err != nil {
+		return err
+	}
+	return mdb.replace(kindTeacher, m.ID, m)
+}
+
+// DeleteTeacher removes an existing teacher together with its responsibilities
+func (mdb *DB) DeleteTeacher(id string) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if !mdb.exists(kindTeacher, id) {
+		return c.ErrDBNoSuchEntity
+	}
+	responsibilities, err := mdb.responsibilities(id, "")
+	if err != nil {
+		return err
+	}
+	for _, tr := range responsibilities {
+		mdb.remove(kindTeacherResponsibility, responsibilityName(tr.TeacherId, tr.SubjectId))
+	}
+	mdb.remove(kindTeacher, id)
+	return nil
+}
+
+// AddTeacherResponsibility qualifies a teacher for a subject.
+// Both the teacher and the subject need to exist, and a teacher can only be qualified once per subject.
+func (mdb *DB) AddTeacherResponsibility(tr *model.TeacherResponsibility) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if !mdb.exists(kindTeacher, tr.TeacherId) {
+		return c.ErrDBNoSuchEntity
+	}
+	if !mdb.exists(kindSubject, tr.SubjectId) {
+		return &c.ErrValidation{Violations: fmt.Errorf("subject %v does not exist", tr.SubjectId)}
+	}
+	return mdb.add(kindTeacherResponsibility, responsibilityName(tr.TeacherId, tr.SubjectId), tr)
+}
+
+// GetAllTeacherResponsibility gets a page of teacher qualifications ordered by teacher and subject.
+// Empty teacherID or subjectID values are not filtered on.
+func (mdb *DB) GetAllTeacherResponsibility(teacherID string, subjectID string, page db.Page) ([]*model.TeacherResponsibility, string, error) {
+	mdb.mu.RLock()
+	list, err := mdb.responsibilities(teacherID, subjectID)
+	mdb.mu.RUnlock()
+	if err != nil {
+		return nil, "", err
+	}
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// ReplaceTeacherResponsibility replaces all qualifications of a teacher at once, a subject listed more than
+// once is stored once
+func (mdb *DB) ReplaceTeacherResponsibility(teacherID string, subjectIDs []string) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if !mdb.exists(kindTeacher, teacherID) {
+		return c.ErrDBNoSuchEntity
+	}
+	list := model.NewTeacherResponsibilities(teacherID, subjectIDs)
+	ids := make([]string, len(list))
+	for i, tr := range list {
+		ids[i] = tr.SubjectId
+	}
+	if err := mdb.checkSubjectsExist(ids); err != nil {
+		return err
+	}
+
+	existing, err := mdb.responsibilities(teacherID, "")
+	if err != nil {
+		return err
+	}
+	for _, tr := range existing {
+		mdb.remove(kindTeacherResponsibility, responsibilityName(tr.TeacherId, tr.SubjectId))
+	}
+	for _, tr := range list {
+		if err := mdb.put(kindTeacherResponsibility, responsibilityName(teacherID, tr.SubjectId), tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTeacherResponsibility removes a single qualification of a teacher
+func (mdb *DB) DeleteTeacherResponsibility(teacherID string, subjectID string) error {
+	return mdb.lockedDelete(kindTeacherResponsibility, responsibilityName(teacherID, subjectID))
+}
+
+// GetAllTeacherBySubject gets a page of the teachers qualified for a subject, either through their main
+// subject or an explicit responsibility, ordered by teacher id. An unknown subject fails with c.ErrDBNoSuchEntity.
+func (mdb *DB) GetAllTeacherBySubject(subjectID string, page db.Page) ([]*model.Teacher, string, error) {
+	mdb.mu.RLock()
+	list, err := mdb.teachersBySubject(subjectID)
+	mdb.mu.RUnlock()
+	if err != nil {
+		return nil, "", err
+	}
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+func (mdb *DB) teachersBySubject(subjectID string) ([]*model.Teacher, error) {
+	subject := &model.Subject{}
+	if err := mdb.get(kindSubject, subjectID, subject); err != nil {
+		return nil, err
+	}
+
+	responsibilities, err := mdb.responsibilities("", subjectID)
+	if err != nil {
+		return nil, err
+	}
+	qualified := make(map[string]bool, len(responsibilities))
+	for _, tr := range responsibilities {
+		qualified[tr.TeacherId] = true
+	}
+
+	teachers := make([]*model.Teacher, 0)
+	if err := mdb.getAll(kindTeacher, &teachers); err != nil {
+		return nil, err
+	}
+	list := make([]*model.Teacher, 0)
+	for _, t := range teachers {
+		if t.MainSubjectID == subject.MainSubjectId || qualified[t.ID] {
+			list = append(list, t)
+		}
+	}
+	return list, nil
+}
+
+// GetAllSubjectByTeacher gets a page of the subjects a teacher is qualified for, either through the
+// teacher's main subject or an explicit responsibility, ordered by subject id. An unknown teacher fails with
+// c.ErrDBNoSuchEntity.
+func (mdb *DB) GetAllSubjectByTeacher(teacherID string, page db.Page) ([]*model.Subject, string, error) {
+	mdb.mu.RLock()
+	list, err := mdb.subjectsByTeacher(teacherID)
+	mdb.mu.RUnlock()
+	if err != nil {
+		return nil, "", err
+	}
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+func (mdb *DB) subjectsByTeacher(teacherID string) ([]*model.Subject, error) {
+	teacher := &model.Teacher{}
+	if err := mdb.get(kindTeacher, teacherID, teacher); err != nil {
+		return nil, err
+	}
+
+	responsibilities, err := mdb.responsibilities(teacherID, "")
+	if err != nil {
+		return nil, err
+	}
+	qualified := make(map[string]bool, len(responsibilities))
+	for _, tr := range responsibilities {
+		qualified[tr.SubjectId] = true
+	}
+
+	subjects := make([]*model.Subject, 0)
+	if err := mdb.getAll(kindSubject, &subjects); err != nil {
+		return nil, err
+	}
+	list := make([]*model.Subject, 0)
+	for _, s := range subjects {
+		if s.MainSubjectId == teacher.MainSubjectID || qualified[s.ID] {
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+// responsibilities gets the responsibilities ordered by teacher and subject like the keys of datastore,
+// where they are children of their teacher. Empty teacherID or subjectID values are not filtered on.
+func (mdb *DB) responsibilities(teacherID string, subjectID string) ([]*model.TeacherResponsibility, error) {
+	all := make([]*model.TeacherResponsibility, 0)
+	if err := mdb.getAll(kindTeacherResponsibility, &all); err != nil {
+		return nil, err
+	}
+	list := make([]*model.TeacherResponsibility, 0, len(all))
+	for _, tr := range all {
+		if (len(teacherID) == 0 || tr.TeacherId == teacherID) && (len(subjectID) == 0 || tr.SubjectId == subjectID) {
+			list = append(list, tr)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].TeacherId != list[j].TeacherId {
+			return list[i].TeacherId < list[j].TeacherId
+		}
+		return list[i].SubjectId < list[j].SubjectId
+	})
+	return list, nil
+}
+
+// responsibilityName is the key name of a teacher's qualification for a subject
+func responsibilityName(teacherID, subjectID string) string {
+	return teacherID + "/" + subjectID
+}
+
+// ----- Main subjects and subjects -----------------------------------------------
+
+// AddMainSubject adds a new MainSubject
+func (mdb *DB) AddMainSubject(m *model.MainSubject) error {
+	return mdb.lockedAdd(kindMainSubject, m.ID, m)
+}
+
+// GetAllMainSubject gets a page of main subjects
+func (mdb *DB) GetAllMainSubject(page db.Page) ([]*model.MainSubject, string, error) {
+	list := make([]*model.MainSubject, 0)
+	if err := mdb.lockedGetAll(kindMainSubject, &list); err != nil {
+		return nil, "", err
+	}
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetMainSubject gets a single main subject by id
+func (mdb *DB) GetMainSubject(id string) (*model.MainSubject, error) {
+	m := &model.MainSubject{}
+	if err := mdb.lockedGet(kindMainSubject, id, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UpdateMainSubject replaces an existing main subject
+func (mdb *DB) UpdateMainSubject(m *model.MainSubject) error {
+	return mdb.lockedReplace(kindMainSubject, m.ID, m)
+}
+
+// DeleteMainSubject removes an existing main subject.
+// Subjects and teachers referring to the main subject block the delete with c.ErrConflict. When cascade
+// is set they are removed as well, together with the responsibilities attached to them, unless
+// confirmation details ask for one of the subjects.
+func (mdb *DB) DeleteMainSubject(id string, cascade bool) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if !mdb.exists(kindMainSubject, id) {
+		return c.ErrDBNoSuchEntity
+	}
+
+	subjects := make([]*model.Subject, 0)
+	if err := mdb.getAll(kindSubject, &subjects); err != nil {
+		return err
+	}
+	teachers := make([]*model.Teacher, 0)
+	if err := mdb.getAll(kindTeacher, &teachers); err != nil {
+		return err
+	}
+	subjectIDs := make([]string, 0)
+	for _, s := range subjects {
+		if s.MainSubjectId == id {
+			subjectIDs = append(subjectIDs, s.ID)
+		}
+	}
+	teacherIDs := make([]string, 0)
+	for _, t := range teachers {
+		if t.MainSubjectID == id {
+			teacherIDs = append(teacherIDs, t.ID)
+		}
+	}
+
+	if used := len(subjectIDs) + len(teacherIDs); used > 0 && !cascade {
+		return fmt.Errorf("%w: main subject %v is still used by %d subjects and teachers", c.ErrConflict, id, used)
+	}
+
+	removed := make(map[string]bool, len(subjectIDs)+len(teacherIDs))
+	for _, sid := range subjectIDs {
+		removed[sid] = true
+	}
+	asked, err := mdb.countSubjectDetails(removed)
+	if err != nil {
+		return err
+	}
+	if asked > 0 {
+		return fmt.Errorf("%w: subjects of main subject %v are still asked for by %d confirmation details", c.ErrConflict, id, asked)
+	}
+
+	responsibilities, err := mdb.responsibilities("", "")
+	if err != nil {
+		return err
+	}
+	for _, tid := range teacherIDs {
+		removed[tid] = true
+	}
+	for _, tr := range responsibilities {
+		if removed[tr.TeacherId] || removed[tr.SubjectId] {
+			mdb.remove(kindTeacherResponsibility, responsibilityName(tr.TeacherId, tr.SubjectId))
+		}
+	}
+	mdb.remove(kindSubject, subjectIDs...)
+	mdb.remove(kindTeacher, teacherIDs...)
+	mdb.remove(kindMainSubject, id)
+	return nil
+}
+
+// AddSubject adds a new Subject, its main subject has to exist
+func (mdb *DB) AddSubject(m *model.Subject) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if err := mdb.checkMainSubjectExists(m.MainSubjectId); err != nil {
+		return err
+	}
+	return mdb.add(kindSubject, m.ID, m)
+}
+
+// GetAllSubject gets a page of the subjects matching sq. Like a datastore query with an inequality
+// filter, filtering on the minimum number of students orders the subjects by it.
+func (mdb *DB) GetAllSubject(sq db.SubjectQuery, page db.Page) ([]*model.Subject, string, error) {
+	all := make([]*model.Subject, 0)
+	if err := mdb.lockedGetAll(kindSubject, &all); err != nil {
+		return nil, "", err
+	}
+
+	list := make([]*model.Subject, 0, len(all))
+	for _, s := range all {
+		if len(sq.MainSubjectID) > 0 && s.MainSubjectId != sq.MainSubjectID {
+			continue
+		}
+		if sq.MinStudentsGte > 0 && s.MinOfStudent < sq.MinStudentsGte {
+			continue
+		}
+		list = append(list, s)
+	}
+	if sq.MinStudentsGte > 0 {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].MinOfStudent < list[j].MinOfStudent })
+	}
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetSubject gets a single subject by id
+func (mdb *DB) GetSubject(id string) (*model.Subject, error) {
+	m := &model.Subject{}
+	if err := mdb.lockedGet(kindSubject, id, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UpdateSubject replaces an existing subject, its main subject has to exist
+func (mdb *DB) UpdateSubject(m *model.Subject) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if err := mdb.checkMainSubjectExists(m.MainSubjectId); err != nil {
+		return err
+	}
+	return mdb.replace(kindSubject, m.ID, m)
+}
+
+// DeleteSubject removes an existing subject.
+// Teacher responsibilities for the subject block the delete with c.ErrConflict, unless cascade is set
+// in which case they are removed as well. Confirmation details asking for the subject always block it.
+func (mdb *DB) DeleteSubject(id string, cascade bool) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if !mdb.exists(kindSubject, id) {
+		return c.ErrDBNoSuchEntity
+	}
+	asked, err := mdb.countSubjectDetails(map[string]bool{id: true})
+	if err != nil {
+		return err
+	}
+	if asked > 0 {
+		return fmt.Errorf("%w: subject %v is still asked for by %d confirmation details", c.ErrConflict, id, asked)
+	}
+	responsibilities, err := mdb.responsibilities("", id)
+	if err != nil {
+		return err
+	}
+	if len(responsibilities) > 0 && !cascade {
+		return fmt.Errorf("%w: subject %v is still assigned to %d teachers", c.ErrConflict, id, len(responsibilities))
+	}
+
+	for _, tr := range responsibilities {
+		mdb.remove(kindTeacherResponsibility, responsibilityName(tr.TeacherId, tr.SubjectId))
+	}
+	mdb.remove(kindSubject, id)
+	return nil
+}
+
+// countSubjectDetails counts the confirmation details asking for one of the subjects
+func (mdb *DB) countSubjectDetails(subjectIDs map[string]bool) (int, error) {
+	details := make([]*model.ConfirmationDetail, 0)
+	if err := mdb.getAll(kindConfirmationDetail, &details); err != nil {
+		return 0, err
+	}
+	asked := 0
+	for _, d := range details {
+		for _, subjectID := range d.SubjectDetailID {
+			if subjectIDs[subjectID] {
+				asked++
+				break
+			}
+		}
+	}
+	return asked, nil
+}
+
+// checkMainSubjectExists returns a validation error when the main subject referred to does not exist
+func (mdb *DB) checkMainSubjectExists(id string) error {
+	if !mdb.exists(kindMainSubject, id) {
+		return &c.ErrValidation{Violations: fmt.Errorf("main subject %v does not exist", id)}
+	}
+	return nil
+}
+
+// checkSubjectsExist fails with c.ErrValidation listing the subjects which do not exist
+func (mdb *DB) checkSubjectsExist(ids []string) error {
+	missing := make([]string, 0)
+	for _, id := range ids {
+		if !mdb.exists(kindSubject, id) {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return &c.ErrValidation{Violations: fmt.Errorf("subjects %v do not exist", missing)}
+	}
+	return nil
+}
+
+// ----- Confirmations --------------------------------------------------------------
+
+// AddConfirmation adds a new Confirmation, its bell schedule and term have to exist when set
+func (mdb *DB) AddConfirmation(m *model.Confirmation) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if len(m.BellScheduleID) > 0 && !mdb.exists(kindBellSchedule, m.BellScheduleID) {
+		return &c.ErrValidation{Violations: fmt.Errorf("bell schedule %v does not exist", m.BellScheduleID)}
+	}
+	if len(m.TermID) > 0 && !mdb.exists(kindTerm, m.TermID) {
+		return &c.ErrValidation{Violations: fmt.Errorf("term %v does not exist", m.TermID)}
+	}
+	return mdb.add(kindConfirmation, m.ID, m)
+}
+
+// GetConfirmation gets a single confirmation by id
+func (mdb *DB) GetConfirmation(id string) (*model.Confirmation, error) {
+	m := &model.Confirmation{}
+	if err := mdb.lockedGet(kindConfirmation, id, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetAllConfirmation gets a page of confirmations
+func (mdb *DB) GetAllConfirmation(page db.Page) ([]*model.Confirmation, string, error) {
+	list := make([]*model.Confirmation, 0)
+	if err := mdb.lockedGetAll(kindConfirmation, &list); err != nil {
+		return nil, "", err
+	}
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// AddConfirmationDetail adds a detail to its confirmation. The confirmation has to accept details and the
+// subjects have to exist, a second detail of the student on the same day and period of the confirmation is
+// rejected. Both fail with c.ErrValidation naming the offending ids.
+func (mdb *DB) AddConfirmationDetail(m *model.ConfirmationDetail) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	return mdb.addConfirmationDetail(m)
+}
+
+// addConfirmationDetail checks and adds a single detail, the caller holds the lock
+func (mdb *DB) addConfirmationDetail(m *model.ConfirmationDetail) error {
+	confirmation := &model.Confirmation{}
+	switch err := mdb.get(kindConfirmation, m.ConfirmationID, confirmation); err {
+	case nil:
+	case c.ErrDBNoSuchEntity:
+		return &c.ErrValidation{Violations: fmt.Errorf("confirmation %v does not exist", m.ConfirmationID)}
+	default:
+		return err
+	}
+	if err := confirmation.CheckAcceptsDetails(); err != nil {
+		return err
+	}
+	if err := mdb.checkSubjectsExist(m.SubjectDetailID); err != nil {
+		return err
+	}
+
+	details, err := mdb.confirmationDetails(m.ConfirmationID)
+	if err != nil {
+		return err
+	}
+	for _, d := range details {
+		if d.ID != m.ID && d.Day == m.Day && d.Period == m.Period && d.StudentKey() == m.StudentKey() {
+			return &c.ErrValidation{Violations: fmt.Errorf("student %v already has detail %v on %v period %d of confirmation %v",
+				m.StudentKey(), d.ID, m.Day, m.Period, m.ConfirmationID)}
+		}
+	}
+
+	return mdb.add(kindConfirmationDetail, m.ID, m)
+}
+
+// AddConfirmationDetails adds the confirmation details, each checked the way AddConfirmationDetail checks
+// it. Either all of them are added or, when one fails, none.
+func (mdb *DB) AddConfirmationDetails(list []*model.ConfirmationDetail) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	added := make([]string, 0, len(list))
+	for _, m := range list {
+		if err := mdb.addConfirmationDetail(m); err != nil {
+			// the added ids were free before, removing them restores the store
+			mdb.remove(kindConfirmationDetail, added...)
+			return err
+		}
+		added = append(added, m.ID)
+	}
+	return nil
+}
+
+// GetAllConfirmationDetail gets a page of the details of a confirmation
+func (mdb *DB) GetAllConfirmationDetail(confirmationId string, page db.Page) ([]*model.ConfirmationDetail, string, error) {
+	mdb.mu.RLock()
+	list, err := mdb.confirmationDetails(confirmationId)
+	mdb.mu.RUnlock()
+	if err != nil {
+		return nil, "", err
+	}
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+func (mdb *DB) confirmationDetails(confirmationID string) ([]*model.ConfirmationDetail, error) {
+	all := make([]*model.ConfirmationDetail, 0)
+	if err := mdb.getAll(kindConfirmationDetail, &all); err != nil {
+		return nil, err
+	}
+	list := make([]*model.ConfirmationDetail, 0)
+	for _, d := range all {
+		if d.ConfirmationID == confirmationID {
+			list = append(list, d)
+		}
+	}
+	return list, nil
+}
+
+// UpdateConfirmationStatus moves a confirmation to the status, transitions the lifecycle does not allow
+// fail with c.ErrConflict
+func (mdb *DB) UpdateConfirmationStatus(id string, status model.ConfirmationStatus, changedBy string, changedAt time.Time) (*model.Confirmation, error) {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	m := &model.Confirmation{}
+	if err := mdb.get(kindConfirmation, id, m); err != nil {
+		return nil, err
+	}
+	if !m.Status.CanBecome(status) {
+		return nil, fmt.Errorf("%w: confirmation %v cannot become %v while it is %v", c.ErrConflict, id, status, m.Status)
+	}
+	m.Status = status
+	m.StatusChangedBy = changedBy
+	m.StatusChangedAt = changedAt
+	if err := mdb.put(kindConfirmation, id, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ----- Schedules ------------------------------------------------------------------
+
+// AddSchedule adds a Schedule together with its slots
+func (mdb *DB) AddSchedule(s *model.Schedule, slots []*model.ScheduleSlot) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if err := mdb.add(kindSchedule, s.ID, s); err != nil {
+		return err
+	}
+	for _, slot := range slots {
+		if err := mdb.put(kindScheduleSlot, slot.ID, slot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSchedule gets a single schedule by id
+func (mdb *DB) GetSchedule(id string) (*model.Schedule, error) {
+	s := &model.Schedule{}
+	if err := mdb.lockedGet(kindSchedule, id, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetAllScheduleSlot gets the slots of a schedule. Empty teacherID or studentID values are not filtered on.
+func (mdb *DB) GetAllScheduleSlot(scheduleID string, teacherID string, studentID string) ([]*model.ScheduleSlot, error) {
+	all := make([]*model.ScheduleSlot, 0)
+	if err := mdb.lockedGetAll(kindScheduleSlot, &all); err != nil {
+		return nil, err
+	}
+	list := make([]*model.ScheduleSlot, 0)
+	for _, s := range all {
+		if s.ScheduleID != scheduleID {
+			continue
+		}
+		if len(teacherID) > 0 && s.TeacherID != teacherID {
+			continue
+		}
+		if len(studentID) > 0 && !contains(s.StudentIDs, studentID) {
+			continue
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// GetAllStudentScheduleSlot gets the slots of a schedule attended by a student
+func (mdb *DB) GetAllStudentScheduleSlot(scheduleID string, studentID string) ([]*model.ScheduleSlot, error) {
+	all := make([]*model.ScheduleSlot, 0)
+	if err := mdb.lockedGetAll(kindScheduleSlot, &all); err != nil {
+		return nil, err
+	}
+	list := make([]*model.ScheduleSlot, 0)
+	for _, s := range all {
+		if s.ScheduleID == scheduleID && contains(s.StudentIDs, studentID) {
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+// contains reports whether the list holds the value, the way an equality filter matches a list property
+func contains(list []string, v string) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ----- Bell schedules, terms and holidays -----------------------------------------
+
+// AddBellSchedule adds a BellSchedule, a default bell schedule unsets the previous default
+func (mdb *DB) AddBellSchedule(b *model.BellSchedule) error {
+	return mdb.putBellSchedule(b, true)
+}
+
+// GetAllBellSchedule gets a page of bell schedules
+func (mdb *DB) GetAllBellSchedule(page db.Page) ([]*model.BellSchedule, string, error) {
+	list := make([]*model.BellSchedule, 0)
+	if err := mdb.lockedGetAll(kindBellSchedule, &list); err != nil {
+		return nil, "", err
+	}
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetBellSchedule gets a single bell schedule by id
+func (mdb *DB) GetBellSchedule(id string) (*model.BellSchedule, error) {
+	b := &model.BellSchedule{}
+	if err := mdb.lockedGet(kindBellSchedule, id, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetDefaultBellSchedule gets the bell schedule marked as default
+func (mdb *DB) GetDefaultBellSchedule() (*model.BellSchedule, error) {
+	list := make([]*model.BellSchedule, 0)
+	if err := mdb.lockedGetAll(kindBellSchedule, &list); err != nil {
+		return nil, err
+	}
+	for _, b := range list {
+		if b.Default {
+			return b, nil
+		}
+	}
+	return nil, c.ErrDBNoSuchEntity
+}
+
+// UpdateBellSchedule replaces an existing bell schedule, a default bell schedule unsets the previous default
+func (mdb *DB) UpdateBellSchedule(b *model.BellSchedule) error {
+	return mdb.putBellSchedule(b, false)
+}
+
+// putBellSchedule adds the bell schedule when create is set, otherwise replaces the existing one.
+// A default bell schedule unsets the previous default.
+func (mdb *DB) putBellSchedule(b *model.BellSchedule, create bool) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	switch exists := mdb.exists(kindBellSchedule, b.ID); {
+	case exists && create:
+		return c.ErrDBEntityAlreadyExists
+	case !exists && !create:
+		return c.ErrDBNoSuchEntity
+	}
+
+	if b.Default {
+		list := make([]*model.BellSchedule, 0)
+		if err := mdb.getAll(kindBellSchedule, &list); err != nil {
+			return err
+		}
+		for _, previous := range list {
+			if previous.ID == b.ID || !previous.Default {
+				continue
+			}
+			previous.Default = false
+			if err := mdb.put(kindBellSchedule, previous.ID, previous); err != nil {
+				return err
+			}
+		}
+	}
+	return mdb.put(kindBellSchedule, b.ID, b)
+}
+
+// DeleteBellSchedule removes an existing bell schedule.
+// Confirmations referring to the bell schedule block the delete with c.ErrConflict.
+func (mdb *DB) DeleteBellSchedule(id string) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if !mdb.exists(kindBellSchedule, id) {
+		return c.ErrDBNoSuchEntity
+	}
+	confirmations := make([]*model.Confirmation, 0)
+	if err := mdb.getAll(kindConfirmation, &confirmations); err != nil {
+		return err
+	}
+	used := 0
+	for _, m := range confirmations {
+		if m.BellScheduleID == id {
+			used++
+		}
+	}
+	if used > 0 {
+		return fmt.Errorf("%w: bell schedule %v is still used by %d confirmations", c.ErrConflict, id, used)
+	}
+	mdb.remove(kindBellSchedule, id)
+	return nil
+}
+
+// AddTerm adds a new Term
+func (mdb *DB) AddTerm(t *model.Term) error {
+	return mdb.lockedAdd(kindTerm, t.ID, t)
+}
+
+// GetAllTerm gets a page of terms ordered by start date
+func (mdb *DB) GetAllTerm(page db.Page) ([]*model.Term, string, error) {
+	list := make([]*model.Term, 0)
+	if err := mdb.lockedGetAll(kindTerm, &list); err != nil {
+		return nil, "", err
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].StartDate.Before(list[j].StartDate) })
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetTerm gets a single term by id
+func (mdb *DB) GetTerm(id string) (*model.Term, error) {
+	t := &model.Term{}
+	if err := mdb.lockedGet(kindTerm, id, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UpdateTerm replaces an existing term
+func (mdb *DB) UpdateTerm(t *model.Term) error {
+	return mdb.lockedReplace(kindTerm, t.ID, t)
+}
+
+// DeleteTerm removes an existing term.
+// Confirmations referring to the term block the delete with c.ErrConflict.
+func (mdb *DB) DeleteTerm(id string) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if !mdb.exists(kindTerm, id) {
+		return c.ErrDBNoSuchEntity
+	}
+	confirmations := make([]*model.Confirmation, 0)
+	if err := mdb.getAll(kindConfirmation, &confirmations); err != nil {
+		return err
+	}
+	used := 0
+	for _, m := range confirmations {
+		if m.TermID == id {
+			used++
+		}
+	}
+	if used > 0 {
+		return fmt.Errorf("%w: term %v is still used by %d confirmations", c.ErrConflict, id, used)
+	}
+	mdb.remove(kindTerm, id)
+	return nil
+}
+
+// AddHoliday adds a new Holiday
+func (mdb *DB) AddHoliday(h *model.Holiday) error {
+	return mdb.lockedAdd(kindHoliday, h.ID, h)
+}
+
+// GetAllHoliday gets a page of holidays ordered by start date
+func (mdb *DB) GetAllHoliday(page db.Page) ([]*model.Holiday, string, error) {
+	list := make([]*model.Holiday, 0)
+	if err := mdb.lockedGetAll(kindHoliday, &list); err != nil {
+		return nil, "", err
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].StartDate.Before(list[j].StartDate) })
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetHoliday gets a single holiday by id
+func (mdb *DB) GetHoliday(id string) (*model.Holiday, error) {
+	h := &model.Holiday{}
+	if err := mdb.lockedGet(kindHoliday, id, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// UpdateHoliday replaces an existing holiday
+func (mdb *DB) UpdateHoliday(h *model.Holiday) error {
+	return mdb.lockedReplace(kindHoliday, h.ID, h)
+}
+
+// DeleteHoliday removes an existing holiday
+func (mdb *DB) DeleteHoliday(id string) error {
+	return mdb.lockedDelete(kindHoliday, id)
+}
+
+// ----- Rooms and students ---------------------------------------------------------
+
+// AddRoom adds a new Room
+func (mdb *DB) AddRoom(r *model.Room) error {
+	return mdb.lockedAdd(kindRoom, r.ID, r)
+}
+
+// GetAllRoom gets a page of rooms
+func (mdb *DB) GetAllRoom(page db.Page) ([]*model.Room, string, error) {
+	list := make([]*model.Room, 0)
+	if err := mdb.lockedGetAll(kindRoom, &list); err != nil {
+		return nil, "", err
+	}
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetRoom gets a single room by id
+func (mdb *DB) GetRoom(id string) (*model.Room, error) {
+	r := &model.Room{}
+	if err := mdb.lockedGet(kindRoom, id, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpdateRoom replaces an existing room
+func (mdb *DB) UpdateRoom(r *model.Room) error {
+	return mdb.lockedReplace(kindRoom, r.ID, r)
+}
+
+// DeleteRoom removes an existing room
+func (mdb *DB) DeleteRoom(id string) error {
+	return mdb.lockedDelete(kindRoom, id)
+}
+
+// AddStudent adds a new Student
+func (mdb *DB) AddStudent(s *model.Student) error {
+	return mdb.lockedAdd(kindStudent, s.ID, s)
+}
+
+// GetAllStudent gets a page of students ordered by name
+func (mdb *DB) GetAllStudent(page db.Page) ([]*model.Student, string, error) {
+	list := make([]*model.Student, 0)
+	if err := mdb.lockedGetAll(kindStudent, &list); err != nil {
+		return nil, "", err
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	next, err := getPage(&list, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetStudent gets a single student by id
+func (mdb *DB) GetStudent(id string) (*model.Student, error) {
+	s := &model.Student{}
+	if err := mdb.lockedGet(kindStudent, id, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// UpdateStudent replaces an existing student
+func (mdb *DB) UpdateStudent(s *model.Student) error {
+	return mdb.lockedReplace(kindStudent, s.ID, s)
+}
+
+// DeleteStudent removes an existing student.
+// Confirmation details referring to the student block the delete with c.ErrConflict.
+func (mdb *DB) DeleteStudent(id string) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
+
+	if !mdb.exists(kindStudent, id) {
+		return c.ErrDBNoSuchEntity
+	}
+	details := make([]*model.ConfirmationDetail, 0)
+	if err := mdb.getAll(kindConfirmationDetail, &details); err != nil {
+		return err
+	}
+	used := 0
+	for _, d := range details {
+		if d.StudentID == id {
+			used++
+		}
+	}
+	if used > 0 {
+		return fmt.Errorf("%w: student %v is still referred to by %d confirmation details", c.ErrConflict, id, used)
+	}
+	mdb.remove(kindStudent, id)
+	return nil
+}
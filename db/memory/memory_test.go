@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// The behaviour shared with the other backends is tested by the conformance suite, the tests below cover
+// what is particular to the in-memory store.
+
+// TestCopies tests the entities read and written are not shared with the store
+func TestCopies(t *testing.T) {
+	mdb := NewDB()
+
+	s := model.NewStudent("s1", "alice", "P.4", "", "", "", "")
+	if err := mdb.AddStudent(s); err != nil {
+		t.Fatalf("failed to add student: %v", err)
+	}
+	s.Name = "changed after add"
+
+	got, err := mdb.GetStudent("s1")
+	if err != nil {
+		t.Fatalf("failed to get student: %v", err)
+	}
+	if got.Name != "alice" {
+		t.Errorf("the added student was changed through the caller's copy: %v", got.Name)
+	}
+	got.Name = "changed after get"
+	if got, _ := mdb.GetStudent("s1"); got.Name != "alice" {
+		t.Errorf("the stored student was changed through a read copy: %v", got.Name)
+	}
+}
+
+// TestPageToken tests the page tokens are the offsets of the next page
+func TestPageToken(t *testing.T) {
+	mdb := NewDB()
+	for _, s := range []*model.Student{
+		model.NewStudent("s1", "carol", "", "", "", "", ""),
+		model.NewStudent("s2", "alice", "", "", "", "", ""),
+		model.NewStudent("s3", "bob", "", "", "", "", ""),
+	} {
+		if err := mdb.AddStudent(s); err != nil {
+			t.Fatalf("failed to add student: %v", err)
+		}
+	}
+
+	_, next, err := mdb.GetAllStudent(db.Page{Size: 2})
+	if err != nil {
+		t.Fatalf("failed to get the first page: %v", err)
+	}
+	if next != db.OffsetToken(2) {
+		t.Errorf("expected the token of offset 2 got %q", next)
+	}
+	list, next, err := mdb.GetAllStudent(db.Page{Size: 2, Token: db.OffsetToken(1)})
+	if err != nil {
+		t.Fatalf("failed to get the page at offset 1: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "bob" || list[1].Name != "carol" || next != "" {
+		t.Errorf("unexpected page at offset 1 %v %q", list, next)
+	}
+}
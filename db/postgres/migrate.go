@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationFiles are the versioned schema changes, named <version>_<description>.sql. A migration is
+// never changed once released, the schema is changed by adding one with the next version.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLock is the key of the advisory lock held while migrating, so servers starting at
+// the same time do not apply a migration twice
+const migrationLock = 5613
+
+// migration is a single schema change
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// migrations reads the migrations ordered by version
+func migrations() ([]migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]migration, 0, len(entries))
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".sql")
+		prefix := strings.SplitN(name, "_", 2)[0]
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %v does not start with a version", e.Name())
+		}
+		b, err := migrationFiles.ReadFile(path.Join("migrations", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, migration{version: version, name: name, sql: string(b)})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].version < list[j].version })
+	for i := 1; i < len(list); i++ {
+		if list[i].version == list[i-1].version {
+			return nil, fmt.Errorf("migrations %v and %v have the same version", list[i-1].name, list[i].name)
+		}
+	}
+	return list, nil
+}
+
+// migrate applies the migrations the database has not seen yet in a single transaction, and returns
+// the names of those applied
+func migrate(conn *sql.DB) ([]string, error) {
+	list, err := migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLock); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migration (
+		version INTEGER PRIMARY KEY,
+		name    TEXT NOT NULL,
+		applied TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	var current int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migration").Scan(&current); err != nil {
+		return nil, err
+	}
+
+	applied := make([]string, 0)
+	for _, m := range list {
+		if m.version <= current {
+			continue
+		}
+		if _, err := tx.Exec(m.sql); err != nil {
+			return nil, fmt.Errorf("migration %v failed: %w", m.name, err)
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migration (version, name) VALUES ($1, $2)", m.version, m.name); err != nil {
+			return nil, err
+		}
+		applied = append(applied, m.name)
+	}
+	return applied, tx.Commit()
+}
@@ -0,0 +1,128 @@
+-- Every entity is stored as json in data, next to the columns it is filtered, ordered and referred to by.
+-- Ids and sorted names use the "C" collation so they are ordered byte by byte like datastore keys.
+
+CREATE TABLE merchant (
+	id   TEXT COLLATE "C" PRIMARY KEY,
+	data JSONB NOT NULL
+);
+
+CREATE TABLE role (
+	organisation_id TEXT COLLATE "C" NOT NULL,
+	user_id         TEXT COLLATE "C" NOT NULL,
+	data            JSONB NOT NULL,
+	PRIMARY KEY (organisation_id, user_id)
+);
+
+CREATE TABLE kym (
+	id               TEXT COLLATE "C" PRIMARY KEY,
+	status           TEXT NOT NULL,
+	datetime_created TIMESTAMPTZ NOT NULL,
+	data             JSONB NOT NULL
+);
+
+CREATE INDEX kym_status ON kym (status, datetime_created DESC);
+
+CREATE TABLE main_subject (
+	id   TEXT COLLATE "C" PRIMARY KEY,
+	data JSONB NOT NULL
+);
+
+CREATE TABLE subject (
+	id              TEXT COLLATE "C" PRIMARY KEY,
+	main_subject_id TEXT COLLATE "C" NOT NULL REFERENCES main_subject (id),
+	min_of_student  INTEGER NOT NULL,
+	data            JSONB NOT NULL
+);
+
+CREATE INDEX subject_main_subject ON subject (main_subject_id);
+
+-- search_names holds the trimmed lowercase first, nick and last name the teachers are searched by
+CREATE TABLE teacher (
+	id              TEXT COLLATE "C" PRIMARY KEY,
+	main_subject_id TEXT COLLATE "C" NOT NULL REFERENCES main_subject (id),
+	first_name      TEXT COLLATE "C" NOT NULL,
+	nick_name       TEXT COLLATE "C" NOT NULL,
+	last_name       TEXT COLLATE "C" NOT NULL,
+	search_names    TEXT[] NOT NULL,
+	data            JSONB NOT NULL
+);
+
+CREATE INDEX teacher_main_subject ON teacher (main_subject_id);
+
+CREATE TABLE teacher_responsibility (
+	teacher_id TEXT COLLATE "C" NOT NULL REFERENCES teacher (id) ON DELETE CASCADE,
+	subject_id TEXT COLLATE "C" NOT NULL REFERENCES subject (id),
+	data       JSONB NOT NULL,
+	PRIMARY KEY (teacher_id, subject_id)
+);
+
+CREATE INDEX teacher_responsibility_subject ON teacher_responsibility (subject_id);
+
+CREATE TABLE bell_schedule (
+	id         TEXT COLLATE "C" PRIMARY KEY,
+	is_default BOOLEAN NOT NULL,
+	data       JSONB NOT NULL
+);
+
+CREATE TABLE term (
+	id         TEXT COLLATE "C" PRIMARY KEY,
+	start_date TIMESTAMPTZ NOT NULL,
+	data       JSONB NOT NULL
+);
+
+CREATE TABLE holiday (
+	id         TEXT COLLATE "C" PRIMARY KEY,
+	start_date TIMESTAMPTZ NOT NULL,
+	data       JSONB NOT NULL
+);
+
+CREATE TABLE room (
+	id   TEXT COLLATE "C" PRIMARY KEY,
+	data JSONB NOT NULL
+);
+
+CREATE TABLE student (
+	id   TEXT COLLATE "C" PRIMARY KEY,
+	name TEXT COLLATE "C" NOT NULL,
+	data JSONB NOT NULL
+);
+
+-- the bell schedule and term are NULL for confirmations which do not name one
+CREATE TABLE confirmation (
+	id               TEXT COLLATE "C" PRIMARY KEY,
+	bell_schedule_id TEXT COLLATE "C" REFERENCES bell_schedule (id),
+	term_id          TEXT COLLATE "C" REFERENCES term (id),
+	data             JSONB NOT NULL
+);
+
+-- student_id is not a foreign key, details stored before students existed only have a name and
+-- student_key holds the id or that name, see model.ConfirmationDetail.StudentKey
+CREATE TABLE confirmation_detail (
+	id              TEXT COLLATE "C" PRIMARY KEY,
+	confirmation_id TEXT COLLATE "C" NOT NULL REFERENCES confirmation (id),
+	student_id      TEXT COLLATE "C" NOT NULL,
+	student_key     TEXT COLLATE "C" NOT NULL,
+	day             TEXT NOT NULL,
+	period          INTEGER NOT NULL,
+	data            JSONB NOT NULL
+);
+
+CREATE INDEX confirmation_detail_slot ON confirmation_detail (confirmation_id, day, period, student_key);
+CREATE INDEX confirmation_detail_student ON confirmation_detail (student_id);
+
+CREATE TABLE schedule (
+	id      TEXT COLLATE "C" PRIMARY KEY,
+	created TIMESTAMPTZ NOT NULL,
+	data    JSONB NOT NULL
+);
+
+CREATE TABLE schedule_slot (
+	id            TEXT COLLATE "C" PRIMARY KEY,
+	schedule_id   TEXT COLLATE "C" NOT NULL REFERENCES schedule (id),
+	teacher_id    TEXT COLLATE "C" NOT NULL,
+	student_names TEXT[] NOT NULL,
+	student_ids   TEXT[] NOT NULL,
+	data          JSONB NOT NULL
+);
+
+CREATE INDEX schedule_slot_schedule ON schedule_slot (schedule_id, teacher_id);
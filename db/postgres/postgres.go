@@ -0,0 +1,1369 @@
+// Package postgres keeps the application data in PostgreSQL. It implements the interfaces of the db
+// package with the same conflict and not-found semantics as db.AppDatastore, for schools which cannot
+// use Google Cloud. The schema is created and upgraded by the migrations in the migrations directory.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// DB is the PostgreSQL implementation of db.Store
+type DB struct {
+	conn *sql.DB
+}
+
+var _ db.Store = (*DB)(nil)
+
+// NewDB connects to the database at dataSourceName, a postgres:// url or lib/pq connection string,
+// and applies the migrations it has not seen yet
+func NewDB(dataSourceName string) (*DB, error) {
+	conn, err := sql.Open("postgres", dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	// context with connection timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to communicate to postgres: %w", err)
+	}
+
+	if _, err := migrate(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to migrate postgres: %w", err)
+	}
+	return &DB{conn}, nil
+}
+
+// Close is needed to close the connection pool
+func (pdb *DB) Close() error {
+	return pdb.conn.Close()
+}
+
+// ----- Transactions -------------------------------------------------------------
+
+// maxAttempts is how often a transaction is run before giving up, the default of datastore's RunInTransaction
+const maxAttempts = 3
+
+// runInTransaction runs f in a serializable transaction. Like datastore's RunInTransaction the
+// transaction is retried when it conflicts with a concurrent one, so f may run more than once.
+func (pdb *DB) runInTransaction(f func(tx *sql.Tx) error) error {
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var tx *sql.Tx
+		tx, err = pdb.conn.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+		if err != nil {
+			return err
+		}
+		if err = f(tx); err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+		if !isConcurrentTransaction(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// isConcurrentTransaction reports whether err is a serialization failure or deadlock, after which the
+// transaction succeeds when it is run again
+func isConcurrentTransaction(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == "40001" || pqErr.Code == "40P01")
+}
+
+// ----- Tables ---------------------------------------------------------------------
+
+// table describes how a kind is stored. The entity is kept as json in the data column, next to the
+// columns it is filtered, ordered and referred to by.
+type table struct {
+	name string
+	// keys are the primary key columns
+	keys []string
+	// columns are the other columns besides data
+	columns []string
+	// values returns the values of the keys followed by those of the columns
+	values func(e interface{}) []interface{}
+}
+
+var (
+	merchantTable = table{"merchant", []string{"id"}, nil, func(e interface{}) []interface{} {
+		return []interface{}{e.(*model.Merchant).MerchantID}
+	}}
+	roleTable = table{"role", []string{"organisation_id", "user_id"}, nil, func(e interface{}) []interface{} {
+		r := e.(*model.Role)
+		return []interface{}{r.OrganisationID, r.UserID}
+	}}
+	kymTable = table{"kym", []string{"id"}, []string{"status", "datetime_created"}, func(e interface{}) []interface{} {
+		k := e.(*model.Kym)
+		return []interface{}{k.ID, k.Status, k.DatetimeCreated}
+	}}
+	mainSubjectTable = table{"main_subject", []string{"id"}, nil, func(e interface{}) []interface{} {
+		return []interface{}{e.(*model.MainSubject).ID}
+	}}
+	subjectTable = table{"subject", []string{"id"}, []string{"main_subject_id", "min_of_student"}, func(e interface{}) []interface{} {
+		s := e.(*model.Subject)
+		return []interface{}{s.ID, s.MainSubjectId, s.MinOfStudent}
+	}}
+	teacherTable = table{"teacher", []string{"id"}, []string{"main_subject_id", "first_name", "nick_name", "last_name", "search_names"},
+		func(e interface{}) []interface{} {
+			t := e.(*model.Teacher)
+			return []interface{}{t.ID, t.MainSubjectID, t.FirstName, t.NickName, t.LastName,
+				pq.StringArray(searchNames(t.FirstName, t.NickName, t.LastName))}
+		}}
+	responsibilityTable = table{"teacher_responsibility", []string{"teacher_id", "subject_id"}, nil, func(e interface{}) []interface{} {
+		tr := e.(*model.TeacherResponsibility)
+		return []interface{}{tr.TeacherId, tr.SubjectId}
+	}}
+	bellScheduleTable = table{"bell_schedule", []string{"id"}, []string{"is_default"}, func(e interface{}) []interface{} {
+		b := e.(*model.BellSchedule)
+		return []interface{}{b.ID, b.Default}
+	}}
+	termTable = table{"term", []string{"id"}, []string{"start_date"}, func(e interface{}) []interface{} {
+		t := e.(*model.Term)
+		return []interface{}{t.ID, t.StartDate}
+	}}
+	holidayTable = table{"holiday", []string{"id"}, []string{"start_date"}, func(e interface{}) []interface{} {
+		h := e.(*model.Holiday)
+		return []interface{}{h.ID, h.StartDate}
+	}}
+	roomTable = table{"room", []string{"id"}, nil, func(e interface{}) []interface{} {
+		return []interface{}{e.(*model.Room).ID}
+	}}
+	studentTable = table{"student", []string{"id"}, []string{"name"}, func(e interface{}) []interface{} {
+		s := e.(*model.Student)
+		return []interface{}{s.ID, s.Name}
+	}}
+	confirmationTable = table{"confirmation", []string{"id"}, []string{"bell_schedule_id", "term_id"}, func(e interface{}) []interface{} {
+		m := e.(*model.Confirmation)
+		return []interface{}{m.ID, nullIfEmpty(m.BellScheduleID), nullIfEmpty(m.TermID)}
+	}}
+	confirmationDetailTable = table{"confirmation_detail", []string{"id"}, []string{"confirmation_id", "student_id", "student_key", "day", "period"},
+		func(e interface{}) []interface{} {
+			cd := e.(*model.ConfirmationDetail)
+			return []interface{}{cd.ID, cd.ConfirmationID, cd.StudentID, cd.StudentKey(), string(cd.Day), int(cd.Period)}
+		}}
+	scheduleTable = table{"schedule", []string{"id"}, []string{"created"}, func(e interface{}) []interface{} {
+		s := e.(*model.Schedule)
+		return []interface{}{s.ID, s.Created}
+	}}
+	scheduleSlotTable = table{"schedule_slot", []string{"id"}, []string{"schedule_id", "teacher_id", "student_names", "student_ids"},
+		func(e interface{}) []interface{} {
+			s := e.(*model.ScheduleSlot)
+			return []interface{}{s.ID, s.ScheduleID, s.TeacherID, pq.StringArray(nonNil(s.StudentNames)), pq.StringArray(nonNil(s.StudentIDs))}
+		}}
+)
+
+// searchNames are the names teachers are searched by, trimmed and in lowercase
+func searchNames(names ...string) []string {
+	res := make([]string, len(names))
+	for i, name := range names {
+		res[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	return res
+}
+
+func nullIfEmpty(s string) interface{} {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+func nonNil(list []string) []string {
+	if list == nil {
+		return make([]string, 0)
+	}
+	return list
+}
+
+// ----- Entity access ----------------------------------------------------------------
+
+// queryer is implemented by both *sql.DB and *sql.Tx, reads run inside or outside of a transaction
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// entityArgs returns the column values of e followed by e as json
+func (t table) entityArgs(e interface{}) ([]interface{}, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return append(t.values(e), data), nil
+}
+
+// allColumns lists the keys, the columns and data in the order of entityArgs
+func (t table) allColumns() []string {
+	res := append(append(make([]string, 0, len(t.keys)+len(t.columns)+1), t.keys...), t.columns...)
+	return append(res, "data")
+}
+
+// keyCondition matches the primary key given as the first arguments
+func (t table) keyCondition() string {
+	conditions := make([]string, len(t.keys))
+	for i, k := range t.keys {
+		conditions[i] = fmt.Sprintf("%v = $%d", k, i+1)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+func placeholders(n int) string {
+	res := make([]string, n)
+	for i := range res {
+		res[i] = fmt.Sprintf("$%d", i+1)
+	}
+	return strings.Join(res, ", ")
+}
+
+// insert adds a new entity, failing with c.ErrDBEntityAlreadyExists when its key is taken
+func insert(q queryer, t table, e interface{}) error {
+	args, err := t.entityArgs(e)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %v (%v) VALUES (%v) ON CONFLICT (%v) DO NOTHING",
+		t.name, strings.Join(t.allColumns(), ", "), placeholders(len(args)), strings.Join(t.keys, ", "))
+	return expectRow(q.Exec(query, args...))(c.ErrDBEntityAlreadyExists)
+}
+
+// update replaces an existing entity, failing with c.ErrDBNoSuchEntity when there is none
+func update(q queryer, t table, e interface{}) error {
+	args, err := t.entityArgs(e)
+	if err != nil {
+		return err
+	}
+	columns := append(append(make([]string, 0), t.columns...), "data")
+	set := make([]string, len(columns))
+	for i, col := range columns {
+		set[i] = fmt.Sprintf("%v = $%d", col, len(t.keys)+i+1)
+	}
+	query := fmt.Sprintf("UPDATE %v SET %v WHERE %v", t.name, strings.Join(set, ", "), t.keyCondition())
+	return expectRow(q.Exec(query, args...))(c.ErrDBNoSuchEntity)
+}
+
+// upsert stores the entity, replacing the one stored under its key like a datastore put
+func upsert(q queryer, t table, e interface{}) error {
+	args, err := t.entityArgs(e)
+	if err != nil {
+		return err
+	}
+	columns := append(append(make([]string, 0), t.columns...), "data")
+	set := make([]string, len(columns))
+	for i, col := range columns {
+		set[i] = fmt.Sprintf("%v = EXCLUDED.%v", col, col)
+	}
+	query := fmt.Sprintf("INSERT INTO %v (%v) VALUES (%v) ON CONFLICT (%v) DO UPDATE SET %v",
+		t.name, strings.Join(t.allColumns(), ", "), placeholders(len(args)), strings.Join(t.keys, ", "), strings.Join(set, ", "))
+	_, err = q.Exec(query, args...)
+	return err
+}
+
+// remove deletes an existing entity by key, failing with c.ErrDBNoSuchEntity when there is none
+func remove(q queryer, t table, key ...interface{}) error {
+	query := fmt.Sprintf("DELETE FROM %v WHERE %v", t.name, t.keyCondition())
+	return expectRow(q.Exec(query, key...))(c.ErrDBNoSuchEntity)
+}
+
+// expectRow returns a function failing with errNone when the statement did not affect any row
+func expectRow(res sql.Result, err error) func(errNone error) error {
+	return func(errNone error) error {
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errNone
+		}
+		return nil
+	}
+}
+
+// get loads an entity by key into dst, failing with c.ErrDBNoSuchEntity when there is none
+func get(q queryer, t table, dst interface{}, key ...interface{}) error {
+	var data []byte
+	query := fmt.Sprintf("SELECT data FROM %v WHERE %v", t.name, t.keyCondition())
+	switch err := q.QueryRow(query, key...).Scan(&data); err {
+	case nil:
+		return json.Unmarshal(data, dst)
+	case sql.ErrNoRows:
+		return c.ErrDBNoSuchEntity
+	default:
+		return err
+	}
+}
+
+// exists reports whether an entity with the key is stored
+func exists(q queryer, t table, key ...interface{}) (bool, error) {
+	var found bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %v WHERE %v)", t.name, t.keyCondition())
+	err := q.QueryRow(query, key...).Scan(&found)
+	return found, err
+}
+
+// count counts the rows the query selects
+func count(q queryer, query string, args ...interface{}) (int, error) {
+	var n int
+	err := q.QueryRow("SELECT COUNT(*) FROM ("+query+") AS counted", args...).Scan(&n)
+	return n, err
+}
+
+// getAll loads the data selected by the query into dst, which must be a pointer to a slice of entity pointers
+func getAll(q queryer, dst interface{}, query string, args ...interface{}) error {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	list := reflect.ValueOf(dst).Elem()
+	entityType := list.Type().Elem().Elem()
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		entity := reflect.New(entityType)
+		if err := json.Unmarshal(data, entity.Interface()); err != nil {
+			return err
+		}
+		list.Set(reflect.Append(list, entity))
+	}
+	return rows.Err()
+}
+
+// getPage loads a single page of the ordered query into dst, which must be a pointer to a slice of entity pointers.
+// The returned token is the offset of the next page and is empty on the last page.
+func getPage(q queryer, page db.Page, dst interface{}, query string, args ...interface{}) (string, error) {
+	offset, err := db.ParseOffsetToken(page.Token)
+	if err != nil {
+		return "", err
+	}
+
+	// one entity more than the page size is read to find out whether another page follows
+	query = fmt.Sprintf("%v LIMIT $%d OFFSET $%d", query, len(args)+1, len(args)+2)
+	if err := getAll(q, dst, query, append(args, page.Size+1, offset)...); err != nil {
+		return "", err
+	}
+
+	list := reflect.ValueOf(dst).Elem()
+	if list.Len() <= page.Size {
+		return "", nil
+	}
+	list.Set(list.Slice(0, page.Size))
+	return db.OffsetToken(offset + page.Size), nil
+}
+
+// conditions collects the WHERE clause of a query and its arguments
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// add adds the clause, $? in it is replaced by the placeholder of the argument
+func (w *conditions) add(clause string, arg interface{}) {
+	w.args = append(w.args, arg)
+	w.clauses = append(w.clauses, strings.ReplaceAll(clause, "$?", fmt.Sprintf("$%d", len(w.args))))
+}
+
+func (w *conditions) String() string {
+	if len(w.clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.clauses, " AND ")
+}
+
+// ----- Merchants, roles and kym -------------------------------------------------------
+
+// GetMerchant returns the Merchant given the ID
+func (pdb *DB) GetMerchant(merchantID string) (*model.Merchant, error) {
+	m := &model.Merchant{}
+	if err := get(pdb.conn, merchantTable, m, merchantID); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AddMerchant adds a new Merchant, merchantID == organisationID so organisations are not added twice
+func (pdb *DB) AddMerchant(m *model.Merchant) error {
+	return insert(pdb.conn, merchantTable, m)
+}
+
+// UpdateMerchant updates the existing Merchant with the new properties
+func (pdb *DB) UpdateMerchant(m *model.Merchant) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		old := &model.Merchant{}
+		if err := get(tx, merchantTable, old, m.MerchantID); err != nil {
+			return err
+		}
+		if err := old.CheckUpdate(m); err != nil {
+			return err
+		}
+
+		// preserve other fields and update the last updated timestamp
+		m.Updated = time.Now()
+		m.Created = old.Created
+		m.PayOutConfig = old.PayOutConfig
+		return update(tx, merchantTable, m)
+	})
+}
+
+// UpsertPayOutConfig Merchant's PayOutConfig to be updated or inserted
+func (pdb *DB) UpsertPayOutConfig(merchantID string, poc *model.PayOutConfig) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		old := &model.Merchant{}
+		if err := get(tx, merchantTable, old, merchantID); err != nil {
+			return err
+		}
+		old.PayOutConfig = poc
+		old.Updated = time.Now()
+		return update(tx, merchantTable, old)
+	})
+}
+
+// GetRole retrieves an organisation role by ID
+func (pdb *DB) GetRole(organisationID, userID string) (*model.Role, error) {
+	role := &model.Role{}
+	if err := get(pdb.conn, roleTable, role, organisationID, userID); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// AddRole stores a role, a user has a single role in each organisation
+func (pdb *DB) AddRole(role *model.Role) error {
+	return insert(pdb.conn, roleTable, role)
+}
+
+// AddKym adds a new Kym
+func (pdb *DB) AddKym(kym *model.Kym) error {
+	return insert(pdb.conn, kymTable, kym)
+}
+
+// GetAllKym gets a page of kym with the status, newest first. An empty status is not filtered on.
+func (pdb *DB) GetAllKym(status string, page db.Page) ([]*model.Kym, string, error) {
+	w := &conditions{}
+	if len(status) > 0 {
+		if !c.IsValidKymStatus(status) {
+			return nil, "", &c.ErrValidation{Violations: fmt.Errorf("filter is not allowed with status : %v", status)}
+		}
+		w.add("status = $?", status)
+	}
+
+	list := make([]*model.Kym, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM kym"+w.String()+" ORDER BY datetime_created DESC, id", w.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetKym gets a single kym by id
+func (pdb *DB) GetKym(id string) (*model.Kym, error) {
+	kym := &model.Kym{}
+	if err := get(pdb.conn, kymTable, kym, id); err != nil {
+		return nil, err
+	}
+	return kym, nil
+}
+
+// UpdateKymStatus sets the status and notes of an existing kym
+func (pdb *DB) UpdateKymStatus(kym *model.Kym, status string, notes string) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		found, err := exists(tx, kymTable, kym.ID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return c.ErrDBNoSuchEntity
+		}
+		kym.Status = status
+		kym.Notes = notes
+		return update(tx, kymTable, kym)
+	})
+}
+
+// ----- Teachers and responsibilities ----------------------------------------------------
+
+// AddTeacher adds a new Teacher, its main subject has to exist
+func (pdb *DB) AddTeacher(m *model.Teacher) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		if err := checkMainSubjectExists(tx, m.MainSubjectID); err != nil {
+			return err
+		}
+		return insert(tx, teacherTable, m)
+	})
+}
+
+// GetAllTeacher gets a page of the teachers matching tq
+func (pdb *DB) GetAllTeacher(tq db.TeacherQuery, page db.Page) ([]*model.Teacher, string, error) {
+	w := &conditions{}
+	if len(tq.MainSubjectID) > 0 {
+		w.add("main_subject_id = $?", tq.MainSubjectID)
+	}
+	if q := strings.ToLower(strings.TrimSpace(tq.Q)); len(q) > 0 {
+		w.add("EXISTS (SELECT 1 FROM unnest(search_names) AS name WHERE starts_with(name, $?))", q)
+	}
+	order := "id"
+	switch tq.Sort {
+	case db.TeacherSortFirstName:
+		order = "first_name, id"
+	case db.TeacherSortNickName:
+		order = "nick_name, id"
+	case db.TeacherSortLastName:
+		order = "last_name, id"
+	}
+
+	list := make([]*model.Teacher, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM teacher"+w.String()+" ORDER BY "+order, w.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetTeacher gets a single teacher by id
+func (pdb *DB) GetTeacher(id string) (*model.Teacher, error) {
+	t := &model.Teacher{}
+	if err := get(pdb.conn, teacherTable, t, id); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UpdateTeacherAvailability replaces the availability of an existing teacher
+func (pdb *DB) UpdateTeacherAvailability(id string, availability []model.AvailabilityWindow, exceptions []model.AvailabilityException) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		old := &model.Teacher{}
+		if err := get(tx, teacherTable, old, id); err != nil {
+			return err
+		}
+		old.Availability = availability
+		old.AvailabilityExceptions = exceptions
+		return update(tx, teacherTable, old)
+	})
+}
+
+// UpdateTeacher replaces an existing teacher, its main subject has to exist
+func (pdb *DB) UpdateTeacher(m *model.Teacher) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		if err := checkMainSubjectExists(tx, m.MainSubjectID); err != nil {
+			return err
+		}
+		return update(tx, teacherTable, m)
+	})
+}
+
+// DeleteTeacher removes an existing teacher, its responsibilities are removed by the foreign key
+func (pdb *DB) DeleteTeacher(id string) error {
+	return remove(pdb.conn, teacherTable, id)
+}
+
+// AddTeacherResponsibility qualifies a teacher for a subject.
+// Both the teacher and the subject need to exist, and a teacher can only be qualified once per subject.
+func (pdb *DB) AddTeacherResponsibility(tr *model.TeacherResponsibility) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		found, err := exists(tx, teacherTable, tr.TeacherId)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return c.ErrDBNoSuchEntity
+		}
+		if err := checkSubjectsExist(tx, []string{tr.SubjectId}); err != nil {
+			return &c.ErrValidation{Violations: fmt.Errorf("subject %v does not exist", tr.SubjectId)}
+		}
+		return insert(tx, responsibilityTable, tr)
+	})
+}
+
+// GetAllTeacherResponsibility gets a page of teacher qualifications ordered by teacher and subject.
+// Empty teacherID or subjectID values are not filtered on.
+func (pdb *DB) GetAllTeacherResponsibility(teacherID string, subjectID string, page db.Page) ([]*model.TeacherResponsibility, string, error) {
+	w := &conditions{}
+	if len(teacherID) > 0 {
+		w.add("teacher_id = $?", teacherID)
+	}
+	if len(subjectID) > 0 {
+		w.add("subject_id = $?", subjectID)
+	}
+
+	list := make([]*model.TeacherResponsibility, 0)
+	query := "SELECT data FROM teacher_responsibility" + w.String() + " ORDER BY teacher_id, subject_id"
+	next, err := getPage(pdb.conn, page, &list, query, w.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// ReplaceTeacherResponsibility replaces all qualifications of a teacher in a single transaction, a subject
+// listed more than once is stored once
+func (pdb *DB) ReplaceTeacherResponsibility(teacherID string, subjectIDs []string) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		found, err := exists(tx, teacherTable, teacherID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return c.ErrDBNoSuchEntity
+		}
+		list := model.NewTeacherResponsibilities(teacherID, subjectIDs)
+		ids := make([]string, len(list))
+		for i, tr := range list {
+			ids[i] = tr.SubjectId
+		}
+		if err := checkSubjectsExist(tx, ids); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("DELETE FROM teacher_responsibility WHERE teacher_id = $1", teacherID); err != nil {
+			return err
+		}
+		for _, tr := range list {
+			if err := insert(tx, responsibilityTable, tr); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteTeacherResponsibility removes a single qualification of a teacher
+func (pdb *DB) DeleteTeacherResponsibility(teacherID string, subjectID string) error {
+	return remove(pdb.conn, responsibilityTable, teacherID, subjectID)
+}
+
+// GetAllTeacherBySubject gets a page of the teachers qualified for a subject, either through their main
+// subject or an explicit responsibility, ordered by teacher id. An unknown subject fails with c.ErrDBNoSuchEntity.
+func (pdb *DB) GetAllTeacherBySubject(subjectID string, page db.Page) ([]*model.Teacher, string, error) {
+	found, err := exists(pdb.conn, subjectTable, subjectID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !found {
+		return nil, "", c.ErrDBNoSuchEntity
+	}
+	query := `SELECT t.data FROM teacher t
+		WHERE t.main_subject_id = (SELECT s.main_subject_id FROM subject s WHERE s.id = $1)
+		OR EXISTS (SELECT 1 FROM teacher_responsibility r WHERE r.teacher_id = t.id AND r.subject_id = $1)
+		ORDER BY t.id`
+	list := make([]*model.Teacher, 0)
+	next, err := getPage(pdb.conn, page, &list, query, subjectID)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetAllSubjectByTeacher gets a page of the subjects a teacher is qualified for, either through the
+// teacher's main subject or an explicit responsibility, ordered by subject id. An unknown teacher fails with
+// c.ErrDBNoSuchEntity.
+func (pdb *DB) GetAllSubjectByTeacher(teacherID string, page db.Page) ([]*model.Subject, string, error) {
+	found, err := exists(pdb.conn, teacherTable, teacherID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !found {
+		return nil, "", c.ErrDBNoSuchEntity
+	}
+	query := `SELECT s.data FROM subject s
+		WHERE s.main_subject_id = (SELECT t.main_subject_id FROM teacher t WHERE t.id = $1)
+		OR EXISTS (SELECT 1 FROM teacher_responsibility r WHERE r.subject_id = s.id AND r.teacher_id = $1)
+		ORDER BY s.id`
+	list := make([]*model.Subject, 0)
+	next, err := getPage(pdb.conn, page, &list, query, teacherID)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// ----- Main subjects and subjects -------------------------------------------------------
+
+// AddMainSubject adds a new MainSubject
+func (pdb *DB) AddMainSubject(m *model.MainSubject) error {
+	return insert(pdb.conn, mainSubjectTable, m)
+}
+
+// GetAllMainSubject gets a page of main subjects
+func (pdb *DB) GetAllMainSubject(page db.Page) ([]*model.MainSubject, string, error) {
+	list := make([]*model.MainSubject, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM main_subject ORDER BY id")
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetMainSubject gets a single main subject by id
+func (pdb *DB) GetMainSubject(id string) (*model.MainSubject, error) {
+	m := &model.MainSubject{}
+	if err := get(pdb.conn, mainSubjectTable, m, id); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UpdateMainSubject replaces an existing main subject
+func (pdb *DB) UpdateMainSubject(m *model.MainSubject) error {
+	return update(pdb.conn, mainSubjectTable, m)
+}
+
+// DeleteMainSubject removes an existing main subject.
+// Subjects and teachers referring to the main subject block the delete with c.ErrConflict. When cascade
+// is set they are removed in the same transaction, together with the responsibilities attached to them,
+// unless confirmation details ask for one of the subjects.
+func (pdb *DB) DeleteMainSubject(id string, cascade bool) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		found, err := exists(tx, mainSubjectTable, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return c.ErrDBNoSuchEntity
+		}
+
+		used, err := count(tx, `SELECT id FROM subject WHERE main_subject_id = $1
+			UNION ALL SELECT id FROM teacher WHERE main_subject_id = $1`, id)
+		if err != nil {
+			return err
+		}
+		if used > 0 && !cascade {
+			return fmt.Errorf("%w: main subject %v is still used by %d subjects and teachers", c.ErrConflict, id, used)
+		}
+		asked, err := count(tx, `SELECT 1 FROM confirmation_detail
+			WHERE data->'SubjectDetailID' ?| ARRAY(SELECT id FROM subject WHERE main_subject_id = $1)`, id)
+		if err != nil {
+			return err
+		}
+		if asked > 0 {
+			return fmt.Errorf("%w: subjects of main subject %v are still asked for by %d confirmation details", c.ErrConflict, id, asked)
+		}
+
+		// the responsibilities of the teachers are removed by the foreign key
+		statements := []string{
+			"DELETE FROM teacher_responsibility WHERE subject_id IN (SELECT id FROM subject WHERE main_subject_id = $1)",
+			"DELETE FROM teacher WHERE main_subject_id = $1",
+			"DELETE FROM subject WHERE main_subject_id = $1",
+			"DELETE FROM main_subject WHERE id = $1",
+		}
+		for _, s := range statements {
+			if _, err := tx.Exec(s, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddSubject adds a new Subject, its main subject has to exist
+func (pdb *DB) AddSubject(m *model.Subject) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		if err := checkMainSubjectExists(tx, m.MainSubjectId); err != nil {
+			return err
+		}
+		return insert(tx, subjectTable, m)
+	})
+}
+
+// GetAllSubject gets a page of the subjects matching sq. Like a datastore query with an inequality
+// filter, filtering on the minimum number of students orders the subjects by it.
+func (pdb *DB) GetAllSubject(sq db.SubjectQuery, page db.Page) ([]*model.Subject, string, error) {
+	w := &conditions{}
+	order := "id"
+	if len(sq.MainSubjectID) > 0 {
+		w.add("main_subject_id = $?", sq.MainSubjectID)
+	}
+	if sq.MinStudentsGte > 0 {
+		w.add("min_of_student >= $?", sq.MinStudentsGte)
+		order = "min_of_student, id"
+	}
+
+	list := make([]*model.Subject, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM subject"+w.String()+" ORDER BY "+order, w.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetSubject gets a single subject by id
+func (pdb *DB) GetSubject(id string) (*model.Subject, error) {
+	m := &model.Subject{}
+	if err := get(pdb.conn, subjectTable, m, id); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UpdateSubject replaces an existing subject, its main subject has to exist
+func (pdb *DB) UpdateSubject(m *model.Subject) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		if err := checkMainSubjectExists(tx, m.MainSubjectId); err != nil {
+			return err
+		}
+		return update(tx, subjectTable, m)
+	})
+}
+
+// DeleteSubject removes an existing subject.
+// Teacher responsibilities for the subject block the delete with c.ErrConflict, unless cascade is set
+// in which case they are removed in the same transaction. Confirmation details asking for the subject
+// always block it, they are never cascaded.
+func (pdb *DB) DeleteSubject(id string, cascade bool) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		found, err := exists(tx, subjectTable, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return c.ErrDBNoSuchEntity
+		}
+
+		asked, err := count(tx, "SELECT 1 FROM confirmation_detail WHERE data->'SubjectDetailID' ? $1", id)
+		if err != nil {
+			return err
+		}
+		if asked > 0 {
+			return fmt.Errorf("%w: subject %v is still asked for by %d confirmation details", c.ErrConflict, id, asked)
+		}
+
+		assigned, err := count(tx, "SELECT 1 FROM teacher_responsibility WHERE subject_id = $1", id)
+		if err != nil {
+			return err
+		}
+		if assigned > 0 && !cascade {
+			return fmt.Errorf("%w: subject %v is still assigned to %d teachers", c.ErrConflict, id, assigned)
+		}
+
+		if _, err := tx.Exec("DELETE FROM teacher_responsibility WHERE subject_id = $1", id); err != nil {
+			return err
+		}
+		return remove(tx, subjectTable, id)
+	})
+}
+
+// checkMainSubjectExists returns a validation error when the main subject referred to does not exist
+func checkMainSubjectExists(q queryer, id string) error {
+	found, err := exists(q, mainSubjectTable, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return &c.ErrValidation{Violations: fmt.Errorf("main subject %v does not exist", id)}
+	}
+	return nil
+}
+
+// checkSubjectsExist fails with c.ErrValidation listing the subjects which do not exist
+func checkSubjectsExist(q queryer, ids []string) error {
+	missing := make([]string, 0)
+	for _, id := range ids {
+		found, err := exists(q, subjectTable, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return &c.ErrValidation{Violations: fmt.Errorf("subjects %v do not exist", missing)}
+	}
+	return nil
+}
+
+// ----- Confirmations --------------------------------------------------------------------
+
+// AddConfirmation adds a new Confirmation, its bell schedule and term have to exist when set
+func (pdb *DB) AddConfirmation(m *model.Confirmation) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		if len(m.BellScheduleID) > 0 {
+			found, err := exists(tx, bellScheduleTable, m.BellScheduleID)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return &c.ErrValidation{Violations: fmt.Errorf("bell schedule %v does not exist", m.BellScheduleID)}
+			}
+		}
+		if len(m.TermID) > 0 {
+			found, err := exists(tx, termTable, m.TermID)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return &c.ErrValidation{Violations: fmt.Errorf("term %v does not exist", m.TermID)}
+			}
+		}
+		return insert(tx, confirmationTable, m)
+	})
+}
+
+// GetConfirmation gets a single confirmation by id
+func (pdb *DB) GetConfirmation(id string) (*model.Confirmation, error) {
+	m := &model.Confirmation{}
+	if err := get(pdb.conn, confirmationTable, m, id); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetAllConfirmation gets a page of confirmations
+func (pdb *DB) GetAllConfirmation(page db.Page) ([]*model.Confirmation, string, error) {
+	list := make([]*model.Confirmation, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM confirmation ORDER BY id")
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// AddConfirmationDetail adds a detail to its confirmation in a transaction. The confirmation has to accept
+// details and the subjects have to exist, a second detail of the student on the same day and period of the
+// confirmation is rejected. Both fail with c.ErrValidation naming the offending ids.
+func (pdb *DB) AddConfirmationDetail(m *model.ConfirmationDetail) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		return addConfirmationDetail(tx, m)
+	})
+}
+
+// addConfirmationDetail checks and inserts a single detail within the transaction. Details inserted
+// earlier in the transaction are found when looking for duplicates.
+func addConfirmationDetail(tx *sql.Tx, m *model.ConfirmationDetail) error {
+	confirmation := &model.Confirmation{}
+	switch err := get(tx, confirmationTable, confirmation, m.ConfirmationID); err {
+	case nil:
+	case c.ErrDBNoSuchEntity:
+		return &c.ErrValidation{Violations: fmt.Errorf("confirmation %v does not exist", m.ConfirmationID)}
+	default:
+		return err
+	}
+	if err := confirmation.CheckAcceptsDetails(); err != nil {
+		return err
+	}
+	if err := checkSubjectsExist(tx, m.SubjectDetailID); err != nil {
+		return err
+	}
+
+	var duplicate string
+	err := tx.QueryRow(`SELECT id FROM confirmation_detail
+		WHERE confirmation_id = $1 AND day = $2 AND period = $3 AND student_key = $4 AND id <> $5
+		ORDER BY id LIMIT 1`, m.ConfirmationID, string(m.Day), int(m.Period), m.StudentKey(), m.ID).Scan(&duplicate)
+	switch err {
+	case nil:
+		return &c.ErrValidation{Violations: fmt.Errorf("student %v already has detail %v on %v period %d of confirmation %v",
+			m.StudentKey(), duplicate, m.Day, m.Period, m.ConfirmationID)}
+	case sql.ErrNoRows:
+	default:
+		return err
+	}
+
+	return insert(tx, confirmationDetailTable, m)
+}
+
+// maxBatch is the number of details AddConfirmationDetails writes per transaction, the largest
+// PutMulti of datastore
+const maxBatch = 500
+
+// AddConfirmationDetails adds the confirmation details in batches of a transaction each. Every detail is
+// checked the way AddConfirmationDetail checks it, so a confirmation locked or a duplicate added while
+// the details are written stops the batch. The batches written before a failing one are kept.
+func (pdb *DB) AddConfirmationDetails(list []*model.ConfirmationDetail) error {
+	for start := 0; start < len(list); start += maxBatch {
+		end := start + maxBatch
+		if end > len(list) {
+			end = len(list)
+		}
+		err := pdb.runInTransaction(func(tx *sql.Tx) error {
+			for _, m := range list[start:end] {
+				if err := addConfirmationDetail(tx, m); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAllConfirmationDetail gets a page of the details of a confirmation
+func (pdb *DB) GetAllConfirmationDetail(confirmationId string, page db.Page) ([]*model.ConfirmationDetail, string, error) {
+	list := make([]*model.ConfirmationDetail, 0)
+	query := "SELECT data FROM confirmation_detail WHERE confirmation_id = $1 ORDER BY id"
+	next, err := getPage(pdb.conn, page, &list, query, confirmationId)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// UpdateConfirmationStatus moves a confirmation to the status in a transaction, so concurrent changes
+// cannot skip a step of the lifecycle
+func (pdb *DB) UpdateConfirmationStatus(id string, status model.ConfirmationStatus, changedBy string, changedAt time.Time) (*model.Confirmation, error) {
+	m := &model.Confirmation{}
+	err := pdb.runInTransaction(func(tx *sql.Tx) error {
+		if err := get(tx, confirmationTable, m, id); err != nil {
+			return err
+		}
+		if !m.Status.CanBecome(status) {
+			return fmt.Errorf("%w: confirmation %v cannot become %v while it is %v", c.ErrConflict, id, status, m.Status)
+		}
+		m.Status = status
+		m.StatusChangedBy = changedBy
+		m.StatusChangedAt = changedAt
+		return update(tx, confirmationTable, m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ----- Schedules ---------------------------------------------------------------------
+
+// AddSchedule adds a Schedule and its slots in a single transaction
+func (pdb *DB) AddSchedule(s *model.Schedule, slots []*model.ScheduleSlot) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		if err := insert(tx, scheduleTable, s); err != nil {
+			return err
+		}
+		for _, slot := range slots {
+			if err := upsert(tx, scheduleSlotTable, slot); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetSchedule gets a single schedule by id
+func (pdb *DB) GetSchedule(id string) (*model.Schedule, error) {
+	s := &model.Schedule{}
+	if err := get(pdb.conn, scheduleTable, s, id); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetAllScheduleSlot gets the slots of a schedule. Empty teacherID or studentID values are not filtered on.
+func (pdb *DB) GetAllScheduleSlot(scheduleID string, teacherID string, studentID string) ([]*model.ScheduleSlot, error) {
+	w := &conditions{}
+	w.add("schedule_id = $?", scheduleID)
+	if len(teacherID) > 0 {
+		w.add("teacher_id = $?", teacherID)
+	}
+	if len(studentID) > 0 {
+		w.add("$? = ANY(student_ids)", studentID)
+	}
+
+	list := make([]*model.ScheduleSlot, 0)
+	if err := getAll(pdb.conn, &list, "SELECT data FROM schedule_slot"+w.String()+" ORDER BY id", w.args...); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetAllStudentScheduleSlot gets the slots of a schedule attended by a student
+func (pdb *DB) GetAllStudentScheduleSlot(scheduleID string, studentID string) ([]*model.ScheduleSlot, error) {
+	list := make([]*model.ScheduleSlot, 0)
+	query := "SELECT data FROM schedule_slot WHERE schedule_id = $1 AND $2 = ANY(student_ids) ORDER BY id"
+	if err := getAll(pdb.conn, &list, query, scheduleID, studentID); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ----- Bell schedules, terms and holidays -----------------------------------------------
+
+// AddBellSchedule adds a BellSchedule, a default bell schedule unsets the previous default
+func (pdb *DB) AddBellSchedule(b *model.BellSchedule) error {
+	return pdb.putBellSchedule(b, true)
+}
+
+// GetAllBellSchedule gets a page of bell schedules
+func (pdb *DB) GetAllBellSchedule(page db.Page) ([]*model.BellSchedule, string, error) {
+	list := make([]*model.BellSchedule, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM bell_schedule ORDER BY id")
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetBellSchedule gets a single bell schedule by id
+func (pdb *DB) GetBellSchedule(id string) (*model.BellSchedule, error) {
+	b := &model.BellSchedule{}
+	if err := get(pdb.conn, bellScheduleTable, b, id); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetDefaultBellSchedule gets the bell schedule marked as default
+func (pdb *DB) GetDefaultBellSchedule() (*model.BellSchedule, error) {
+	list := make([]*model.BellSchedule, 0)
+	if err := getAll(pdb.conn, &list, "SELECT data FROM bell_schedule WHERE is_default ORDER BY id LIMIT 1"); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, c.ErrDBNoSuchEntity
+	}
+	return list[0], nil
+}
+
+// UpdateBellSchedule replaces an existing bell schedule, a default bell schedule unsets the previous default
+func (pdb *DB) UpdateBellSchedule(b *model.BellSchedule) error {
+	return pdb.putBellSchedule(b, false)
+}
+
+// putBellSchedule adds the bell schedule when create is set, otherwise replaces the existing one.
+// A default bell schedule unsets the previous default in the same transaction.
+func (pdb *DB) putBellSchedule(b *model.BellSchedule, create bool) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		found, err := exists(tx, bellScheduleTable, b.ID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case found && create:
+			return c.ErrDBEntityAlreadyExists
+		case !found && !create:
+			return c.ErrDBNoSuchEntity
+		}
+
+		if b.Default {
+			previous := make([]*model.BellSchedule, 0)
+			if err := getAll(tx, &previous, "SELECT data FROM bell_schedule WHERE is_default AND id <> $1", b.ID); err != nil {
+				return err
+			}
+			for _, p := range previous {
+				p.Default = false
+				if err := update(tx, bellScheduleTable, p); err != nil {
+					return err
+				}
+			}
+		}
+		return upsert(tx, bellScheduleTable, b)
+	})
+}
+
+// DeleteBellSchedule removes an existing bell schedule.
+// Confirmations referring to the bell schedule block the delete with c.ErrConflict.
+func (pdb *DB) DeleteBellSchedule(id string) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		found, err := exists(tx, bellScheduleTable, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return c.ErrDBNoSuchEntity
+		}
+		used, err := count(tx, "SELECT 1 FROM confirmation WHERE bell_schedule_id = $1", id)
+		if err != nil {
+			return err
+		}
+		if used > 0 {
+			return fmt.Errorf("%w: bell schedule %v is still used by %d confirmations", c.ErrConflict, id, used)
+		}
+		return remove(tx, bellScheduleTable, id)
+	})
+}
+
+// AddTerm adds a new Term
+func (pdb *DB) AddTerm(t *model.Term) error {
+	return insert(pdb.conn, termTable, t)
+}
+
+// GetAllTerm gets a page of terms ordered by start date
+func (pdb *DB) GetAllTerm(page db.Page) ([]*model.Term, string, error) {
+	list := make([]*model.Term, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM term ORDER BY start_date, id")
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetTerm gets a single term by id
+func (pdb *DB) GetTerm(id string) (*model.Term, error) {
+	t := &model.Term{}
+	if err := get(pdb.conn, termTable, t, id); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UpdateTerm replaces an existing term
+func (pdb *DB) UpdateTerm(t *model.Term) error {
+	return update(pdb.conn, termTable, t)
+}
+
+// DeleteTerm removes an existing term.
+// Confirmations referring to the term block the delete with c.ErrConflict.
+func (pdb *DB) DeleteTerm(id string) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		found, err := exists(tx, termTable, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return c.ErrDBNoSuchEntity
+		}
+		used, err := count(tx, "SELECT 1 FROM confirmation WHERE term_id = $1", id)
+		if err != nil {
+			return err
+		}
+		if used > 0 {
+			return fmt.Errorf("%w: term %v is still used by %d confirmations", c.ErrConflict, id, used)
+		}
+		return remove(tx, termTable, id)
+	})
+}
+
+// AddHoliday adds a new Holiday
+func (pdb *DB) AddHoliday(h *model.Holiday) error {
+	return insert(pdb.conn, holidayTable, h)
+}
+
+// GetAllHoliday gets a page of holidays ordered by start date
+func (pdb *DB) GetAllHoliday(page db.Page) ([]*model.Holiday, string, error) {
+	list := make([]*model.Holiday, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM holiday ORDER BY start_date, id")
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetHoliday gets a single holiday by id
+func (pdb *DB) GetHoliday(id string) (*model.Holiday, error) {
+	h := &model.Holiday{}
+	if err := get(pdb.conn, holidayTable, h, id); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// UpdateHoliday replaces an existing holiday
+func (pdb *DB) UpdateHoliday(h *model.Holiday) error {
+	return update(pdb.conn, holidayTable, h)
+}
+
+// DeleteHoliday removes an existing holiday
+func (pdb *DB) DeleteHoliday(id string) error {
+	return remove(pdb.conn, holidayTable, id)
+}
+
+// ----- Rooms and students ---------------------------------------------------------------
+
+// AddRoom adds a new Room
+func (pdb *DB) AddRoom(r *model.Room) error {
+	return insert(pdb.conn, roomTable, r)
+}
+
+// GetAllRoom gets a page of rooms
+func (pdb *DB) GetAllRoom(page db.Page) ([]*model.Room, string, error) {
+	list := make([]*model.Room, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM room ORDER BY id")
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetRoom gets a single room by id
+func (pdb *DB) GetRoom(id string) (*model.Room, error) {
+	r := &model.Room{}
+	if err := get(pdb.conn, roomTable, r, id); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpdateRoom replaces an existing room
+func (pdb *DB) UpdateRoom(r *model.Room) error {
+	return update(pdb.conn, roomTable, r)
+}
+
+// DeleteRoom removes an existing room
+func (pdb *DB) DeleteRoom(id string) error {
+	return remove(pdb.conn, roomTable, id)
+}
+
+// AddStudent adds a new Student
+func (pdb *DB) AddStudent(s *model.Student) error {
+	return insert(pdb.conn, studentTable, s)
+}
+
+// GetAllStudent gets a page of students ordered by name
+func (pdb *DB) GetAllStudent(page db.Page) ([]*model.Student, string, error) {
+	list := make([]*model.Student, 0)
+	next, err := getPage(pdb.conn, page, &list, "SELECT data FROM student ORDER BY name, id")
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// GetStudent gets a single student by id
+func (pdb *DB) GetStudent(id string) (*model.Student, error) {
+	s := &model.Student{}
+	if err := get(pdb.conn, studentTable, s, id); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// UpdateStudent replaces an existing student
+func (pdb *DB) UpdateStudent(s *model.Student) error {
+	return update(pdb.conn, studentTable, s)
+}
+
+// DeleteStudent removes an existing student.
+// Confirmation details referring to the student block the delete with c.ErrConflict.
+func (pdb *DB) DeleteStudent(id string) error {
+	return pdb.runInTransaction(func(tx *sql.Tx) error {
+		found, err := exists(tx, studentTable, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return c.ErrDBNoSuchEntity
+		}
+		used, err := count(tx, "SELECT 1 FROM confirmation_detail WHERE student_id = $1", id)
+		if err != nil {
+			return err
+		}
+		if used > 0 {
+			return fmt.Errorf("%w: student %v is still referred to by %d confirmation details", c.ErrConflict, id, used)
+		}
+		return remove(tx, studentTable, id)
+	})
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db/dbtest"
+)
+
+// testURLEnv names the database the tests run against, they are skipped without it. Every table of
+// the database is emptied by the tests.
+const testURLEnv = "POSTGRES_TEST_URL"
+
+func TestMigrations(t *testing.T) {
+	list, err := migrations()
+	if err != nil {
+		t.Fatalf("failed to read migrations: %v", err)
+	}
+	if len(list) == 0 || list[0].version != 1 {
+		t.Fatalf("expecting the migrations to start with version 1, got %v", list)
+	}
+	for i, m := range list {
+		if i > 0 && m.version <= list[i-1].version {
+			t.Errorf("migration %v is not ordered after %v", m.name, list[i-1].name)
+		}
+		if len(strings.TrimSpace(m.sql)) == 0 {
+			t.Errorf("migration %v is empty", m.name)
+		}
+	}
+}
+
+func TestConformance(t *testing.T) {
+	pdb := openTestDB(t)
+	dbtest.Run(t, func(t *testing.T) db.Store {
+		if err := pdb.truncate(); err != nil {
+			t.Fatalf("failed to empty the database: %v", err)
+		}
+		return pdb
+	})
+}
+
+func TestMigrateTwice(t *testing.T) {
+	pdb := openTestDB(t)
+	applied, err := migrate(pdb.conn)
+	if err != nil {
+		t.Fatalf("failed to migrate a migrated database: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("expecting no migrations to be applied again, got %v", applied)
+	}
+}
+
+// openTestDB connects to the database named by testURLEnv, or skips the test
+func openTestDB(t *testing.T) *DB {
+	url, ok := os.LookupEnv(testURLEnv)
+	if !ok {
+		t.Skipf("this test depends on %v being set", testURLEnv)
+	}
+	pdb, err := NewDB(url)
+	if err != nil {
+		t.Fatalf("failed to init db connection: %v", err)
+	}
+	t.Cleanup(func() { pdb.Close() })
+	return pdb
+}
+
+// truncate deletes all entities, leaving the schema in place
+func (pdb *DB) truncate() error {
+	tables := []table{merchantTable, roleTable, kymTable, mainSubjectTable, subjectTable, teacherTable, responsibilityTable,
+		bellScheduleTable, termTable, holidayTable, roomTable, studentTable, confirmationTable, confirmationDetailTable,
+		scheduleTable, scheduleSlotTable}
+	names := make([]string, len(tables))
+	for i, t := range tables {
+		names[i] = t.name
+	}
+	_, err := pdb.conn.Exec("TRUNCATE " + strings.Join(names, ", "))
+	return err
+}
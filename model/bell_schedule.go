@@ -0,0 +1,48 @@
+package model
+
+// BellSchedule defines model for the periods of a school day, it is what gives "period 3" a time.
+type BellSchedule struct {
+	ID   string
+	Name string
+
+	// Default marks the bell schedule used when a confirmation does not name one, at most one is set
+	Default bool
+
+	// Periods are the teaching periods and breaks of the day in the order they take place
+	Periods []BellPeriod `datastore:",noindex"`
+}
+
+// BellPeriod is a single teaching period or break of a BellSchedule.
+// Start and End are the wall clock times formatted as 15:04.
+type BellPeriod struct {
+	// Period is the number lessons refer to the teaching period by, 0 for breaks
+	Period Period
+	Name   string
+	Start  string
+	End    string
+}
+
+// NewBellSchedule is a constructor for BellSchedule
+func NewBellSchedule(id string, name string, isDefault bool, periods []BellPeriod) *BellSchedule {
+	return &BellSchedule{
+		ID:      id,
+		Name:    name,
+		Default: isDefault,
+		Periods: periods,
+	}
+}
+
+// IsBreak reports whether the period is a break rather than a teaching period
+func (bp BellPeriod) IsBreak() bool {
+	return bp.Period == 0
+}
+
+// TeachingPeriod looks up the teaching period p
+func (b *BellSchedule) TeachingPeriod(p Period) (BellPeriod, bool) {
+	for _, bp := range b.Periods {
+		if !bp.IsBreak() && bp.Period == p {
+			return bp, true
+		}
+	}
+	return BellPeriod{}, false
+}
@@ -1,17 +1,114 @@
 package model
 
+import (
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/datastore"
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+)
+
 type Confirmation struct {
 	ID string
 	ConfirmationName string
 	CreateDate string
+	// BellScheduleID is the bell schedule the periods of the details refer to, the default one when empty
+	BellScheduleID string
+	// TermID is the term the weekly lessons of the details take place in
+	TermID string
+	// Status is the stage of the confirmation's lifecycle, it decides whether details can still be added
+	Status ConfirmationStatus
+	// StatusChangedBy is the user who last changed the status, empty for unauthenticated requests
+	StatusChangedBy string
+	// StatusChangedAt is when the status was last changed, zero while the confirmation is a new draft
+	StatusChangedAt time.Time
 }
 
-func NewConfirmation(id string,	confirmationName string, createDate string) *Confirmation {
+func NewConfirmation(id string,	confirmationName string, createDate string, bellScheduleID string, termID string) *Confirmation {
 	return &Confirmation{
 		ID:   id,
 		ConfirmationName: confirmationName,
 		CreateDate: createDate,
+		BellScheduleID: bellScheduleID,
+		TermID: termID,
+		Status: ConfirmationDraft,
+	}
+}
+
+// CheckAcceptsDetails returns an error wrapping c.ErrConflict when details can no longer be added to the
+// confirmation
+func (m *Confirmation) CheckAcceptsDetails() error {
+	if m.Status.AcceptsDetails() {
+		return nil
+	}
+	return fmt.Errorf("%w: confirmation %v is %v, details can only be added while it is %v or %v",
+		c.ErrConflict, m.ID, m.Status, ConfirmationDraft, ConfirmationSubmitted)
+}
+
+// Load implements datastore.PropertyLoadSaver, confirmations stored before the lifecycle existed are drafts
+func (m *Confirmation) Load(ps []datastore.Property) error {
+	if err := datastore.LoadStruct(m, ps); err != nil {
+		return err
 	}
+	if len(m.Status) == 0 {
+		m.Status = ConfirmationDraft
+	}
+	return nil
+}
+
+// Save implements datastore.PropertyLoadSaver
+func (m *Confirmation) Save() ([]datastore.Property, error) {
+	return datastore.SaveStruct(m)
+}
+
+// ConfirmationStatus is a stage of the lifecycle of a confirmation
+type ConfirmationStatus string
+
+const (
+	// ConfirmationDraft confirmations are still being filled in
+	ConfirmationDraft ConfirmationStatus = "draft"
+	// ConfirmationSubmitted confirmations are complete and ready to be scheduled, details can still be added
+	ConfirmationSubmitted ConfirmationStatus = "submitted"
+	// ConfirmationLocked confirmations have been scheduled, their details no longer change
+	ConfirmationLocked ConfirmationStatus = "locked"
+	// ConfirmationArchived confirmations are only kept for reference
+	ConfirmationArchived ConfirmationStatus = "archived"
+)
+
+// confirmationTransitions lists the statuses a confirmation can move to from each status. A locked
+// confirmation is unlocked by submitting it again, archived confirmations stay archived.
+var confirmationTransitions = map[ConfirmationStatus][]ConfirmationStatus{
+	ConfirmationDraft:     {ConfirmationSubmitted, ConfirmationArchived},
+	ConfirmationSubmitted: {ConfirmationDraft, ConfirmationLocked, ConfirmationArchived},
+	ConfirmationLocked:    {ConfirmationSubmitted, ConfirmationArchived},
+	ConfirmationArchived:  {},
+}
+
+// Valid reports whether s is one of the statuses of the lifecycle
+func (s ConfirmationStatus) Valid() bool {
+	_, ok := confirmationTransitions[s]
+	return ok
+}
+
+// CanBecome reports whether a confirmation with status s can be moved to the status
+func (s ConfirmationStatus) CanBecome(status ConfirmationStatus) bool {
+	for _, next := range confirmationTransitions[s] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsDetails reports whether details can be added to a confirmation with status s
+func (s ConfirmationStatus) AcceptsDetails() bool {
+	return s == ConfirmationDraft || s == ConfirmationSubmitted
+}
+
+// Schedulable reports whether a schedule can be generated from a confirmation with status s, drafts are
+// still being filled in and archived confirmations are only kept for reference
+func (s ConfirmationStatus) Schedulable() bool {
+	return s == ConfirmationSubmitted || s == ConfirmationLocked
 }
 
 
@@ -19,20 +116,42 @@ type ConfirmationDetail struct {
 	ID string
 	ConfirmationID string
 	SubjectDetailID []string
+	// StudentID refers to the Student, details stored before students existed only have a StudentName
+	StudentID string
 	StudentName string
 	Level string
-	Period string
-	Day string
+	Period Period
+	Day Day
 }
 
-func NewConfirmationDetail(id string,confirmationID string, subjectDetailID []string, studentName string, level string,period string,day string) *ConfirmationDetail {
+func NewConfirmationDetail(id string,confirmationID string, subjectDetailID []string, studentID string, studentName string, level string,period Period,day Day) *ConfirmationDetail {
 	return &ConfirmationDetail{
 		ID: id,
 		ConfirmationID:   confirmationID,
 		SubjectDetailID: subjectDetailID,
+		StudentID: studentID,
 		StudentName: studentName,
 		Level: level,
 		Period: period,
 		Day: day,
 	}
 }
+
+// StudentKey identifies the student of the detail by StudentID, or by StudentName for details stored
+// before students existed
+func (cd *ConfirmationDetail) StudentKey() string {
+	if len(cd.StudentID) > 0 {
+		return cd.StudentID
+	}
+	return cd.StudentName
+}
+
+// Load implements datastore.PropertyLoadSaver, details stored before Day and Period existed hold them as free text
+func (cd *ConfirmationDetail) Load(ps []datastore.Property) error {
+	return datastore.LoadStruct(cd, upgradeDayPeriod(ps))
+}
+
+// Save implements datastore.PropertyLoadSaver
+func (cd *ConfirmationDetail) Save() ([]datastore.Property, error) {
+	return datastore.SaveStruct(cd)
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+)
+
+func TestConfirmationStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from ConfirmationStatus
+		to   ConfirmationStatus
+		want bool
+	}{
+		{ConfirmationDraft, ConfirmationSubmitted, true},
+		{ConfirmationDraft, ConfirmationLocked, false},
+		{ConfirmationDraft, ConfirmationDraft, false},
+		{ConfirmationSubmitted, ConfirmationDraft, true},
+		{ConfirmationSubmitted, ConfirmationLocked, true},
+		{ConfirmationLocked, ConfirmationDraft, false},
+		{ConfirmationLocked, ConfirmationSubmitted, true},
+		{ConfirmationLocked, ConfirmationArchived, true},
+		{ConfirmationArchived, ConfirmationDraft, false},
+		{ConfirmationArchived, ConfirmationSubmitted, false},
+		{ConfirmationDraft, "cancelled", false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanBecome(tt.to); got != tt.want {
+			t.Errorf("%v.CanBecome(%v) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmationAcceptsDetails(t *testing.T) {
+	confirmation := NewConfirmation("c1", "term 1", "2022-05-01", "", "t1")
+	if confirmation.Status != ConfirmationDraft {
+		t.Fatalf("expecting a new confirmation to be a draft, got %v", confirmation.Status)
+	}
+
+	for _, status := range []ConfirmationStatus{ConfirmationDraft, ConfirmationSubmitted} {
+		confirmation.Status = status
+		if err := confirmation.CheckAcceptsDetails(); err != nil {
+			t.Errorf("expecting a %v confirmation to accept details: %v", status, err)
+		}
+	}
+	for _, status := range []ConfirmationStatus{ConfirmationLocked, ConfirmationArchived} {
+		confirmation.Status = status
+		if err := confirmation.CheckAcceptsDetails(); !errors.Is(err, c.ErrConflict) {
+			t.Errorf("expecting a %v confirmation to refuse details with c.ErrConflict, got %v", status, err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/datastore"
+)
+
+// Day is a day of the week a lesson takes place on
+type Day string
+
+const (
+	Monday    Day = "monday"
+	Tuesday   Day = "tuesday"
+	Wednesday Day = "wednesday"
+	Thursday  Day = "thursday"
+	Friday    Day = "friday"
+	Saturday  Day = "saturday"
+	Sunday    Day = "sunday"
+)
+
+// Days lists the days of the week in order, starting on Monday
+var Days = []Day{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday}
+
+// dayAliases maps the ways days were written as free text to a Day
+var dayAliases = map[string]Day{
+	"mon": Monday, "tue": Tuesday, "tues": Tuesday, "wed": Wednesday, "thu": Thursday, "thur": Thursday, "thurs": Thursday,
+	"fri": Friday, "sat": Saturday, "sun": Sunday,
+	"จันทร์": Monday, "อังคาร": Tuesday, "พุธ": Wednesday, "พฤหัส": Thursday, "พฤหัสบดี": Thursday,
+	"ศุกร์": Friday, "เสาร์": Saturday, "อาทิตย์": Sunday,
+}
+
+// ParseDay reads a day of the week ignoring case and surrounding spaces. Besides the Day values
+// it accepts english abbreviations and thai day names, optionally prefixed with วัน.
+func ParseDay(s string) (Day, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	v = strings.TrimSpace(strings.TrimPrefix(v, "วัน"))
+	if d := Day(v); d.Valid() {
+		return d, nil
+	}
+	if d, ok := dayAliases[v]; ok {
+		return d, nil
+	}
+	return "", fmt.Errorf("%q is not a day of the week", s)
+}
+
+// Valid reports whether d is one of the days of the week
+func (d Day) Valid() bool {
+	return d.Index() >= 0
+}
+
+// Index is the position of the day in the week starting from 0 on Monday, -1 for an invalid day
+func (d Day) Index() int {
+	for i, day := range Days {
+		if d == day {
+			return i
+		}
+	}
+	return -1
+}
+
+// Weekday converts the day to a time.Weekday, it is only meaningful for a valid day
+func (d Day) Weekday() time.Weekday {
+	return time.Weekday((d.Index() + 1) % 7)
+}
+
+// DayOf returns the day of the week of the date
+func DayOf(date time.Time) Day {
+	return Days[(int(date.Weekday())+6)%7]
+}
+
+// Before reports whether d comes earlier in the week than o, invalid days sort last
+func (d Day) Before(o Day) bool {
+	i, j := d.Index(), o.Index()
+	if i < 0 {
+		i = len(Days)
+	}
+	if j < 0 {
+		j = len(Days)
+	}
+	if i != j {
+		return i < j
+	}
+	return d < o
+}
+
+// UnmarshalJSON normalises the day when it can be parsed, anything else is kept as is to be
+// rejected by validation
+func (d *Day) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if parsed, err := ParseDay(s); err == nil {
+		*d = parsed
+		return nil
+	}
+	*d = Day(s)
+	return nil
+}
+
+// Period is a teaching period of the day, the 1 based index of a slot in the school's bell schedule
+type Period int
+
+// ParsePeriod reads a period number, ignoring surrounding spaces and a leading "period" or "p"
+func ParsePeriod(s string) (Period, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	for _, prefix := range []string{"period", "p", "คาบที่", "คาบ"} {
+		if strings.HasPrefix(v, prefix) {
+			v = strings.TrimSpace(strings.TrimPrefix(v, prefix))
+			break
+		}
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a period", s)
+	}
+	return Period(n), nil
+}
+
+// UnmarshalJSON accepts the period as a number, or as a string the way it was sent before
+// Period existed
+func (p *Period) UnmarshalJSON(b []byte) error {
+	var n int
+	if err := json.Unmarshal(b, &n); err == nil {
+		*p = Period(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("period must be a number: %w", err)
+	}
+	parsed, err := ParsePeriod(s)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// upgradeDayPeriod converts the Day and Period properties of entities stored while they were free
+// text. A day that cannot be parsed is kept lowercased, a period that cannot be parsed becomes 0,
+// neither is a valid value so they can be found and fixed by hand.
+func upgradeDayPeriod(ps []datastore.Property) []datastore.Property {
+	for i, p := range ps {
+		v, ok := p.Value.(string)
+		if !ok {
+			continue
+		}
+		switch p.Name {
+		case "Day":
+			d, err := ParseDay(v)
+			if err != nil {
+				d = Day(strings.ToLower(strings.TrimSpace(v)))
+			}
+			ps[i].Value = string(d)
+		case "Period":
+			n, _ := ParsePeriod(v)
+			ps[i].Value = int64(n)
+		}
+	}
+	return ps
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cloud.google.com/go/datastore"
+)
+
+func TestParseDay(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Day
+		wantErr bool
+	}{
+		{in: "monday", want: Monday},
+		{in: " Tuesday ", want: Tuesday},
+		{in: "THU", want: Thursday},
+		{in: "วันศุกร์", want: Friday},
+		{in: "อาทิตย์", want: Sunday},
+		{in: "someday", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDay(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Period
+		wantErr bool
+	}{
+		{in: "1", want: 1},
+		{in: " 10 ", want: 10},
+		{in: "Period 3", want: 3},
+		{in: "p4", want: 4},
+		{in: "คาบที่ 2", want: 2},
+		{in: "0", wantErr: true},
+		{in: "afternoon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePeriod(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeriod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePeriod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDay_Before(t *testing.T) {
+	if !Monday.Before(Tuesday) || Sunday.Before(Monday) {
+		t.Errorf("Before() does not follow the order of the week")
+	}
+	if Day("someday").Before(Sunday) || !Sunday.Before(Day("someday")) {
+		t.Errorf("Before() does not sort invalid days last")
+	}
+}
+
+func TestPeriod_UnmarshalJSON(t *testing.T) {
+	var v struct {
+		Day    Day    `json:"day"`
+		Period Period `json:"period"`
+	}
+	if err := json.Unmarshal([]byte(`{"day":"Monday","period":"2"}`), &v); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+	if v.Day != Monday || v.Period != 2 {
+		t.Errorf("Unmarshal() = %+v, want monday period 2", v)
+	}
+	if err := json.Unmarshal([]byte(`{"period":"lunch"}`), &v); err == nil {
+		t.Errorf("Unmarshal() expected error for a period that is not a number")
+	}
+}
+
+func TestConfirmationDetail_LoadLegacyDayPeriod(t *testing.T) {
+	var cd ConfirmationDetail
+	err := cd.Load([]datastore.Property{
+		{Name: "ID", Value: "d1"},
+		{Name: "StudentName", Value: "alice"},
+		{Name: "Period", Value: " 3"},
+		{Name: "Day", Value: "Wednesday"},
+	})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cd.ID != "d1" || cd.Day != Wednesday || cd.Period != 3 {
+		t.Errorf("Load() = %+v, want d1 on wednesday period 3", cd)
+	}
+
+	props, err := cd.Save()
+	if err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+	var loaded ConfirmationDetail
+	if err := loaded.Load(props); err != nil {
+		t.Fatalf("Load() of saved detail unexpected error: %v", err)
+	}
+	if loaded.Day != Wednesday || loaded.Period != 3 {
+		t.Errorf("Load() of saved detail = %+v", loaded)
+	}
+}
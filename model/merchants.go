@@ -1,7 +1,10 @@
 package model
 
 import (
+	"errors"
 	"time"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 )
 
 // Teacher defines model for Teacher.
@@ -72,3 +75,26 @@ func NewMerchant(address Address, contactNumber, email, fullName, orgID, logoURL
 		Updated:        now,
 	}
 }
+
+// CheckUpdate returns a c.ErrValidation when the merchant can not be updated to `to`, the ids, the name and
+// the currency are fixed once the merchant is added
+func (m *Merchant) CheckUpdate(to *Merchant) error {
+
+	if m.OrganisationID != to.OrganisationID {
+		return &c.ErrValidation{Violations: errors.New("changing organisationID is unallowed")}
+	}
+
+	if m.MerchantID != to.MerchantID {
+		return &c.ErrValidation{Violations: errors.New("changing organisationID is unallowed")}
+	}
+
+	if m.FullName != to.FullName {
+		return &c.ErrValidation{Violations: errors.New("changing fullName is unallowed")}
+	}
+
+	if m.CurrencyCode != to.CurrencyCode {
+		return &c.ErrValidation{Violations: errors.New("changing CurrencyCode is unallowed")}
+	}
+
+	return nil
+}
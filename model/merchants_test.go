@@ -1,8 +1,10 @@
 package model_test
 
 import (
+	"errors"
 	"testing"
 
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
 )
 
@@ -44,3 +46,19 @@ func TestCreateMerchant(t *testing.T) {
 		t.Error("property Updated has not been set")
 	}
 }
+
+func TestMerchantCheckUpdate(t *testing.T) {
+	from := model.NewMerchant(model.Address{City: "Bangkok"}, "123456", "test@user.com", "company", "org123", "logo", "THB", []string{})
+
+	to := *from
+	to.Email = "changed@user.com"
+	if err := from.CheckUpdate(&to); err != nil {
+		t.Errorf("expected the email to be updatable, got %v", err)
+	}
+
+	to.FullName = "renamed"
+	var errValidation *c.ErrValidation
+	if err := from.CheckUpdate(&to); !errors.As(err, &errValidation) {
+		t.Errorf("expected renaming the merchant to fail with c.ErrValidation, got %v", err)
+	}
+}
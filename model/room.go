@@ -0,0 +1,60 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// Room defines model for a room classes are taught in.
+// Capacity is the largest number of students a class in the room may have, Features are tags such as
+// "lab" or "piano" matched against Subject.RequiredFeatures.
+type Room struct {
+	ID       string
+	Name     string
+	Capacity int
+	Features []string
+}
+
+// NewRoom is a constructor for Room which normalizes the features, see NormalizeFeatures
+func NewRoom(id string, name string, capacity int, features []string) *Room {
+	return &Room{
+		ID:       id,
+		Name:     name,
+		Capacity: capacity,
+		Features: NormalizeFeatures(features),
+	}
+}
+
+// HasFeatures reports whether the room has every one of the required features
+func (r *Room) HasFeatures(required []string) bool {
+	for _, f := range required {
+		found := false
+		for _, rf := range r.Features {
+			if rf == f {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeFeatures lowercases and trims feature tags, drops empty and repeated ones and sorts the
+// rest, so "Piano" required by a subject matches " piano" offered by a room
+func NormalizeFeatures(features []string) []string {
+	res := make([]string, 0, len(features))
+	seen := make(map[string]bool, len(features))
+	for _, f := range features {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if len(f) == 0 || seen[f] {
+			continue
+		}
+		seen[f] = true
+		res = append(res, f)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeFeatures(t *testing.T) {
+	got := NormalizeFeatures([]string{" Piano", "lab", "", "piano", "LAB "})
+	if want := []string{"lab", "piano"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeFeatures() = %v, want %v", got, want)
+	}
+}
+
+func TestRoom_HasFeatures(t *testing.T) {
+	r := NewRoom("r1", "Music room", 10, []string{"Piano", "projector"})
+
+	tests := []struct {
+		name     string
+		required []string
+		want     bool
+	}{
+		{name: "noneRequired", required: nil, want: true},
+		{name: "allPresent", required: []string{"piano", "projector"}, want: true},
+		{name: "oneMissing", required: []string{"piano", "lab"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.HasFeatures(tt.required); got != tt.want {
+				t.Errorf("HasFeatures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"time"
+
+	"cloud.google.com/go/datastore"
+)
+
+// Schedule defines model for a timetable generated from a Confirmation.
+type Schedule struct {
+	ID string
+
+	// ConfirmationID is the Confirmation the schedule was generated from
+	ConfirmationID string
+
+	// BellScheduleID is the bell schedule the periods of the slots refer to, empty when none was configured
+	BellScheduleID string
+
+	// TermID is the term the weekly slots take place in, empty for confirmations made before terms existed
+	TermID string
+
+	// Created is the timestamp the schedule was generated
+	Created time.Time
+
+	// Unscheduled lists the requested lessons which could not be placed
+	Unscheduled []UnscheduledLesson `datastore:",noindex"`
+}
+
+// Load implements datastore.PropertyLoadSaver, the unscheduled lessons of schedules generated before
+// Day and Period existed hold them as free text
+func (s *Schedule) Load(ps []datastore.Property) error {
+	for _, p := range ps {
+		if p.Name != "Unscheduled" {
+			continue
+		}
+		lessons, _ := p.Value.([]interface{})
+		for _, l := range lessons {
+			if e, ok := l.(*datastore.Entity); ok {
+				e.Properties = upgradeDayPeriod(e.Properties)
+			}
+		}
+	}
+	return datastore.LoadStruct(s, ps)
+}
+
+// Save implements datastore.PropertyLoadSaver
+func (s *Schedule) Save() ([]datastore.Property, error) {
+	return datastore.SaveStruct(s)
+}
+
+// NewSchedule is a constructor for Schedule which populates the created timestamp
+func NewSchedule(id string, confirmationID string, bellScheduleID string, termID string, unscheduled []UnscheduledLesson) *Schedule {
+	return &Schedule{
+		ID:             id,
+		ConfirmationID: confirmationID,
+		BellScheduleID: bellScheduleID,
+		TermID:         termID,
+		Created:        time.Now(),
+		Unscheduled:    unscheduled,
+	}
+}
+
+// ScheduleSlot defines a single class of a Schedule: one teacher teaching one subject
+// to a group of students on a given day and period.
+type ScheduleSlot struct {
+	ID         string
+	ScheduleID string
+	TeacherID  string
+	SubjectID  string
+	Day        Day
+	Period     Period
+
+	// RoomID is the room the class is taught in, empty when no rooms were defined
+	RoomID string
+
+	// StudentNames are the students attending the class
+	StudentNames []string
+
+	// StudentIDs are the ids of the students in the order of StudentNames, empty for students of
+	// details stored before students existed. Slots generated before then have none at all.
+	StudentIDs []string
+
+	// ConfirmationDetailIDs are the confirmation details the class was built from
+	ConfirmationDetailIDs []string `datastore:",noindex"`
+}
+
+// StudentKeys identifies the students attending the class by id, or by name when they have none,
+// see ConfirmationDetail.StudentKey
+func (s *ScheduleSlot) StudentKeys() []string {
+	keys := make([]string, 0, len(s.StudentNames))
+	for i, name := range s.StudentNames {
+		if i < len(s.StudentIDs) && len(s.StudentIDs[i]) > 0 {
+			keys = append(keys, s.StudentIDs[i])
+			continue
+		}
+		keys = append(keys, name)
+	}
+	return keys
+}
+
+// Load implements datastore.PropertyLoadSaver, slots generated before Day and Period existed hold them as free text
+func (s *ScheduleSlot) Load(ps []datastore.Property) error {
+	return datastore.LoadStruct(s, upgradeDayPeriod(ps))
+}
+
+// Save implements datastore.PropertyLoadSaver
+func (s *ScheduleSlot) Save() ([]datastore.Property, error) {
+	return datastore.SaveStruct(s)
+}
+
+// UnscheduledLesson is a requested lesson the scheduler was unable to place and why.
+type UnscheduledLesson struct {
+	ConfirmationDetailID string
+	StudentID            string
+	StudentName          string
+	SubjectID            string
+	Day                  Day
+	Period               Period
+	Reason               string
+}
@@ -0,0 +1,29 @@
+package model
+
+// Student defines model for a student taking lessons. Students are referred to by ID, so two
+// students sharing a name are told apart.
+type Student struct {
+	ID    string
+	Name  string
+	Level string
+
+	// GuardianName, GuardianPhone and GuardianEmail are how the student's guardian is contacted
+	GuardianName  string
+	GuardianPhone string `datastore:",noindex"`
+	GuardianEmail string `datastore:",noindex"`
+
+	Notes string `datastore:",noindex"`
+}
+
+// NewStudent is a constructor for Student
+func NewStudent(id string, name string, level string, guardianName string, guardianPhone string, guardianEmail string, notes string) *Student {
+	return &Student{
+		ID:            id,
+		Name:          name,
+		Level:         level,
+		GuardianName:  guardianName,
+		GuardianPhone: guardianPhone,
+		GuardianEmail: guardianEmail,
+		Notes:         notes,
+	}
+}
@@ -6,13 +6,17 @@ type Subject struct {
 	SubjectName string
 	MainSubjectId string
 	MinOfStudent int
+
+	// RequiredFeatures are the features a room needs to have for the subject to be taught in it
+	RequiredFeatures []string
 }
 
-func NewSubject(id string, subjectName string, mainSubjectId string, minOfStudent int) *Subject {
+func NewSubject(id string, subjectName string, mainSubjectId string, minOfStudent int, requiredFeatures []string) *Subject {
 	return &Subject{
 		ID:            id,
 		SubjectName: subjectName,
 		MainSubjectId: mainSubjectId,
 		MinOfStudent: minOfStudent,
+		RequiredFeatures: NormalizeFeatures(requiredFeatures),
 	}
 }
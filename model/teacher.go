@@ -1,5 +1,17 @@
 package model
 
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/datastore"
+)
+
+// TeacherNamePrefixes is a property only written to the datastore. It holds every lowercase prefix of the
+// teacher's first, nick and last name so teachers can be searched by the start of any of their names.
+const TeacherNamePrefixes = "NamePrefixes"
+
 // Teacher defines model for Teacher.
 type Teacher struct {
 	ID string `json:"Id"`
@@ -11,21 +23,146 @@ type Teacher struct {
 	LastName string `json:"lastName"`
 	// The merchant's main contact number
 	ContactNumber string `json:"contactNumber"`
-	// Only THB is supported currently
-	Capacity string `json:"capacity"`
+	// Workload limits of the teacher
+	Capacity TeacherCapacity `json:"capacity"`
 	// Only THB is supported currently
 	MainSubjectID string `json:"mainSubjectId"`
+	// Weekly windows the teacher is able to teach in, no windows means the teacher is always available
+	Availability []AvailabilityWindow `json:"availability"`
+	// Date ranges the teacher is away regardless of the weekly windows
+	AvailabilityExceptions []AvailabilityException `json:"availabilityExceptions"`
+}
+
+// TeacherCapacity defines the workload limits of a teacher.
+// Zero limits mean the teacher is not limited, they only occur on teachers stored before the limits existed.
+type TeacherCapacity struct {
+	MaxPeriodsPerDay     int `json:"maxPeriodsPerDay"`
+	MaxPeriodsPerWeek    int `json:"maxPeriodsPerWeek"`
+	MaxStudentsPerPeriod int `json:"maxStudentsPerPeriod"`
+}
+
+// AvailabilityWindow is a range of periods on a day of the week, inclusive of both ends
+type AvailabilityWindow struct {
+	Day         Day    `json:"day"`
+	StartPeriod Period `json:"startPeriod"`
+	EndPeriod   Period `json:"endPeriod"`
+}
+
+// AvailabilityException is a range of dates the teacher is unavailable, inclusive of both ends
+type AvailabilityException struct {
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+	Reason    string    `json:"reason"`
 }
 
 // NewTeacher is a constructor for Merchant which populates the MerchantID and the timestamps
-func NewTeacher(id string, firstName string, nickName string, lastName string, contactNumber string, capacity string, mainSubjectID string) *Teacher {
+func NewTeacher(id string, firstName string, nickName string, lastName string, contactNumber string, capacity TeacherCapacity, mainSubjectID string, availability []AvailabilityWindow, exceptions []AvailabilityException) *Teacher {
 	return &Teacher{
-		ID:            id,
-		NickName:      nickName,
-		FirstName:     firstName,
-		LastName:      lastName,
-		ContactNumber: contactNumber,
-		Capacity:      capacity,
-		MainSubjectID: mainSubjectID,
+		ID:                     id,
+		NickName:               nickName,
+		FirstName:              firstName,
+		LastName:               lastName,
+		ContactNumber:          contactNumber,
+		Capacity:               capacity,
+		MainSubjectID:          mainSubjectID,
+		Availability:           availability,
+		AvailabilityExceptions: exceptions,
 	}
 }
+
+// Load implements datastore.PropertyLoadSaver.
+// Teachers stored before TeacherCapacity existed hold a free text Capacity, a number there is read as MaxStudentsPerPeriod.
+// Availability windows stored before Day existed may hold a day in any case.
+func (t *Teacher) Load(ps []datastore.Property) error {
+	legacyCapacity := ""
+	props := make([]datastore.Property, 0, len(ps))
+	for _, p := range ps {
+		if v, ok := p.Value.(string); ok && p.Name == "Capacity" {
+			legacyCapacity = v
+			continue
+		}
+		if p.Name == TeacherNamePrefixes {
+			continue
+		}
+		if windows, ok := p.Value.([]interface{}); ok && p.Name == "Availability" {
+			for _, w := range windows {
+				if e, ok := w.(*datastore.Entity); ok {
+					e.Properties = upgradeDayPeriod(e.Properties)
+				}
+			}
+		}
+		props = append(props, p)
+	}
+
+	if err := datastore.LoadStruct(t, props); err != nil {
+		return err
+	}
+
+	if n, err := strconv.Atoi(strings.TrimSpace(legacyCapacity)); err == nil && n > 0 {
+		t.Capacity = TeacherCapacity{MaxStudentsPerPeriod: n}
+	}
+	return nil
+}
+
+// Save implements datastore.PropertyLoadSaver, it adds the TeacherNamePrefixes used to search teachers
+func (t *Teacher) Save() ([]datastore.Property, error) {
+	props, err := datastore.SaveStruct(t)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := make([]interface{}, 0)
+	for _, p := range namePrefixes(t.FirstName, t.NickName, t.LastName) {
+		prefixes = append(prefixes, p)
+	}
+	return append(props, datastore.Property{Name: TeacherNamePrefixes, Value: prefixes}), nil
+}
+
+// namePrefixes returns the distinct lowercase prefixes of the names
+func namePrefixes(names ...string) []string {
+	seen := make(map[string]bool)
+	res := make([]string, 0)
+	for _, name := range names {
+		runes := []rune(strings.ToLower(strings.TrimSpace(name)))
+		for i := 1; i <= len(runes); i++ {
+			prefix := string(runes[:i])
+			if !seen[prefix] {
+				seen[prefix] = true
+				res = append(res, prefix)
+			}
+		}
+	}
+	return res
+}
+
+// AvailableAt reports whether the teacher's weekly windows cover the period on the day.
+// Teachers without any windows are treated as available at all times.
+func (t *Teacher) AvailableAt(day Day, period Period) bool {
+	if len(t.Availability) == 0 {
+		return true
+	}
+
+	for _, w := range t.Availability {
+		if w.Day == day && w.StartPeriod <= period && period <= w.EndPeriod {
+			return true
+		}
+	}
+	return false
+}
+
+// AvailableOn reports whether the date falls outside all of the teacher's exceptions
+func (t *Teacher) AvailableOn(date time.Time) bool {
+	d := truncateToDate(date)
+	for _, e := range t.AvailabilityExceptions {
+		if !d.Before(truncateToDate(e.StartDate)) && !d.After(truncateToDate(e.EndDate)) {
+			return false
+		}
+	}
+	return true
+}
+
+// truncateToDate drops the time of day so dates can be compared regardless of the hour
+func truncateToDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
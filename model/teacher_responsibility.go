@@ -1,6 +1,7 @@
 package model
 
-
+// TeacherResponsibility qualifies a teacher to teach a subject
+// in addition to the subjects of the teacher's main subject.
 type TeacherResponsibility struct {
 	ID string
 	TeacherId string
@@ -14,3 +15,18 @@ func NewTeacherResponsibility(id string, teacherId string, subjectId string) *Te
 		SubjectId: subjectId,
 	}
 }
+
+// NewTeacherResponsibilities creates the responsibilities qualifying a teacher for the subjects, in their
+// order. A subject listed more than once is qualified for once.
+func NewTeacherResponsibilities(teacherId string, subjectIds []string) []*TeacherResponsibility {
+	list := make([]*TeacherResponsibility, 0, len(subjectIds))
+	seen := make(map[string]bool, len(subjectIds))
+	for _, subjectId := range subjectIds {
+		if seen[subjectId] {
+			continue
+		}
+		seen[subjectId] = true
+		list = append(list, NewTeacherResponsibility(teacherId+"-"+subjectId, teacherId, subjectId))
+	}
+	return list
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/datastore"
+)
+
+func TestTeacher_AvailableAt(t *testing.T) {
+	teacher := NewTeacher("t1", "first", "nick", "last", "0", TeacherCapacity{MaxStudentsPerPeriod: 10}, "music", []AvailabilityWindow{
+		{Day: "tuesday", StartPeriod: 5, EndPeriod: 8},
+	}, nil)
+
+	tests := []struct {
+		name    string
+		teacher *Teacher
+		day     Day
+		period  Period
+		want    bool
+	}{
+		{name: "insideWindow", teacher: teacher, day: Tuesday, period: 5, want: true},
+		{name: "lastPeriodOfWindow", teacher: teacher, day: Tuesday, period: 8, want: true},
+		{name: "outsidePeriods", teacher: teacher, day: Tuesday, period: 4, want: false},
+		{name: "otherDay", teacher: teacher, day: Monday, period: 5, want: false},
+		{name: "noWindowsMeansAlwaysAvailable", teacher: &Teacher{ID: "t2"}, day: Monday, period: 1, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.teacher.AvailableAt(tt.day, tt.period); got != tt.want {
+				t.Errorf("AvailableAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeacher_AvailableOn(t *testing.T) {
+	teacher := NewTeacher("t1", "first", "nick", "last", "0", TeacherCapacity{MaxStudentsPerPeriod: 10}, "music", nil, []AvailabilityException{
+		{
+			StartDate: time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2022, 1, 14, 0, 0, 0, 0, time.UTC),
+			Reason:    "leave",
+		},
+	})
+
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{name: "beforeException", date: time.Date(2022, 1, 9, 12, 0, 0, 0, time.UTC), want: true},
+		{name: "firstDay", date: time.Date(2022, 1, 10, 9, 0, 0, 0, time.UTC), want: false},
+		{name: "lastDayAfternoon", date: time.Date(2022, 1, 14, 15, 0, 0, 0, time.UTC), want: false},
+		{name: "afterException", date: time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := teacher.AvailableOn(tt.date); got != tt.want {
+				t.Errorf("AvailableOn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeacher_LoadLegacyCapacity(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity datastore.Property
+		want     TeacherCapacity
+	}{
+		{
+			name:     "numericString",
+			capacity: datastore.Property{Name: "Capacity", Value: " 12 "},
+			want:     TeacherCapacity{MaxStudentsPerPeriod: 12},
+		},
+		{
+			name:     "freeText",
+			capacity: datastore.Property{Name: "Capacity", Value: "mornings only"},
+			want:     TeacherCapacity{},
+		},
+		{
+			name: "structured",
+			capacity: datastore.Property{Name: "Capacity", Value: &datastore.Entity{Properties: []datastore.Property{
+				{Name: "MaxPeriodsPerDay", Value: int64(3)},
+				{Name: "MaxPeriodsPerWeek", Value: int64(12)},
+				{Name: "MaxStudentsPerPeriod", Value: int64(6)},
+			}}},
+			want: TeacherCapacity{MaxPeriodsPerDay: 3, MaxPeriodsPerWeek: 12, MaxStudentsPerPeriod: 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teacher := &Teacher{}
+			err := teacher.Load([]datastore.Property{
+				{Name: "ID", Value: "t1"},
+				{Name: "MainSubjectID", Value: "music"},
+				tt.capacity,
+			})
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if teacher.ID != "t1" || teacher.MainSubjectID != "music" {
+				t.Errorf("Load() did not load other fields: %+v", teacher)
+			}
+			if teacher.Capacity != tt.want {
+				t.Errorf("Load() capacity = %+v, want %+v", teacher.Capacity, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeacher_SaveNamePrefixes(t *testing.T) {
+	teacher := NewTeacher("t1", "Somchai", "Ann", "An", "0", TeacherCapacity{}, "music", nil, nil)
+
+	props, err := teacher.Save()
+	if err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	var prefixes []interface{}
+	for _, p := range props {
+		if p.Name == TeacherNamePrefixes {
+			prefixes = p.Value.([]interface{})
+		}
+	}
+	want := []interface{}{"s", "so", "som", "somc", "somch", "somcha", "somchai", "a", "an", "ann"}
+	if !reflect.DeepEqual(prefixes, want) {
+		t.Errorf("Save() prefixes = %v, want %v", prefixes, want)
+	}
+
+	loaded := &Teacher{}
+	if err := loaded.Load(props); err != nil {
+		t.Fatalf("Load() of saved teacher unexpected error: %v", err)
+	}
+	if loaded.FirstName != "Somchai" || loaded.LastName != "An" {
+		t.Errorf("Load() did not load saved teacher: %+v", loaded)
+	}
+}
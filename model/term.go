@@ -0,0 +1,93 @@
+package model
+
+import "time"
+
+// Term defines model for an academic term, the dates a confirmation's weekly lessons take place between.
+// StartDate and EndDate are inclusive and stored as midnight UTC of the date, see DateOf.
+type Term struct {
+	ID        string
+	Name      string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// Holiday defines model for a holiday or school closure, no lessons take place from StartDate to
+// EndDate inclusive. The dates are stored as midnight UTC of the date, see DateOf.
+type Holiday struct {
+	ID        string
+	Name      string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// NewTerm is a constructor for Term which drops the time of day from the dates
+func NewTerm(id string, name string, startDate time.Time, endDate time.Time) *Term {
+	return &Term{
+		ID:        id,
+		Name:      name,
+		StartDate: DateOf(startDate),
+		EndDate:   DateOf(endDate),
+	}
+}
+
+// NewHoliday is a constructor for Holiday which drops the time of day from the dates
+func NewHoliday(id string, name string, startDate time.Time, endDate time.Time) *Holiday {
+	return &Holiday{
+		ID:        id,
+		Name:      name,
+		StartDate: DateOf(startDate),
+		EndDate:   DateOf(endDate),
+	}
+}
+
+// DateOf returns midnight UTC of the calendar date of t in its own location. Calendar dates are stored
+// this way so they do not move to another day when read in a different time zone.
+func DateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Covers reports whether the date falls within the holiday
+func (h *Holiday) Covers(date time.Time) bool {
+	d := DateOf(date)
+	return !d.Before(h.StartDate.UTC()) && !d.After(h.EndDate.UTC())
+}
+
+// Dates returns every date of the term falling on the day of the week, holidays included
+func (t *Term) Dates(day Day) []time.Time {
+	res := make([]time.Time, 0)
+	if !day.Valid() {
+		return res
+	}
+
+	d := t.StartDate.UTC()
+	end := t.EndDate.UTC()
+	for d.Weekday() != day.Weekday() {
+		d = d.AddDate(0, 0, 1)
+	}
+	for ; !d.After(end); d = d.AddDate(0, 0, 7) {
+		res = append(res, d)
+	}
+	return res
+}
+
+// SessionDates returns every date of the term falling on the day of the week, except those covered by a holiday
+func (t *Term) SessionDates(day Day, holidays []*Holiday) []time.Time {
+	res := make([]time.Time, 0)
+	for _, d := range t.Dates(day) {
+		if !CoveredByHoliday(d, holidays) {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// CoveredByHoliday reports whether any of the holidays covers the date
+func CoveredByHoliday(date time.Time, holidays []*Holiday) bool {
+	for _, h := range holidays {
+		if h.Covers(date) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTerm_SessionDates(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	// 2022-05-16 is a Monday
+	term := NewTerm("t1", "Term 1", date("2022-05-16"), date("2022-06-06"))
+	holidays := []*Holiday{
+		NewHoliday("h1", "Visakha Bucha", date("2022-05-23"), date("2022-05-23")),
+		NewHoliday("h2", "Closure", date("2022-05-31"), date("2022-06-01")),
+	}
+
+	tests := []struct {
+		name string
+		day  Day
+		want []string
+	}{
+		{name: "startsOnTheDay", day: Monday, want: []string{"2022-05-16", "2022-05-30", "2022-06-06"}},
+		{name: "skipsClosure", day: Wednesday, want: []string{"2022-05-18", "2022-05-25"}},
+		{name: "noHoliday", day: Friday, want: []string{"2022-05-20", "2022-05-27", "2022-06-03"}},
+		{name: "invalidDay", day: Day("someday"), want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := term.SessionDates(tt.day, holidays)
+			if len(got) != len(tt.want) {
+				t.Fatalf("SessionDates() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Format("2006-01-02") != tt.want[i] {
+					t.Errorf("SessionDates()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHoliday_Covers(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	h := NewHoliday("h1", "Songkran", time.Date(2022, 4, 13, 0, 0, 0, 0, bangkok), time.Date(2022, 4, 15, 0, 0, 0, 0, bangkok))
+
+	if !h.Covers(time.Date(2022, 4, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Covers() the first day = false, want true")
+	}
+	if !h.Covers(time.Date(2022, 4, 15, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("Covers() late on the last day = false, want true")
+	}
+	if h.Covers(time.Date(2022, 4, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Covers() the day after = true, want false")
+	}
+}
@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// NewBellSchedule struct used for creating and replacing bell schedules
+type NewBellSchedule struct {
+	Name string `json:"name" validate:"required" example:"Regular day"`
+	// The bell schedule used by confirmations which do not name one, setting it unsets the previous default
+	Default bool `json:"default" example:"true"`
+	// The teaching periods and breaks of the day in the order they take place
+	Periods []BellPeriod `json:"periods" validate:"required,min=1,dive"`
+}
+
+// BellPeriod is a single teaching period or break of a bell schedule
+type BellPeriod struct {
+	// Number of the teaching period, teaching periods are numbered from 1 in order. 0 for breaks
+	Period model.Period `json:"period" validate:"min=0" example:"1"`
+	Name   string       `json:"name" validate:"required" example:"Period 1"`
+	// Time the period starts formatted as 15:04
+	StartTime string `json:"startTime" validate:"required,datetime=15:04" example:"08:30"`
+	// Time the period ends formatted as 15:04
+	EndTime string `json:"endTime" validate:"required,datetime=15:04" example:"09:20"`
+}
+
+// BellSchedule struct used for reading bell schedules
+type BellSchedule struct {
+	NewBellSchedule
+	ID string `json:"id" validate:"required"`
+}
+
+// PeriodTime is when a period takes place according to the bell schedule, it is empty when no bell
+// schedule defines the period
+type PeriodTime struct {
+	PeriodName string `json:"periodName,omitempty" example:"Period 1"`
+	StartTime  string `json:"startTime,omitempty" example:"08:30"`
+	EndTime    string `json:"endTime,omitempty" example:"09:20"`
+}
+
+// NewPeriodTime resolves the period against the bell schedule, bell may be nil
+func NewPeriodTime(bell *model.BellSchedule, p model.Period) PeriodTime {
+	if bell == nil {
+		return PeriodTime{}
+	}
+	bp, ok := bell.TeachingPeriod(p)
+	if !ok {
+		return PeriodTime{}
+	}
+	return PeriodTime{PeriodName: bp.Name, StartTime: bp.Start, EndTime: bp.End}
+}
+
+// Validate does some simple validation on the NewBellSchedule object per annotations, and checks the
+// periods are in order without overlapping and the teaching periods are numbered from 1
+func (nb *NewBellSchedule) Validate() error {
+	if err := validator.New().Struct(nb); err != nil {
+		return err
+	}
+
+	next := model.Period(1)
+	for i, bp := range nb.Periods {
+		if !clockTime(bp.StartTime).Before(clockTime(bp.EndTime)) {
+			return fmt.Errorf("%v must start before it ends", bp.Name)
+		}
+		if i > 0 && clockTime(bp.StartTime).Before(clockTime(nb.Periods[i-1].EndTime)) {
+			return fmt.Errorf("%v starts before %v ends", bp.Name, nb.Periods[i-1].Name)
+		}
+		if bp.Period == 0 {
+			continue
+		}
+		if bp.Period != next {
+			return fmt.Errorf("%v is numbered %d but should be period %d, teaching periods are numbered from 1 in order", bp.Name, bp.Period, next)
+		}
+		next++
+	}
+	if next == 1 {
+		return fmt.Errorf("a bell schedule needs at least one teaching period")
+	}
+	return nil
+}
+
+// ToModel converts dto.NewBellSchedule to model.BellSchedule
+func (nb *NewBellSchedule) ToModel(id string) *model.BellSchedule {
+	periods := make([]model.BellPeriod, 0, len(nb.Periods))
+	for _, bp := range nb.Periods {
+		periods = append(periods, model.BellPeriod{
+			Period: bp.Period,
+			Name:   bp.Name,
+			Start:  clockTime(bp.StartTime).Format(clockFormat),
+			End:    clockTime(bp.EndTime).Format(clockFormat),
+		})
+	}
+	return model.NewBellSchedule(id, nb.Name, nb.Default, periods)
+}
+
+// clockFormat is the layout of the times of a bell schedule
+const clockFormat = "15:04"
+
+// clockTime parses a time of a validated bell period, so 8:30 is stored as 08:30 and compares before 10:00
+func clockTime(s string) time.Time {
+	t, _ := time.Parse(clockFormat, s)
+	return t
+}
+
+// ToBellScheduleDTO converts a list of model.BellSchedule to dto.BellSchedule
+func ToBellScheduleDTO(list []*model.BellSchedule) []*BellSchedule {
+	res := make([]*BellSchedule, 0, len(list))
+	for _, item := range list {
+		periods := make([]BellPeriod, 0, len(item.Periods))
+		for _, bp := range item.Periods {
+			periods = append(periods, BellPeriod{Period: bp.Period, Name: bp.Name, StartTime: bp.Start, EndTime: bp.End})
+		}
+		res = append(res, &BellSchedule{
+			ID: item.ID,
+			NewBellSchedule: NewBellSchedule{
+				Name:    item.Name,
+				Default: item.Default,
+				Periods: periods,
+			},
+		})
+	}
+	return res
+}
+
+// ToBellSchedule converts a model.BellSchedule to dto.BellSchedule
+func ToBellSchedule(b *model.BellSchedule) *BellSchedule {
+	return ToBellScheduleDTO([]*model.BellSchedule{b})[0]
+}
@@ -1,35 +1,48 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
 )
 
 type NewConfirmationDetail struct {
 	ConfirmationID string `json:"confirmationId" validate:"required" example:"merchant company"`
 	SubjectDetailID []string `json:"subjectDetailId" validate:"required" example:"merchant company"`
-	StudentName string `json:"studentName" validate:"required" example:"merchant company"`
-	Level string `json:"level" validate:"required" example:"merchant company"`
-	Period string `json:"period" validate:"required" example:"merchant company"`
-	Day string `json:"day" example:"merchant company"`
+	// The student taking the lessons, their name and level are filled in from the student
+	StudentID string `json:"studentId" example:"5e1c2b3a-4d5f-4a6b-8c7d-9e0f1a2b3c4d"`
+	// Name of a student without a student id, details made before students existed only have a name
+	StudentName string `json:"studentName" validate:"required_without=StudentID" example:"merchant company"`
+	Level string `json:"level" validate:"required_without=StudentID" example:"merchant company"`
+	// Period is the slot of the bell schedule, a number from 1
+	Period model.Period `json:"period" validate:"min=1" example:"3"`
+	// Day is the lowercase english day of the week
+	Day model.Day `json:"day" validate:"required,oneof=monday tuesday wednesday thursday friday saturday sunday" example:"monday"`
 }
 
 
 type ConfirmationDetail struct {
 	NewConfirmationDetail
+	PeriodTime
 	ID string `json:"id" validate:"required"`
 }
 
-func ToConfirmationDetailDTO(confirmationList []*model.ConfirmationDetail) []*ConfirmationDetail {
+// ToConfirmationDetailDTO converts a list of model.ConfirmationDetail to dto.ConfirmationDetail, the
+// periods are resolved against the bell schedule when one is given
+func ToConfirmationDetailDTO(confirmationList []*model.ConfirmationDetail, bell *model.BellSchedule) []*ConfirmationDetail {
 
-	var res []*ConfirmationDetail
+	res := make([]*ConfirmationDetail, 0, len(confirmationList))
 
 	for _, item := range confirmationList {
 		var resItem = &ConfirmationDetail{
 			ID:                  item.ID,
+			PeriodTime: NewPeriodTime(bell, item.Period),
 			NewConfirmationDetail: NewConfirmationDetail{
 				ConfirmationID: item.ConfirmationID,
 				SubjectDetailID: item.SubjectDetailID,
+				StudentID: item.StudentID,
 				StudentName: item.StudentName,
 				Level: item.Level,
 				Period: item.Period,
@@ -53,6 +66,7 @@ func (cd *NewConfirmationDetail) ToModel(id string) *model.ConfirmationDetail {
 		id,
 		cd.ConfirmationID,
 		cd.SubjectDetailID,
+		cd.StudentID,
 		cd.StudentName,
 		cd.Level,
 		cd.Period,
@@ -61,6 +75,48 @@ func (cd *NewConfirmationDetail) ToModel(id string) *model.ConfirmationDetail {
 }
 
 
+// ConfirmationDetailRow is a confirmation detail read from a row of an uploaded spreadsheet
+type ConfirmationDetailRow struct {
+	// Line of the row in the spreadsheet, the header is line 1
+	Line   int
+	Detail *NewConfirmationDetail
+	// Err is set when a cell of the row could not be read, Detail holds the rest of the row
+	Err error
+}
+
+// confirmationDetailColumns are the columns of a spreadsheet of confirmation details, subjectDetailId
+// holds the subject details separated by commas or semicolons
+var confirmationDetailColumns = []string{"studentId", "studentName", "level", "subjectDetailId", "day", "period"}
+
+// ToConfirmationDetailRows reads the rows of a spreadsheet of confirmation details of the confirmation.
+// Days and periods are read the way model.ParseDay and model.ParsePeriod do, a day which cannot be read
+// is kept to be rejected by Validate.
+func ToConfirmationDetailRows(confirmationID string, sheet *util.Sheet) ([]*ConfirmationDetailRow, error) {
+	if err := checkColumns(sheet, confirmationDetailColumns, "studentId|studentName", "subjectDetailId", "day", "period"); err != nil {
+		return nil, err
+	}
+
+	res := make([]*ConfirmationDetailRow, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		cd := &NewConfirmationDetail{
+			ConfirmationID:  confirmationID,
+			SubjectDetailID: splitList(row.Get("subjectDetailId")),
+			StudentID:       row.Get("studentId"),
+			StudentName:     row.Get("studentName"),
+			Level:           row.Get("level"),
+			Day:             model.Day(row.Get("day")),
+		}
+		if day, err := model.ParseDay(row.Get("day")); err == nil {
+			cd.Day = day
+		}
+
+		var err error
+		cd.Period, err = model.ParsePeriod(row.Get("period"))
+		res = append(res, &ConfirmationDetailRow{Line: row.Line, Detail: cd, Err: err})
+	}
+	return res, nil
+}
+
 
 // NewConfirmation struct used for creating new teacher requests
 type NewConfirmation struct {
@@ -68,17 +124,27 @@ type NewConfirmation struct {
 	ConfirmationName string `json:"confirmationName" validate:"required" example:"merchant company"`
 	// The merchant's company name in full
 	CreateDate string `json:"createDate" validate:"required" example:"merchant company"`
+	// The bell schedule the periods of the details refer to, the default bell schedule when empty
+	BellScheduleID string `json:"bellScheduleId" example:"9b2f0c1e-5d7a-4c36-8f0e-2a6b1d3c4e5f"`
+	// The term the weekly lessons take place in
+	TermID string `json:"termId" validate:"required" example:"2c7d1f0a-3b4e-4a5f-9c6d-7e8f9a0b1c2d"`
 }
 
 // Confirmation struct used for updating Teacher
 type Confirmation struct {
 	NewConfirmation
 	ID string `json:"id" validate:"required"`
+	// Status is the stage of the confirmation's lifecycle, details can only be added while it is draft or submitted
+	Status model.ConfirmationStatus `json:"status" example:"draft"`
+	// StatusChangedBy is the user who last changed the status
+	StatusChangedBy string `json:"statusChangedBy,omitempty" example:"teacher@school.ac.th"`
+	// StatusChangedAt is when the status was last changed, missing while the confirmation is a new draft
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty" example:"2022-05-01T09:30:00Z"`
 }
 
 func ToConfirmationDTO(confirmationList []*model.Confirmation) []*Confirmation {
 
-	var res []*Confirmation
+	res := make([]*Confirmation, 0, len(confirmationList))
 
 	for _, item := range confirmationList {
 		var resItem = &Confirmation{
@@ -86,13 +152,38 @@ func ToConfirmationDTO(confirmationList []*model.Confirmation) []*Confirmation {
 			NewConfirmation: NewConfirmation{
 				ConfirmationName: item.ConfirmationName,
 				CreateDate: item.CreateDate,
+				BellScheduleID: item.BellScheduleID,
+				TermID: item.TermID,
 			},
+			Status: item.Status,
+			StatusChangedBy: item.StatusChangedBy,
+		}
+		if !item.StatusChangedAt.IsZero() {
+			changedAt := item.StatusChangedAt
+			resItem.StatusChangedAt = &changedAt
 		}
 		res = append(res, resItem)
 	}
 	return res
 }
 
+// ToConfirmation converts a model.Confirmation to dto.Confirmation
+func ToConfirmation(m *model.Confirmation) *Confirmation {
+	return ToConfirmationDTO([]*model.Confirmation{m})[0]
+}
+
+// UpdateConfirmationStatusRequest moves a confirmation to another stage of its lifecycle. A draft can be
+// submitted, a submitted confirmation locked once it is scheduled or sent back to draft, a locked one
+// submitted again to be corrected, and any of them archived.
+type UpdateConfirmationStatusRequest struct {
+	Status model.ConfirmationStatus `json:"status" validate:"required,oneof=draft submitted locked archived" example:"submitted"`
+}
+
+// Validate does some simple validation on the UpdateConfirmationStatusRequest object per annotations
+func (r *UpdateConfirmationStatusRequest) Validate() error {
+	return validator.New().Struct(r)
+}
+
 
 // Validate does some simple validation on the NewMerchant object per annotations
 func (nt *NewConfirmation) Validate() error {
@@ -106,6 +197,8 @@ func (nt *NewConfirmation) ToModel(id string) *model.Confirmation {
 		id,
 		nt.ConfirmationName,
 		nt.CreateDate,
+		nt.BellScheduleID,
+		nt.TermID,
 	)
 }
 
@@ -0,0 +1,49 @@
+package dto
+
+import "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+
+// ConfirmationConflicts is the report of problems in the details of a confirmation which
+// would stop lessons from being scheduled
+type ConfirmationConflicts struct {
+	ConfirmationID string `json:"confirmationId"`
+	// Set when any of the lists below is not empty
+	HasConflicts bool `json:"hasConflicts"`
+	// Students asking for more than one lesson in the same day and period
+	DoubleBookings []*DoubleBooking `json:"doubleBookings"`
+	// Subjects requested by fewer students than the subject minimum in a day and period
+	UnderfilledSubjects []*UnderfilledSubject `json:"underfilledSubjects"`
+	// Subject ids which do not resolve to an existing subject
+	UnknownSubjects []*UnknownSubject `json:"unknownSubjects"`
+}
+
+// DoubleBooking is a student booked for several lessons in one day and period
+type DoubleBooking struct {
+	StudentID   string            `json:"studentId,omitempty"`
+	StudentName string            `json:"studentName"`
+	Day         model.Day         `json:"day"`
+	Period      model.Period      `json:"period"`
+	Lessons     []*ConflictLesson `json:"lessons"`
+}
+
+// ConflictLesson is a single subject requested on a confirmation detail
+type ConflictLesson struct {
+	ConfirmationDetailID string `json:"confirmationDetailId"`
+	SubjectID            string `json:"subjectId"`
+}
+
+// UnderfilledSubject is a subject in a day and period with too few students to run a class
+type UnderfilledSubject struct {
+	SubjectID             string       `json:"subjectId"`
+	SubjectName           string       `json:"subjectName"`
+	Day                   model.Day    `json:"day"`
+	Period                model.Period `json:"period"`
+	Students              int          `json:"students"`
+	MinOfStudent          int          `json:"minOfStudent"`
+	ConfirmationDetailIDs []string     `json:"confirmationDetailIds"`
+}
+
+// UnknownSubject is a subject id referenced by confirmation details which does not exist
+type UnknownSubject struct {
+	SubjectID             string   `json:"subjectId"`
+	ConfirmationDetailIDs []string `json:"confirmationDetailIds"`
+}
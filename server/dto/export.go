@@ -0,0 +1,20 @@
+package dto
+
+// Export is a table to stream into a spreadsheet, the rows are only read when the spreadsheet is written
+type Export struct {
+	// Name is used for the file and worksheet, without an extension
+	Name string
+	// Columns is the header of the spreadsheet
+	Columns []string
+	// Rows calls write with every row in order and stops at the first error
+	Rows func(write func(row []string) error) error
+	// Paged exports read their rows a page at a time while they are written, the rows of the others are
+	// read before the response is committed so that their errors can still be reported
+	Paged bool
+}
+
+// ExportColumns are the columns of exported confirmations and schedules, a student taking a subject
+var ExportColumns = []string{"Student", "Subject", "Main Subject", "Teacher", "Day", "Period"}
+
+// SessionExportColumns are the columns of exported sessions, which take place on a date
+var SessionExportColumns = append([]string{"Date"}, ExportColumns...)
@@ -0,0 +1,133 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+// ImportResult reports what an import made of the rows of an uploaded spreadsheet
+type ImportResult struct {
+	// DryRun is set when the rows were only checked, nothing was written
+	DryRun bool `json:"dryRun" example:"false"`
+	// Rows is the number of data rows in the spreadsheet, blank rows are not counted
+	Rows int `json:"rows" example:"120"`
+	// Imported is the number of rows written, or which would have been written on a dry run
+	Imported int `json:"imported" example:"118"`
+	// Rejected are the rows which were not written and why
+	Rejected []*RejectedRow `json:"rejected"`
+}
+
+// RejectedRow is a row of an uploaded spreadsheet which could not be imported
+type RejectedRow struct {
+	// Line of the row in the spreadsheet, the header is line 1
+	Line  int    `json:"line" example:"7"`
+	Error string `json:"error" example:"period 9 is not a teaching period of bell schedule Regular day"`
+}
+
+// NewImportResult is a constructor for ImportResult
+func NewImportResult(rows int, dryRun bool) *ImportResult {
+	return &ImportResult{DryRun: dryRun, Rows: rows, Rejected: make([]*RejectedRow, 0)}
+}
+
+// Reject records that the row on line could not be imported
+func (ir *ImportResult) Reject(line int, err error) {
+	ir.Rejected = append(ir.Rejected, newRejectedRow(line, err))
+}
+
+// ImportSummary reports what an import of teachers or subjects made of the rows of an uploaded spreadsheet
+type ImportSummary struct {
+	// DryRun is set when the rows were only checked, nothing was written
+	DryRun bool `json:"dryRun" example:"false"`
+	// Rows is the number of data rows in the spreadsheet, blank rows are not counted
+	Rows int `json:"rows" example:"40"`
+	// Created is the number of rows added as new entities, or which would have been on a dry run
+	Created int `json:"created" example:"35"`
+	// Updated is the number of rows which replaced an existing entity, or which would have on a dry run
+	Updated int `json:"updated" example:"4"`
+	// Rejected are the rows which were not written and why
+	Rejected []*RejectedRow `json:"rejected"`
+	// MainSubjectsCreated names the main subjects created for the rows, or which would have been on a dry run
+	MainSubjectsCreated []string `json:"mainSubjectsCreated" example:"Music"`
+}
+
+// NewImportSummary is a constructor for ImportSummary
+func NewImportSummary(rows int, dryRun bool) *ImportSummary {
+	return &ImportSummary{DryRun: dryRun, Rows: rows, Rejected: make([]*RejectedRow, 0), MainSubjectsCreated: make([]string, 0)}
+}
+
+// Reject records that the row on line could not be imported
+func (is *ImportSummary) Reject(line int, err error) {
+	is.Rejected = append(is.Rejected, newRejectedRow(line, err))
+}
+
+// newRejectedRow reports validation errors without their generic prefix
+func newRejectedRow(line int, err error) *RejectedRow {
+	var errValidation *c.ErrValidation
+	if errors.As(err, &errValidation) {
+		err = errValidation.Violations
+	}
+	return &RejectedRow{Line: line, Error: err.Error()}
+}
+
+// checkColumns rejects a spreadsheet with columns other than the known ones or without one of the
+// required ones, required lists alternatives separated by |
+func checkColumns(sheet *util.Sheet, known []string, required ...string) error {
+	knownKeys := make(map[string]bool, len(known))
+	for _, k := range known {
+		knownKeys[util.ColumnKey(k)] = true
+	}
+	present := make(map[string]bool, len(sheet.Columns))
+	unknown := make([]string, 0)
+	for _, column := range sheet.Columns {
+		present[column] = true
+		if !knownKeys[column] {
+			unknown = append(unknown, column)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return &c.ErrValidation{Violations: fmt.Errorf("unknown columns %v, the columns are %v", unknown, known)}
+	}
+
+	for _, r := range required {
+		found := false
+		for _, alt := range strings.Split(r, "|") {
+			found = found || present[util.ColumnKey(alt)]
+		}
+		if !found {
+			return &c.ErrValidation{Violations: fmt.Errorf("missing column %v", strings.ReplaceAll(r, "|", " or "))}
+		}
+	}
+	return nil
+}
+
+// splitList reads a cell holding several values separated by commas or semicolons, it is nil for an
+// empty cell so the list fails required validation
+func splitList(cell string) []string {
+	var list []string
+	for _, v := range strings.FieldsFunc(cell, func(r rune) bool { return r == ',' || r == ';' }) {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// intCell reads a cell holding a whole number, an empty cell is 0
+func intCell(row *util.SheetRow, column string) (int, error) {
+	value := row.Get(column)
+	if len(value) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v value %q", column, value)
+	}
+	return n, nil
+}
@@ -193,7 +193,7 @@ func (kyms *UpdateKymStatusRequest) Validate() error {
 
 func ToKymDTO(kymList []*model.Kym) []*KymResponse {
 
-	var kymRes []*KymResponse
+	kymRes := make([]*KymResponse, 0, len(kymList))
 
 	for _, item := range kymList {
 		var kymResItem = &KymResponse{
@@ -11,6 +11,11 @@ type NewMainSubject struct {
 	MainSubjectName string `json:"mainSubjectName" validate:"required"`
 }
 
+// PatchMainSubject struct used for partially updating a MainSubject, only the fields present are changed
+type PatchMainSubject struct {
+	MainSubjectName *string `json:"mainSubjectName" validate:"omitempty,min=1"`
+}
+
 // MainSubject struct used for updating Teacher
 type MainSubject struct {
 	NewMainSubject
@@ -19,7 +24,7 @@ type MainSubject struct {
 
 func ToMainSubjectDTO(mainSubjectList []*model.MainSubject) []*MainSubject {
 
-	var mainSubjectRes []*MainSubject
+	mainSubjectRes := make([]*MainSubject, 0, len(mainSubjectList))
 
 	for _, item := range mainSubjectList {
 		var mainSubjectResItem = &MainSubject{
@@ -50,3 +55,19 @@ func (nt *NewMainSubject) ToModel(id string) *model.MainSubject {
 	)
 }
 
+// ToMainSubject converts a model.MainSubject to dto.MainSubject
+func ToMainSubject(m *model.MainSubject) *MainSubject {
+	return ToMainSubjectDTO([]*model.MainSubject{m})[0]
+}
+
+// Validate does some simple validation on the PatchMainSubject object per annotations
+func (pm *PatchMainSubject) Validate() error {
+	return validator.New().Struct(pm)
+}
+
+// ApplyTo copies the fields present in the patch onto the main subject
+func (pm *PatchMainSubject) ApplyTo(m *model.MainSubject) {
+	if pm.MainSubjectName != nil {
+		m.MainSubjectName = *pm.MainSubjectName
+	}
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// DefaultPageSize is the number of items returned when a list request does not set pageSize
+	DefaultPageSize = 30
+	// MaxPageSize is the largest pageSize a list request may ask for
+	MaxPageSize = 100
+)
+
+// PageRequest selects a page of a list endpoint
+type PageRequest struct {
+	// Number of items to return
+	PageSize int `json:"pageSize" validate:"min=1,max=100" example:"30"`
+	// The nextPageToken of the previous page, empty for the first page
+	PageToken string `json:"pageToken"`
+}
+
+// Page is the envelope returned by every list endpoint
+type Page struct {
+	Items interface{} `json:"items"`
+	// Token to pass as pageToken to get the next page, empty on the last page
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// NewPageRequest returns a PageRequest for the first page of the default size
+func NewPageRequest() *PageRequest {
+	return &PageRequest{PageSize: DefaultPageSize}
+}
+
+// Validate does some simple validation on the PageRequest object per annotations
+func (p *PageRequest) Validate() error {
+	return validator.New().Struct(p)
+}
+
+// NewPage wraps the items of a single page together with the token of the next page
+func NewPage(items interface{}, nextPageToken string) *Page {
+	return &Page{Items: items, NextPageToken: nextPageToken}
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// NewRoom struct used for creating and replacing rooms
+type NewRoom struct {
+	Name string `json:"name" validate:"required" example:"Music room 1"`
+	// Largest number of students a class in the room may have
+	Capacity int `json:"capacity" validate:"required,min=1" example:"12"`
+	// Features of the room matched against the requiredFeatures of subjects, e.g. lab or piano
+	Features []string `json:"features" validate:"dive,required" example:"piano"`
+}
+
+// Room struct used for reading rooms
+type Room struct {
+	NewRoom
+	ID string `json:"id" validate:"required"`
+}
+
+// Validate does some simple validation on the NewRoom object per annotations
+func (nr *NewRoom) Validate() error {
+	return validator.New().Struct(nr)
+}
+
+// ToModel converts dto.NewRoom to model.Room
+func (nr *NewRoom) ToModel(id string) *model.Room {
+	return model.NewRoom(id, nr.Name, nr.Capacity, nr.Features)
+}
+
+// ToRoomDTO converts a list of model.Room to dto.Room
+func ToRoomDTO(list []*model.Room) []*Room {
+	res := make([]*Room, 0, len(list))
+	for _, item := range list {
+		features := item.Features
+		if features == nil {
+			features = make([]string, 0)
+		}
+		res = append(res, &Room{
+			ID: item.ID,
+			NewRoom: NewRoom{
+				Name:     item.Name,
+				Capacity: item.Capacity,
+				Features: features,
+			},
+		})
+	}
+	return res
+}
+
+// ToRoom converts a model.Room to dto.Room
+func ToRoom(r *model.Room) *Room {
+	return ToRoomDTO([]*model.Room{r})[0]
+}
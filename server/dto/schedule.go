@@ -0,0 +1,131 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// NewSchedule struct used for requesting a timetable to be generated
+type NewSchedule struct {
+	// The confirmation whose details should be scheduled
+	ConfirmationID string `json:"confirmationId" validate:"required" example:"7b2f0c3e-1d6a-4c1e-9a57-2f1f4d3c9e10"`
+}
+
+// Schedule is the response for a generated timetable
+type Schedule struct {
+	ID             string               `json:"id"`
+	ConfirmationID string               `json:"confirmationId"`
+	BellScheduleID string               `json:"bellScheduleId,omitempty"`
+	TermID         string               `json:"termId,omitempty"`
+	Created        time.Time            `json:"created"`
+	Slots          []*ScheduleSlot      `json:"slots"`
+	Unscheduled    []*UnscheduledLesson `json:"unscheduled"`
+}
+
+// ScheduleSlot is a single class of a Schedule
+type ScheduleSlot struct {
+	ID                    string       `json:"id"`
+	TeacherID             string       `json:"teacherId"`
+	SubjectID             string       `json:"subjectId"`
+	Day                   model.Day    `json:"day"`
+	Period                model.Period `json:"period"`
+	RoomID                string       `json:"roomId,omitempty"`
+	StudentNames          []string     `json:"studentNames"`
+	StudentIDs            []string     `json:"studentIds,omitempty"`
+	ConfirmationDetailIDs []string     `json:"confirmationDetailIds"`
+	PeriodTime
+}
+
+// UnscheduledLesson is a requested lesson which could not be placed
+type UnscheduledLesson struct {
+	ConfirmationDetailID string       `json:"confirmationDetailId"`
+	StudentID            string       `json:"studentId,omitempty"`
+	StudentName          string       `json:"studentName"`
+	SubjectID            string       `json:"subjectId"`
+	Day                  model.Day    `json:"day"`
+	Period               model.Period `json:"period"`
+	Reason               string       `json:"reason"`
+	PeriodTime
+}
+
+// Validate does some simple validation on the NewSchedule object per annotations
+func (ns *NewSchedule) Validate() error {
+	return validator.New().Struct(ns)
+}
+
+// ToScheduleDTO converts a model.Schedule and its slots to dto.Schedule, the periods are resolved
+// against the bell schedule when one is given
+func ToScheduleDTO(s *model.Schedule, slots []*model.ScheduleSlot, bell *model.BellSchedule) *Schedule {
+	res := &Schedule{
+		ID:             s.ID,
+		ConfirmationID: s.ConfirmationID,
+		BellScheduleID: s.BellScheduleID,
+		TermID:         s.TermID,
+		Created:        s.Created,
+		Slots:          ToScheduleSlotDTO(slots, bell),
+		Unscheduled:    ToUnscheduledLessonDTO(s.Unscheduled, bell),
+	}
+	return res
+}
+
+// ToUnscheduledLessonDTO converts a list of model.UnscheduledLesson to dto.UnscheduledLesson
+func ToUnscheduledLessonDTO(list []model.UnscheduledLesson, bell *model.BellSchedule) []*UnscheduledLesson {
+	res := make([]*UnscheduledLesson, 0, len(list))
+	for _, item := range list {
+		res = append(res, &UnscheduledLesson{
+			ConfirmationDetailID: item.ConfirmationDetailID,
+			StudentID:            item.StudentID,
+			StudentName:          item.StudentName,
+			SubjectID:            item.SubjectID,
+			Day:                  item.Day,
+			Period:               item.Period,
+			PeriodTime:           NewPeriodTime(bell, item.Period),
+			Reason:               item.Reason,
+		})
+	}
+	return res
+}
+
+// ToScheduleSlotDTO converts a list of model.ScheduleSlot to dto.ScheduleSlot
+func ToScheduleSlotDTO(slots []*model.ScheduleSlot, bell *model.BellSchedule) []*ScheduleSlot {
+	res := make([]*ScheduleSlot, 0, len(slots))
+	for _, item := range slots {
+		res = append(res, &ScheduleSlot{
+			ID:                    item.ID,
+			TeacherID:             item.TeacherID,
+			SubjectID:             item.SubjectID,
+			Day:                   item.Day,
+			Period:                item.Period,
+			RoomID:                item.RoomID,
+			PeriodTime:            NewPeriodTime(bell, item.Period),
+			StudentNames:          item.StudentNames,
+			StudentIDs:            item.StudentIDs,
+			ConfirmationDetailIDs: item.ConfirmationDetailIDs,
+		})
+	}
+	return res
+}
+
+// ScheduleSessions is a schedule expanded into the dated sessions of its term
+type ScheduleSessions struct {
+	ScheduleID string     `json:"scheduleId"`
+	TermID     string     `json:"termId"`
+	Sessions   []*Session `json:"sessions"`
+}
+
+// Session is a single class of a schedule taking place on a date
+type Session struct {
+	// Date of the session formatted as 2006-01-02
+	Date           string       `json:"date" example:"2022-05-16"`
+	Day            model.Day    `json:"day"`
+	Period         model.Period `json:"period"`
+	ScheduleSlotID string       `json:"scheduleSlotId"`
+	TeacherID      string       `json:"teacherId"`
+	SubjectID      string       `json:"subjectId"`
+	RoomID         string       `json:"roomId,omitempty"`
+	StudentNames   []string     `json:"studentNames"`
+	StudentIDs     []string     `json:"studentIds,omitempty"`
+	PeriodTime
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// NewStudent struct used for creating and replacing students
+type NewStudent struct {
+	Name  string `json:"name" validate:"required" example:"Somchai Jaidee"`
+	Level string `json:"level" validate:"required" example:"P.4"`
+	// Contact of the student's guardian
+	GuardianName  string `json:"guardianName" example:"Somsri Jaidee"`
+	GuardianPhone string `json:"guardianPhone" example:"0812345678"`
+	GuardianEmail string `json:"guardianEmail" validate:"omitempty,email" example:"somsri@example.com"`
+	Notes         string `json:"notes" example:"Allergic to peanuts"`
+}
+
+// Student struct used for reading students
+type Student struct {
+	NewStudent
+	ID string `json:"id" validate:"required"`
+}
+
+// Validate does some simple validation on the NewStudent object per annotations
+func (ns *NewStudent) Validate() error {
+	return validator.New().Struct(ns)
+}
+
+// ToModel converts dto.NewStudent to model.Student
+func (ns *NewStudent) ToModel(id string) *model.Student {
+	return model.NewStudent(id, ns.Name, ns.Level, ns.GuardianName, ns.GuardianPhone, ns.GuardianEmail, ns.Notes)
+}
+
+// ToStudentDTO converts a list of model.Student to dto.Student
+func ToStudentDTO(list []*model.Student) []*Student {
+	res := make([]*Student, 0, len(list))
+	for _, item := range list {
+		res = append(res, &Student{
+			ID: item.ID,
+			NewStudent: NewStudent{
+				Name:          item.Name,
+				Level:         item.Level,
+				GuardianName:  item.GuardianName,
+				GuardianPhone: item.GuardianPhone,
+				GuardianEmail: item.GuardianEmail,
+				Notes:         item.Notes,
+			},
+		})
+	}
+	return res
+}
+
+// ToStudent converts a model.Student to dto.Student
+func ToStudent(s *model.Student) *Student {
+	return ToStudentDTO([]*model.Student{s})[0]
+}
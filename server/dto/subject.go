@@ -3,6 +3,7 @@ package dto
 import (
 	"github.com/go-playground/validator/v10"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
 )
 
 
@@ -10,8 +11,26 @@ type NewSubject struct {
 	SubjectName string `json:"subjectName" validate:"required"`
 	MainSubjectId string `json:"mainSubjectId" validate:"required"`
 	MinOfStudent int `json:"minOfStudent" validate:"required"`
+	// Features a room needs for the subject to be taught in it, e.g. lab or piano
+	RequiredFeatures []string `json:"requiredFeatures,omitempty" validate:"dive,required" example:"piano"`
 }
 
+// PatchSubject struct used for partially updating a Subject, only the fields present are changed
+type PatchSubject struct {
+	SubjectName   *string `json:"subjectName" validate:"omitempty,min=1"`
+	MainSubjectId *string `json:"mainSubjectId" validate:"omitempty,min=1"`
+	MinOfStudent  *int    `json:"minOfStudent" validate:"omitempty,min=1"`
+	// Replaces every required feature, an empty list removes them
+	RequiredFeatures *[]string `json:"requiredFeatures" validate:"omitempty,dive,required"`
+}
+
+// SubjectQuery holds the filters of a subject listing.
+// Subjects a TeacherID is qualified for are found through two lookups, so it cannot be combined with the other filters.
+type SubjectQuery struct {
+	TeacherID      string `json:"teacherId" validate:"excluded_with=MainSubjectID MinStudentsGte"`
+	MainSubjectID  string `json:"mainSubjectId"`
+	MinStudentsGte int    `json:"minStudentsGte" validate:"min=0"`
+}
 
 type Subject struct {
 	NewSubject
@@ -20,7 +39,7 @@ type Subject struct {
 
 func ToSubjectDTO(subjectList []*model.Subject) []*Subject {
 
-	var subjectRes []*Subject
+	subjectRes := make([]*Subject, 0, len(subjectList))
 
 	for _, item := range subjectList {
 		var mainSubjectResItem = &Subject{
@@ -29,6 +48,7 @@ func ToSubjectDTO(subjectList []*model.Subject) []*Subject {
 				SubjectName: item.SubjectName,
 				MainSubjectId: item.MainSubjectId,
 				MinOfStudent: item.MinOfStudent,
+				RequiredFeatures: item.RequiredFeatures,
 			},
 		}
 		subjectRes = append(subjectRes, mainSubjectResItem)
@@ -52,6 +72,87 @@ func (ns *NewSubject) ToModel(id string) *model.Subject {
 		ns.SubjectName,
 		ns.MainSubjectId,
 		ns.MinOfStudent,
+		ns.RequiredFeatures,
 	)
 }
 
+// ToSubject converts a model.Subject to dto.Subject
+func ToSubject(m *model.Subject) *Subject {
+	return ToSubjectDTO([]*model.Subject{m})[0]
+}
+
+// Validate does some simple validation on the SubjectQuery object per annotations
+func (sq *SubjectQuery) Validate() error {
+	return validator.New().Struct(sq)
+}
+
+// Validate does some simple validation on the PatchSubject object per annotations
+func (ps *PatchSubject) Validate() error {
+	return validator.New().Struct(ps)
+}
+
+// ApplyTo copies the fields present in the patch onto the subject
+func (ps *PatchSubject) ApplyTo(m *model.Subject) {
+	if ps.SubjectName != nil {
+		m.SubjectName = *ps.SubjectName
+	}
+	if ps.MainSubjectId != nil {
+		m.MainSubjectId = *ps.MainSubjectId
+	}
+	if ps.MinOfStudent != nil {
+		m.MinOfStudent = *ps.MinOfStudent
+	}
+	if ps.RequiredFeatures != nil {
+		m.RequiredFeatures = model.NormalizeFeatures(*ps.RequiredFeatures)
+	}
+}
+
+// SubjectRow is a subject read from a row of an uploaded spreadsheet
+type SubjectRow struct {
+	// Line of the row in the spreadsheet, the header is line 1
+	Line int
+	// ID of the subject the row replaces, when empty the row replaces the subject with the same name or
+	// creates a new one
+	ID string
+	// Subject is read from the row, its MainSubjectId holds the name of the main subject until the import
+	// resolves it
+	Subject *NewSubject
+	// KeepFeatures is set when the spreadsheet has no requiredFeatures column, a replaced subject keeps its features
+	KeepFeatures bool
+	// Err is set when a cell of the row could not be read
+	Err error
+}
+
+// subjectColumns are the columns of a spreadsheet of subjects, requiredFeatures holds the features
+// separated by commas or semicolons
+var subjectColumns = []string{"id", "subjectName", "mainSubject", "minOfStudent", "requiredFeatures"}
+
+// ToSubjectRows reads the rows of a spreadsheet of subjects, the main subject is given by name
+func ToSubjectRows(sheet *util.Sheet) ([]*SubjectRow, error) {
+	if err := checkColumns(sheet, subjectColumns, "subjectName", "mainSubject", "minOfStudent"); err != nil {
+		return nil, err
+	}
+
+	keepFeatures := true
+	for _, column := range sheet.Columns {
+		keepFeatures = keepFeatures && column != util.ColumnKey("requiredFeatures")
+	}
+
+	res := make([]*SubjectRow, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		minOfStudent, err := intCell(row, "minOfStudent")
+		res = append(res, &SubjectRow{
+			Line: row.Line,
+			ID:   row.Get("id"),
+			Subject: &NewSubject{
+				SubjectName:      row.Get("subjectName"),
+				MainSubjectId:    row.Get("mainSubject"),
+				MinOfStudent:     minOfStudent,
+				RequiredFeatures: splitList(row.Get("requiredFeatures")),
+			},
+			KeepFeatures: keepFeatures,
+			Err:          err,
+		})
+	}
+	return res, nil
+}
@@ -1,8 +1,11 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
 )
 
 // NewTeacher struct used for creating new teacher requests
@@ -15,10 +18,66 @@ type NewTeacher struct {
 	LastName string `json:"lastName" validate:"required" example:"merchant company"`
 	// The merchant's main contact number
 	ContactNumber string `json:"contactNumber" validate:"required" example:"012345678"`
-	// Only THB is supported currently
-	Capacity string `json:"capacity" validate:"required"`
+	// Workload limits of the teacher
+	Capacity TeacherCapacity `json:"capacity" validate:"required"`
 	// Only THB is supported currently
 	MainSubjectID string `json:"mainSubjectID" validate:"required"`
+	// Weekly windows the teacher is able to teach in, leave empty if the teacher is always available
+	Availability []AvailabilityWindow `json:"availability" validate:"dive"`
+	// Date ranges the teacher is away regardless of the weekly windows
+	AvailabilityExceptions []AvailabilityException `json:"availabilityExceptions" validate:"dive"`
+}
+
+// PatchTeacher struct used for partially updating a Teacher, only the fields present are changed
+type PatchTeacher struct {
+	NickName               *string                  `json:"nickName" validate:"omitempty,min=1"`
+	FirstName              *string                  `json:"firstName" validate:"omitempty,min=1"`
+	LastName               *string                  `json:"lastName" validate:"omitempty,min=1"`
+	ContactNumber          *string                  `json:"contactNumber" validate:"omitempty,min=1"`
+	Capacity               *TeacherCapacity         `json:"capacity"`
+	MainSubjectID          *string                  `json:"mainSubjectID" validate:"omitempty,min=1"`
+	Availability           *[]AvailabilityWindow    `json:"availability" validate:"omitempty,dive"`
+	AvailabilityExceptions *[]AvailabilityException `json:"availabilityExceptions" validate:"omitempty,dive"`
+}
+
+// TeacherQuery holds the filters and order of a teacher listing.
+// Teachers qualified for SubjectID are found through two lookups, so it cannot be combined with the other options.
+type TeacherQuery struct {
+	SubjectID     string `json:"subjectId" validate:"excluded_with=MainSubjectID Q Sort"`
+	MainSubjectID string `json:"mainSubjectId"`
+	// Matches teachers whose first, nick or last name starts with it, ignoring case
+	Q    string `json:"q"`
+	Sort string `json:"sort" validate:"omitempty,oneof=firstName nickName lastName"`
+}
+
+// TeacherCapacity defines the workload limits of a teacher
+type TeacherCapacity struct {
+	MaxPeriodsPerDay     int `json:"maxPeriodsPerDay" validate:"min=1" example:"4"`
+	MaxPeriodsPerWeek    int `json:"maxPeriodsPerWeek" validate:"min=1,gtefield=MaxPeriodsPerDay" example:"18"`
+	MaxStudentsPerPeriod int `json:"maxStudentsPerPeriod" validate:"min=1" example:"8"`
+}
+
+// AvailabilityWindow is a range of periods on a day of the week, inclusive of both ends
+type AvailabilityWindow struct {
+	Day         model.Day    `json:"day" validate:"required,oneof=monday tuesday wednesday thursday friday saturday sunday" example:"tuesday"`
+	StartPeriod model.Period `json:"startPeriod" validate:"min=1" example:"5"`
+	EndPeriod   model.Period `json:"endPeriod" validate:"gtefield=StartPeriod" example:"8"`
+	// Read only, when the window starts and ends according to the bell schedule
+	StartTime string `json:"startTime,omitempty" example:"13:00"`
+	EndTime   string `json:"endTime,omitempty" example:"15:30"`
+}
+
+// AvailabilityException is a range of dates the teacher is unavailable, inclusive of both ends
+type AvailabilityException struct {
+	StartDate time.Time `json:"startDate" validate:"required" example:"2022-01-10T00:00:00Z"`
+	EndDate   time.Time `json:"endDate" validate:"required,gtefield=StartDate" example:"2022-01-14T00:00:00Z"`
+	Reason    string    `json:"reason" example:"annual leave"`
+}
+
+// TeacherAvailability struct used for reading and replacing a teacher's availability
+type TeacherAvailability struct {
+	Availability           []AvailabilityWindow    `json:"availability" validate:"dive"`
+	AvailabilityExceptions []AvailabilityException `json:"availabilityExceptions" validate:"dive"`
 }
 
 // Teacher struct used for updating Teacher
@@ -29,7 +88,7 @@ type Teacher struct {
 
 func ToTeacherDTO(teacherList []*model.Teacher) []*Teacher {
 
-	var teacherRes []*Teacher
+	teacherRes := make([]*Teacher, 0, len(teacherList))
 
 	for _, item := range teacherList {
 		var teacherResItem = &Teacher{
@@ -37,9 +96,12 @@ func ToTeacherDTO(teacherList []*model.Teacher) []*Teacher {
 			NewTeacher: NewTeacher{
 				NickName: item.NickName,
 				FirstName: item.FirstName,
-				LastName: item.ContactNumber,
-				Capacity: item.Capacity,
+				LastName: item.LastName,
+				ContactNumber: item.ContactNumber,
+				Capacity: ToTeacherCapacityDTO(item.Capacity),
 				MainSubjectID: item.MainSubjectID,
+				Availability: ToAvailabilityWindowDTO(item.Availability),
+				AvailabilityExceptions: ToAvailabilityExceptionDTO(item.AvailabilityExceptions),
 			},
 		}
 		teacherRes = append(teacherRes, teacherResItem)
@@ -60,12 +122,206 @@ func (nt *NewTeacher) Validate() error {
 func (nt *NewTeacher) ToModel(id string) *model.Teacher {
 	return model.NewTeacher(
 		id,
-		nt.NickName,
 		nt.FirstName,
+		nt.NickName,
 		nt.LastName,
 		nt.ContactNumber,
-		nt.Capacity,
+		nt.Capacity.ToModel(),
 		nt.MainSubjectID,
+		ToAvailabilityWindowModel(nt.Availability),
+		ToAvailabilityExceptionModel(nt.AvailabilityExceptions),
 	)
 }
 
+// ToTeacher converts a model.Teacher to dto.Teacher
+func ToTeacher(t *model.Teacher) *Teacher {
+	return ToTeacherDTO([]*model.Teacher{t})[0]
+}
+
+// Validate does some simple validation on the TeacherQuery object per annotations
+func (tq *TeacherQuery) Validate() error {
+	return validator.New().Struct(tq)
+}
+
+// Validate does some simple validation on the PatchTeacher object per annotations
+func (pt *PatchTeacher) Validate() error {
+	return validator.New().Struct(pt)
+}
+
+// ApplyTo copies the fields present in the patch onto the teacher
+func (pt *PatchTeacher) ApplyTo(t *model.Teacher) {
+	if pt.NickName != nil {
+		t.NickName = *pt.NickName
+	}
+	if pt.FirstName != nil {
+		t.FirstName = *pt.FirstName
+	}
+	if pt.LastName != nil {
+		t.LastName = *pt.LastName
+	}
+	if pt.ContactNumber != nil {
+		t.ContactNumber = *pt.ContactNumber
+	}
+	if pt.Capacity != nil {
+		t.Capacity = pt.Capacity.ToModel()
+	}
+	if pt.MainSubjectID != nil {
+		t.MainSubjectID = *pt.MainSubjectID
+	}
+	if pt.Availability != nil {
+		t.Availability = ToAvailabilityWindowModel(*pt.Availability)
+	}
+	if pt.AvailabilityExceptions != nil {
+		t.AvailabilityExceptions = ToAvailabilityExceptionModel(*pt.AvailabilityExceptions)
+	}
+}
+
+// ToTeacherCapacityDTO converts model.TeacherCapacity to dto.TeacherCapacity
+func ToTeacherCapacityDTO(tc model.TeacherCapacity) TeacherCapacity {
+	return TeacherCapacity{
+		MaxPeriodsPerDay:     tc.MaxPeriodsPerDay,
+		MaxPeriodsPerWeek:    tc.MaxPeriodsPerWeek,
+		MaxStudentsPerPeriod: tc.MaxStudentsPerPeriod,
+	}
+}
+
+// ToModel converts dto.TeacherCapacity to model.TeacherCapacity
+func (tc TeacherCapacity) ToModel() model.TeacherCapacity {
+	return model.TeacherCapacity{
+		MaxPeriodsPerDay:     tc.MaxPeriodsPerDay,
+		MaxPeriodsPerWeek:    tc.MaxPeriodsPerWeek,
+		MaxStudentsPerPeriod: tc.MaxStudentsPerPeriod,
+	}
+}
+
+// ToTeacherAvailabilityDTO converts the availability of a model.Teacher to dto.TeacherAvailability
+func ToTeacherAvailabilityDTO(t *model.Teacher) *TeacherAvailability {
+	return &TeacherAvailability{
+		Availability:           ToAvailabilityWindowDTO(t.Availability),
+		AvailabilityExceptions: ToAvailabilityExceptionDTO(t.AvailabilityExceptions),
+	}
+}
+
+// Validate does some simple validation on the TeacherAvailability object per annotations
+func (ta *TeacherAvailability) Validate() error {
+	return validator.New().Struct(ta)
+}
+
+// ToAvailabilityWindowDTO converts a list of model.AvailabilityWindow to dto.AvailabilityWindow
+func ToAvailabilityWindowDTO(windows []model.AvailabilityWindow) []AvailabilityWindow {
+	res := make([]AvailabilityWindow, 0, len(windows))
+	for _, w := range windows {
+		res = append(res, AvailabilityWindow{Day: w.Day, StartPeriod: w.StartPeriod, EndPeriod: w.EndPeriod})
+	}
+	return res
+}
+
+// ResolveAvailabilityTimes sets when the windows start and end according to the bell schedule, bell may be nil
+func ResolveAvailabilityTimes(windows []AvailabilityWindow, bell *model.BellSchedule) {
+	for i, w := range windows {
+		windows[i].StartTime = NewPeriodTime(bell, w.StartPeriod).StartTime
+		windows[i].EndTime = NewPeriodTime(bell, w.EndPeriod).EndTime
+	}
+}
+
+// ToAvailabilityWindowModel converts a list of dto.AvailabilityWindow to model.AvailabilityWindow
+func ToAvailabilityWindowModel(windows []AvailabilityWindow) []model.AvailabilityWindow {
+	res := make([]model.AvailabilityWindow, 0, len(windows))
+	for _, w := range windows {
+		res = append(res, model.AvailabilityWindow{Day: w.Day, StartPeriod: w.StartPeriod, EndPeriod: w.EndPeriod})
+	}
+	return res
+}
+
+// ToAvailabilityExceptionDTO converts a list of model.AvailabilityException to dto.AvailabilityException
+func ToAvailabilityExceptionDTO(exceptions []model.AvailabilityException) []AvailabilityException {
+	res := make([]AvailabilityException, 0, len(exceptions))
+	for _, e := range exceptions {
+		res = append(res, AvailabilityException{StartDate: e.StartDate, EndDate: e.EndDate, Reason: e.Reason})
+	}
+	return res
+}
+
+// ToAvailabilityExceptionModel converts a list of dto.AvailabilityException to model.AvailabilityException
+func ToAvailabilityExceptionModel(exceptions []AvailabilityException) []model.AvailabilityException {
+	res := make([]model.AvailabilityException, 0, len(exceptions))
+	for _, e := range exceptions {
+		res = append(res, model.AvailabilityException{StartDate: e.StartDate, EndDate: e.EndDate, Reason: e.Reason})
+	}
+	return res
+}
+
+
+// TeacherRow is a teacher read from a row of an uploaded spreadsheet
+type TeacherRow struct {
+	// Line of the row in the spreadsheet, the header is line 1
+	Line int
+	// ID of the teacher the row replaces, when empty the row replaces the teacher with the same first and
+	// last name or creates a new one
+	ID string
+	// Teacher is read from the row. Its MainSubjectID holds the name of the main subject until the import
+	// resolves it, the availability is not part of the spreadsheet.
+	Teacher *NewTeacher
+	// Err is set when a cell of the row could not be read
+	Err error
+}
+
+// teacherColumns are the columns of a spreadsheet of teachers
+var teacherColumns = []string{"id", "firstName", "nickName", "lastName", "contactNumber", "mainSubject",
+	"maxPeriodsPerDay", "maxPeriodsPerWeek", "maxStudentsPerPeriod"}
+
+// ToTeacherRows reads the rows of a spreadsheet of teachers, the main subject is given by name
+func ToTeacherRows(sheet *util.Sheet) ([]*TeacherRow, error) {
+	if err := checkColumns(sheet, teacherColumns, teacherColumns[1:]...); err != nil {
+		return nil, err
+	}
+
+	res := make([]*TeacherRow, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		tr := &TeacherRow{
+			Line: row.Line,
+			ID:   row.Get("id"),
+			Teacher: &NewTeacher{
+				FirstName:     row.Get("firstName"),
+				NickName:      row.Get("nickName"),
+				LastName:      row.Get("lastName"),
+				ContactNumber: row.Get("contactNumber"),
+				MainSubjectID: row.Get("mainSubject"),
+			},
+		}
+		capacity := &tr.Teacher.Capacity
+		for _, cell := range []struct {
+			column string
+			value  *int
+		}{
+			{"maxPeriodsPerDay", &capacity.MaxPeriodsPerDay},
+			{"maxPeriodsPerWeek", &capacity.MaxPeriodsPerWeek},
+			{"maxStudentsPerPeriod", &capacity.MaxStudentsPerPeriod},
+		} {
+			n, err := intCell(row, cell.column)
+			if err != nil && tr.Err == nil {
+				tr.Err = err
+			}
+			*cell.value = n
+		}
+		res = append(res, tr)
+	}
+	return res, nil
+}
+
+// TeacherWorkload is the booked load of a teacher in a schedule compared against the teacher's limits
+type TeacherWorkload struct {
+	TeacherID  string          `json:"teacherId"`
+	ScheduleID string          `json:"scheduleId"`
+	Capacity   TeacherCapacity `json:"capacity"`
+	// Number of periods booked on each day
+	PeriodsPerDay map[model.Day]int `json:"periodsPerDay"`
+	// Number of periods booked over the week
+	PeriodsPerWeek int `json:"periodsPerWeek"`
+	// Largest number of students booked in a single period
+	MaxStudentsInPeriod int `json:"maxStudentsInPeriod"`
+	// Distinct students taught over the week
+	DistinctStudents int `json:"distinctStudents"`
+	// Human readable descriptions of every limit that is exceeded
+	Violations []string `json:"violations"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+// TeacherCalendar is the weekly classes of a teacher in a schedule as recurring calendar events
+type TeacherCalendar struct {
+	TeacherID   string
+	TeacherName string
+	ScheduleID  string
+	// Generated is when the schedule the events come from was generated
+	Generated time.Time
+	// Location is the school's time zone, the times of the events are in it
+	Location *time.Location
+	Events   []*CalendarEvent
+}
+
+// CalendarEvent is a class taking place every week of the term
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	// Start and End are the times of the first class. AllDay is set when the bell schedule does not
+	// give the period a time, Start is then the date of the first class and End is unset.
+	Start  time.Time
+	End    time.Time
+	AllDay bool
+	// Until is the start of the last class of the term
+	Until time.Time
+	// ExDates are the starts of the weekly classes which fall on a holiday
+	ExDates []time.Time
+}
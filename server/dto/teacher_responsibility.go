@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// TeacherResponsibility is the response for a subject a teacher is explicitly qualified for
+type TeacherResponsibility struct {
+	ID        string `json:"id"`
+	TeacherID string `json:"teacherId"`
+	SubjectID string `json:"subjectId"`
+}
+
+// NewTeacherSubject struct used for qualifying a teacher for one more subject
+type NewTeacherSubject struct {
+	SubjectID string `json:"subjectId" validate:"required"`
+}
+
+// TeacherSubjects struct used for replacing all subjects a teacher is qualified for
+type TeacherSubjects struct {
+	SubjectIDs []string `json:"subjectIds" validate:"unique,dive,required"`
+}
+
+// Validate does some simple validation on the NewTeacherSubject object per annotations
+func (ts *NewTeacherSubject) Validate() error {
+	return validator.New().Struct(ts)
+}
+
+// Validate does some simple validation on the TeacherSubjects object per annotations
+func (ts *TeacherSubjects) Validate() error {
+	return validator.New().Struct(ts)
+}
+
+func ToTeacherResponsibilityDTO(list []*model.TeacherResponsibility) []*TeacherResponsibility {
+	res := make([]*TeacherResponsibility, 0, len(list))
+	for _, item := range list {
+		res = append(res, &TeacherResponsibility{
+			ID:        item.ID,
+			TeacherID: item.TeacherId,
+			SubjectID: item.SubjectId,
+		})
+	}
+	return res
+}
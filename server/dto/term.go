@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// NewTerm struct used for creating and replacing academic terms
+type NewTerm struct {
+	Name string `json:"name" validate:"required" example:"Term 1/2022"`
+	// First day of the term, the time of day is ignored
+	StartDate time.Time `json:"startDate" validate:"required" example:"2022-05-16T00:00:00+07:00"`
+	// Last day of the term, the time of day is ignored
+	EndDate time.Time `json:"endDate" validate:"required,gtefield=StartDate" example:"2022-09-30T00:00:00+07:00"`
+}
+
+// Term struct used for reading academic terms
+type Term struct {
+	NewTerm
+	ID string `json:"id" validate:"required"`
+}
+
+// NewHoliday struct used for creating and replacing holidays and school closures
+type NewHoliday struct {
+	Name string `json:"name" validate:"required" example:"Songkran"`
+	// First day without lessons, the time of day is ignored
+	StartDate time.Time `json:"startDate" validate:"required" example:"2022-04-13T00:00:00+07:00"`
+	// Last day without lessons, the time of day is ignored
+	EndDate time.Time `json:"endDate" validate:"required,gtefield=StartDate" example:"2022-04-15T00:00:00+07:00"`
+}
+
+// Holiday struct used for reading holidays and school closures
+type Holiday struct {
+	NewHoliday
+	ID string `json:"id" validate:"required"`
+}
+
+// Validate does some simple validation on the NewTerm object per annotations
+func (nt *NewTerm) Validate() error {
+	return validator.New().Struct(nt)
+}
+
+// ToModel converts dto.NewTerm to model.Term
+func (nt *NewTerm) ToModel(id string) *model.Term {
+	return model.NewTerm(id, nt.Name, nt.StartDate, nt.EndDate)
+}
+
+// ToTermDTO converts a list of model.Term to dto.Term
+func ToTermDTO(list []*model.Term) []*Term {
+	res := make([]*Term, 0, len(list))
+	for _, item := range list {
+		res = append(res, &Term{
+			ID: item.ID,
+			NewTerm: NewTerm{
+				Name:      item.Name,
+				StartDate: item.StartDate.UTC(),
+				EndDate:   item.EndDate.UTC(),
+			},
+		})
+	}
+	return res
+}
+
+// ToTerm converts a model.Term to dto.Term
+func ToTerm(t *model.Term) *Term {
+	return ToTermDTO([]*model.Term{t})[0]
+}
+
+// Validate does some simple validation on the NewHoliday object per annotations
+func (nh *NewHoliday) Validate() error {
+	return validator.New().Struct(nh)
+}
+
+// ToModel converts dto.NewHoliday to model.Holiday
+func (nh *NewHoliday) ToModel(id string) *model.Holiday {
+	return model.NewHoliday(id, nh.Name, nh.StartDate, nh.EndDate)
+}
+
+// ToHolidayDTO converts a list of model.Holiday to dto.Holiday
+func ToHolidayDTO(list []*model.Holiday) []*Holiday {
+	res := make([]*Holiday, 0, len(list))
+	for _, item := range list {
+		res = append(res, &Holiday{
+			ID: item.ID,
+			NewHoliday: NewHoliday{
+				Name:      item.Name,
+				StartDate: item.StartDate.UTC(),
+				EndDate:   item.EndDate.UTC(),
+			},
+		})
+	}
+	return res
+}
+
+// ToHoliday converts a model.Holiday to dto.Holiday
+func ToHoliday(h *model.Holiday) *Holiday {
+	return ToHolidayDTO([]*model.Holiday{h})[0]
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+
+// StudentTimetable is the week of a student in a schedule, grouped by day and period
+type StudentTimetable struct {
+	StudentID   string `json:"studentId"`
+	StudentName string `json:"studentName"`
+	Level       string `json:"level"`
+	ScheduleID  string `json:"scheduleId"`
+	// Days the student has lessons on in the order of the week
+	Days []*TimetableDay `json:"days"`
+	// Lessons the student asked for which could not be scheduled
+	Unscheduled []*UnscheduledLesson `json:"unscheduled"`
+}
+
+// TimetableDay is a day of a StudentTimetable
+type TimetableDay struct {
+	Day model.Day `json:"day" example:"monday"`
+	// Periods the student has a lesson in, in order
+	Periods []*TimetablePeriod `json:"periods"`
+}
+
+// TimetablePeriod is the lesson of a student in a period, a student has at most one lesson per period
+type TimetablePeriod struct {
+	Period         model.Period `json:"period" example:"1"`
+	ScheduleSlotID string       `json:"scheduleSlotId"`
+	SubjectID      string       `json:"subjectId"`
+	SubjectName    string       `json:"subjectName"`
+	TeacherID      string       `json:"teacherId"`
+	TeacherName    string       `json:"teacherName"`
+	RoomID         string       `json:"roomId,omitempty"`
+	RoomName       string       `json:"roomName,omitempty"`
+	PeriodTime
+}
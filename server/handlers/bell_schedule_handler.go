@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+// BellScheduleHandler is a handler for /bell-schedule path
+type BellScheduleHandler struct {
+	util                *util.HandlerUtil
+	bellScheduleService service.BellScheduleServiceInterface
+}
+
+func NewBellScheduleHandler(util *util.HandlerUtil, bellScheduleService service.BellScheduleServiceInterface) *BellScheduleHandler {
+	return &BellScheduleHandler{util: util, bellScheduleService: bellScheduleService}
+}
+
+// GetAllBellSchedule godoc
+// @Id GetAllBellSchedule
+// @Summary Get All Bell Schedule
+// @Description Returns a page of bell schedules
+// @Tags bellschedule
+// @Produce json
+// @Param pageSize query int false "number of items per page, defaults to 30 and is at most 100"
+// @Param pageToken query string false "nextPageToken of the previous page"
+// @Success 200 {object} dto.Page{items=[]dto.BellSchedule} "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /bell-schedule [get]
+func (h *BellScheduleHandler) GetAllBellSchedule(rw http.ResponseWriter, r *http.Request) {
+
+	page, err := pageParam(r)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	res, err := h.bellScheduleService.GetAllBellSchedule(page)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// AddBellSchedule godoc
+// @Id AddBellSchedule
+// @Summary Add Bell Schedule
+// @Description Creates a bell schedule, a default bell schedule replaces the previous default
+// @Tags bellschedule
+// @Produce json
+// @Accept json
+// @Param requestBody body dto.NewBellSchedule true "NewBellSchedule entity"
+// @Success 201 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /bell-schedule [post]
+func (h *BellScheduleHandler) AddBellSchedule(rw http.ResponseWriter, r *http.Request) {
+
+	req := &dto.NewBellSchedule{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing bell schedule : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.bellScheduleService.AddBellSchedule(req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusCreated)
+}
+
+// GetBellSchedule godoc
+// @Id GetBellSchedule
+// @Summary Get Bell Schedule
+// @Description Returns a single bell schedule
+// @Tags bellschedule
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} dto.BellSchedule "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /bell-schedule/{id} [get]
+func (h *BellScheduleHandler) GetBellSchedule(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.bellScheduleService.GetBellSchedule(id)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// UpdateBellSchedule godoc
+// @Id UpdateBellSchedule
+// @Summary Replace Bell Schedule
+// @Description Replaces every field of an existing bell schedule
+// @Tags bellschedule
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.NewBellSchedule true "NewBellSchedule entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /bell-schedule/{id} [put]
+func (h *BellScheduleHandler) UpdateBellSchedule(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.NewBellSchedule{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing bell schedule : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.bellScheduleService.UpdateBellSchedule(id, req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// DeleteBellSchedule godoc
+// @Id DeleteBellSchedule
+// @Summary Delete Bell Schedule
+// @Description Deletes a bell schedule, fails while confirmations still use it
+// @Tags bellschedule
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /bell-schedule/{id} [delete]
+func (h *BellScheduleHandler) DeleteBellSchedule(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.bellScheduleService.DeleteBellSchedule(id); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
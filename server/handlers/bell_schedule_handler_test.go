@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+func TestBellScheduleHandler_AddBellSchedule(t *testing.T) {
+	l := util.NewLogger(true)
+	addSuccessful := BellScheduleServiceStub{
+		addBellSchedule: func(nb *dto.NewBellSchedule) error {
+			return nil
+		},
+	}
+	tests := []struct {
+		name                string
+		reqBody             string
+		bellScheduleService service.BellScheduleServiceInterface
+		expStatus           int
+	}{
+		{
+			name: "addBellScheduleSuccessful",
+			reqBody: `{"name": "Regular day", "default": true, "periods": [
+				{"period": 1, "name": "Period 1", "startTime": "8:30", "endTime": "09:20"},
+				{"period": 0, "name": "Break", "startTime": "09:20", "endTime": "09:40"},
+				{"period": 2, "name": "Period 2", "startTime": "09:40", "endTime": "10:30"}
+			]}`,
+			bellScheduleService: addSuccessful,
+			expStatus:           http.StatusCreated,
+		},
+		{
+			name:      "addBellScheduleFailedWithoutPeriods",
+			reqBody:   `{"name": "Regular day", "periods": []}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name: "addBellScheduleFailedWithInvalidTime",
+			reqBody: `{"name": "Regular day", "periods": [
+				{"period": 1, "name": "Period 1", "startTime": "8.30", "endTime": "09:20"}
+			]}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name: "addBellScheduleFailedWithOverlappingPeriods",
+			reqBody: `{"name": "Regular day", "periods": [
+				{"period": 1, "name": "Period 1", "startTime": "08:30", "endTime": "09:20"},
+				{"period": 2, "name": "Period 2", "startTime": "09:10", "endTime": "10:00"}
+			]}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name: "addBellScheduleFailedWithPeriodOutOfOrder",
+			reqBody: `{"name": "Regular day", "periods": [
+				{"period": 1, "name": "Period 1", "startTime": "08:30", "endTime": "09:20"},
+				{"period": 3, "name": "Period 3", "startTime": "09:20", "endTime": "10:10"}
+			]}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name: "addBellScheduleFailedWithOnlyBreaks",
+			reqBody: `{"name": "Regular day", "periods": [
+				{"period": 0, "name": "Lunch", "startTime": "12:00", "endTime": "13:00"}
+			]}`,
+			expStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBellScheduleHandler(util.NewHandlerUtil(l), tt.bellScheduleService)
+
+			req := httptest.NewRequest(http.MethodPost, "/bell-schedule", strings.NewReader(tt.reqBody))
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodPost).Path("/bell-schedule").HandlerFunc(h.AddBellSchedule)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+func TestBellScheduleHandler_DeleteBellSchedule(t *testing.T) {
+	l := util.NewLogger(true)
+	tests := []struct {
+		name                string
+		bellScheduleService service.BellScheduleServiceInterface
+		expStatus           int
+	}{
+		{
+			name: "deleteBellScheduleSuccessful",
+			bellScheduleService: BellScheduleServiceStub{
+				deleteBellSchedule: func(id string) error {
+					return nil
+				},
+			},
+			expStatus: http.StatusOK,
+		},
+		{
+			name: "deleteBellScheduleFailedWhileUsed",
+			bellScheduleService: BellScheduleServiceStub{
+				deleteBellSchedule: func(id string) error {
+					return c.ErrConflict
+				},
+			},
+			expStatus: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBellScheduleHandler(util.NewHandlerUtil(l), tt.bellScheduleService)
+
+			req := httptest.NewRequest(http.MethodDelete, "/bell-schedule/b1", nil)
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodDelete).Path("/bell-schedule/{id}").HandlerFunc(h.DeleteBellSchedule)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+// BellScheduleServiceStub is a stub struct that proxies method calls to function fields.
+type BellScheduleServiceStub struct {
+	addBellSchedule func(nb *dto.NewBellSchedule) error
+
+	getAllBellSchedule func(page *dto.PageRequest) (*dto.Page, error)
+
+	getBellSchedule func(id string) (*dto.BellSchedule, error)
+
+	updateBellSchedule func(id string, nb *dto.NewBellSchedule) error
+
+	deleteBellSchedule func(id string) error
+}
+
+func (s BellScheduleServiceStub) AddBellSchedule(nb *dto.NewBellSchedule) error {
+	return s.addBellSchedule(nb)
+}
+
+func (s BellScheduleServiceStub) GetAllBellSchedule(page *dto.PageRequest) (*dto.Page, error) {
+	return s.getAllBellSchedule(page)
+}
+
+func (s BellScheduleServiceStub) GetBellSchedule(id string) (*dto.BellSchedule, error) {
+	return s.getBellSchedule(id)
+}
+
+func (s BellScheduleServiceStub) UpdateBellSchedule(id string, nb *dto.NewBellSchedule) error {
+	return s.updateBellSchedule(id, nb)
+}
+
+func (s BellScheduleServiceStub) DeleteBellSchedule(id string) error {
+	return s.deleteBellSchedule(id)
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+// CalendarHandler is a handler for /term and /holiday paths
+type CalendarHandler struct {
+	util            *util.HandlerUtil
+	calendarService service.CalendarServiceInterface
+}
+
+func NewCalendarHandler(util *util.HandlerUtil, calendarService service.CalendarServiceInterface) *CalendarHandler {
+	return &CalendarHandler{util: util, calendarService: calendarService}
+}
+
+// GetAllTerm godoc
+// @Id GetAllTerm
+// @Summary Get All Term
+// @Description Returns a page of terms ordered by start date
+// @Tags calendar
+// @Produce json
+// @Param pageSize query int false "number of items per page, defaults to 30 and is at most 100"
+// @Param pageToken query string false "nextPageToken of the previous page"
+// @Success 200 {object} dto.Page{items=[]dto.Term} "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /term [get]
+func (h *CalendarHandler) GetAllTerm(rw http.ResponseWriter, r *http.Request) {
+
+	page, err := pageParam(r)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	res, err := h.calendarService.GetAllTerm(page)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// AddTerm godoc
+// @Id AddTerm
+// @Summary Add Term
+// @Description Creates an academic term
+// @Tags calendar
+// @Produce json
+// @Accept json
+// @Param requestBody body dto.NewTerm true "NewTerm entity"
+// @Success 201 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /term [post]
+func (h *CalendarHandler) AddTerm(rw http.ResponseWriter, r *http.Request) {
+
+	req := &dto.NewTerm{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing term : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.calendarService.AddTerm(req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusCreated)
+}
+
+// GetTerm godoc
+// @Id GetTerm
+// @Summary Get Term
+// @Description Returns a single term
+// @Tags calendar
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} dto.Term "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /term/{id} [get]
+func (h *CalendarHandler) GetTerm(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.calendarService.GetTerm(id)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// UpdateTerm godoc
+// @Id UpdateTerm
+// @Summary Replace Term
+// @Description Replaces every field of an existing term
+// @Tags calendar
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.NewTerm true "NewTerm entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /term/{id} [put]
+func (h *CalendarHandler) UpdateTerm(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.NewTerm{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing term : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.calendarService.UpdateTerm(id, req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// DeleteTerm godoc
+// @Id DeleteTerm
+// @Summary Delete Term
+// @Description Deletes a term, fails while confirmations still use it
+// @Tags calendar
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /term/{id} [delete]
+func (h *CalendarHandler) DeleteTerm(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.calendarService.DeleteTerm(id); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// GetAllHoliday godoc
+// @Id GetAllHoliday
+// @Summary Get All Holiday
+// @Description Returns a page of holidays ordered by start date
+// @Tags calendar
+// @Produce json
+// @Param pageSize query int false "number of items per page, defaults to 30 and is at most 100"
+// @Param pageToken query string false "nextPageToken of the previous page"
+// @Success 200 {object} dto.Page{items=[]dto.Holiday} "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /holiday [get]
+func (h *CalendarHandler) GetAllHoliday(rw http.ResponseWriter, r *http.Request) {
+
+	page, err := pageParam(r)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	res, err := h.calendarService.GetAllHoliday(page)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// AddHoliday godoc
+// @Id AddHoliday
+// @Summary Add Holiday
+// @Description Creates a holiday or school closure, no sessions take place on its dates
+// @Tags calendar
+// @Produce json
+// @Accept json
+// @Param requestBody body dto.NewHoliday true "NewHoliday entity"
+// @Success 201 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /holiday [post]
+func (h *CalendarHandler) AddHoliday(rw http.ResponseWriter, r *http.Request) {
+
+	req := &dto.NewHoliday{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing holiday : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.calendarService.AddHoliday(req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusCreated)
+}
+
+// GetHoliday godoc
+// @Id GetHoliday
+// @Summary Get Holiday
+// @Description Returns a single holiday
+// @Tags calendar
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} dto.Holiday "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /holiday/{id} [get]
+func (h *CalendarHandler) GetHoliday(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.calendarService.GetHoliday(id)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// UpdateHoliday godoc
+// @Id UpdateHoliday
+// @Summary Replace Holiday
+// @Description Replaces every field of an existing holiday
+// @Tags calendar
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.NewHoliday true "NewHoliday entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /holiday/{id} [put]
+func (h *CalendarHandler) UpdateHoliday(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.NewHoliday{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing holiday : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.calendarService.UpdateHoliday(id, req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// DeleteHoliday godoc
+// @Id DeleteHoliday
+// @Summary Delete Holiday
+// @Description Deletes a holiday, lessons take place on its dates again
+// @Tags calendar
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /holiday/{id} [delete]
+func (h *CalendarHandler) DeleteHoliday(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.calendarService.DeleteHoliday(id); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+func TestCalendarHandler_AddHoliday(t *testing.T) {
+	l := util.NewLogger(true)
+	addSuccessful := CalendarServiceStub{
+		addHoliday: func(nh *dto.NewHoliday) error {
+			return nil
+		},
+	}
+	tests := []struct {
+		name            string
+		reqBody         string
+		calendarService service.CalendarServiceInterface
+		expStatus       int
+	}{
+		{
+			name:            "addHolidaySuccessful",
+			reqBody:         `{"name": "Songkran", "startDate": "2022-04-13T00:00:00+07:00", "endDate": "2022-04-15T00:00:00+07:00"}`,
+			calendarService: addSuccessful,
+			expStatus:       http.StatusCreated,
+		},
+		{
+			name:            "addHolidaySuccessfulForSingleDay",
+			reqBody:         `{"name": "Visakha Bucha", "startDate": "2022-05-16T00:00:00+07:00", "endDate": "2022-05-16T00:00:00+07:00"}`,
+			calendarService: addSuccessful,
+			expStatus:       http.StatusCreated,
+		},
+		{
+			name:      "addHolidayFailedEndingBeforeStart",
+			reqBody:   `{"name": "Songkran", "startDate": "2022-04-15T00:00:00+07:00", "endDate": "2022-04-13T00:00:00+07:00"}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "addHolidayFailedWithoutDates",
+			reqBody:   `{"name": "Songkran"}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "addHolidayFailedWithDateOnly",
+			reqBody:   `{"name": "Songkran", "startDate": "2022-04-13", "endDate": "2022-04-15"}`,
+			expStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCalendarHandler(util.NewHandlerUtil(l), tt.calendarService)
+
+			req := httptest.NewRequest(http.MethodPost, "/holiday", strings.NewReader(tt.reqBody))
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodPost).Path("/holiday").HandlerFunc(h.AddHoliday)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+func TestCalendarHandler_DeleteTerm(t *testing.T) {
+	l := util.NewLogger(true)
+	tests := []struct {
+		name            string
+		calendarService service.CalendarServiceInterface
+		expStatus       int
+	}{
+		{
+			name: "deleteTermSuccessful",
+			calendarService: CalendarServiceStub{
+				deleteTerm: func(id string) error {
+					return nil
+				},
+			},
+			expStatus: http.StatusOK,
+		},
+		{
+			name: "deleteTermFailedWhileUsed",
+			calendarService: CalendarServiceStub{
+				deleteTerm: func(id string) error {
+					return c.ErrConflict
+				},
+			},
+			expStatus: http.StatusConflict,
+		},
+		{
+			name: "deleteTermFailedNotFound",
+			calendarService: CalendarServiceStub{
+				deleteTerm: func(id string) error {
+					return c.ErrDBNoSuchEntity
+				},
+			},
+			expStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCalendarHandler(util.NewHandlerUtil(l), tt.calendarService)
+
+			req := httptest.NewRequest(http.MethodDelete, "/term/t1", nil)
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodDelete).Path("/term/{id}").HandlerFunc(h.DeleteTerm)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+// CalendarServiceStub is a stub struct that proxies method calls to function fields.
+type CalendarServiceStub struct {
+	addTerm func(nt *dto.NewTerm) error
+
+	getAllTerm func(page *dto.PageRequest) (*dto.Page, error)
+
+	getTerm func(id string) (*dto.Term, error)
+
+	updateTerm func(id string, nt *dto.NewTerm) error
+
+	deleteTerm func(id string) error
+
+	addHoliday func(nh *dto.NewHoliday) error
+
+	getAllHoliday func(page *dto.PageRequest) (*dto.Page, error)
+
+	getHoliday func(id string) (*dto.Holiday, error)
+
+	updateHoliday func(id string, nh *dto.NewHoliday) error
+
+	deleteHoliday func(id string) error
+}
+
+func (s CalendarServiceStub) AddTerm(nt *dto.NewTerm) error {
+	return s.addTerm(nt)
+}
+
+func (s CalendarServiceStub) GetAllTerm(page *dto.PageRequest) (*dto.Page, error) {
+	return s.getAllTerm(page)
+}
+
+func (s CalendarServiceStub) GetTerm(id string) (*dto.Term, error) {
+	return s.getTerm(id)
+}
+
+func (s CalendarServiceStub) UpdateTerm(id string, nt *dto.NewTerm) error {
+	return s.updateTerm(id, nt)
+}
+
+func (s CalendarServiceStub) DeleteTerm(id string) error {
+	return s.deleteTerm(id)
+}
+
+func (s CalendarServiceStub) AddHoliday(nh *dto.NewHoliday) error {
+	return s.addHoliday(nh)
+}
+
+func (s CalendarServiceStub) GetAllHoliday(page *dto.PageRequest) (*dto.Page, error) {
+	return s.getAllHoliday(page)
+}
+
+func (s CalendarServiceStub) GetHoliday(id string) (*dto.Holiday, error) {
+	return s.getHoliday(id)
+}
+
+func (s CalendarServiceStub) UpdateHoliday(id string, nh *dto.NewHoliday) error {
+	return s.updateHoliday(id, nh)
+}
+
+func (s CalendarServiceStub) DeleteHoliday(id string) error {
+	return s.deleteHoliday(id)
+}
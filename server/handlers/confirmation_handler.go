@@ -8,6 +8,7 @@ import (
 
 	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/security"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
 )
@@ -25,7 +26,13 @@ func NewConfirmationHandler(util *util.HandlerUtil, subjectService service.Confi
 
 func (m *ConfirmationHandler) GetAllConfirmation(rw http.ResponseWriter, r *http.Request) {
 
-	response, err := m.confirmationService.GetAllConfirmation()
+	page, err := pageParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	response, err := m.confirmationService.GetAllConfirmation(page)
 	if err != nil {
 		m.util.WrappedError(rw, err)
 		return
@@ -43,6 +50,8 @@ func (m *ConfirmationHandler) GetAllConfirmation(rw http.ResponseWriter, r *http
 	rw.Write(resp)
 }
 
+// GetAllConfirmationDetail returns a page of the details of the confirmation, or all of them as a spreadsheet
+// download when the format query parameter is csv or xlsx
 func (m *ConfirmationHandler) GetAllConfirmationDetail(rw http.ResponseWriter, r *http.Request) {
 
 	id, ok := mux.Vars(r)["id"]
@@ -51,7 +60,56 @@ func (m *ConfirmationHandler) GetAllConfirmationDetail(rw http.ResponseWriter, r
 		return
 	}
 
-	response, err := m.confirmationService.GetAllConfirmationDetail(id)
+	format, err := exportFormatParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+	if isExportFormat(format) {
+		export, err := m.confirmationService.ExportConfirmationDetails(id)
+		if err != nil {
+			m.util.WrappedError(rw, err)
+			return
+		}
+		writeExport(m.util, rw, format, export)
+		return
+	}
+
+	page, err := pageParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	response, err := m.confirmationService.GetAllConfirmationDetail(id, page)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(response)
+	if err != nil {
+		m.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.Header().Set("Access-Control-Allow-Origin", "*")
+	rw.Header().Set("Access-Control-Allow-Headers","Content-Type,access-control-allow-origin, access-control-allow-headers")
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+
+// GetConfirmationConflicts reports the problems in the details of a confirmation which have to be fixed before scheduling
+func (m *ConfirmationHandler) GetConfirmationConflicts(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, fmt.Errorf("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	response, err := m.confirmationService.GetConfirmationConflicts(id)
 	if err != nil {
 		m.util.WrappedError(rw, err)
 		return
@@ -130,3 +188,104 @@ func (m *ConfirmationHandler) AddConfirmationDetail(rw http.ResponseWriter, r *h
 	rw.WriteHeader(http.StatusOK)
 	rw.Write(resp)
 }
+
+
+// ImportConfirmationDetails godoc
+// @Id ImportConfirmationDetails
+// @Summary Import Confirmation Details
+// @Description Adds the confirmation details of a csv or xlsx spreadsheet with the columns studentId, studentName, level, subjectDetailId, day and period.
+// @Description Each row is checked like a detail added on its own, the rows which pass are written and the others are reported.
+// @Tags confirmation
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "id"
+// @Param file formData file true "spreadsheet, .csv or .xlsx"
+// @Param dryRun query bool false "only check the rows, nothing is written"
+// @Success 200 {object} dto.ImportResult "import result"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /confirmation/{id}/import [post]
+func (m *ConfirmationHandler) ImportConfirmationDetails(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, fmt.Errorf("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	sheet, dryRun, err := importParam(rw, r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	rows, err := dto.ToConfirmationDetailRows(id, sheet)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	response, err := m.confirmationService.ImportConfirmationDetails(id, rows, dryRun)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(response)
+	if err != nil {
+		m.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.Header().Set("Access-Control-Allow-Origin", "*")
+	rw.Header().Set("Access-Control-Allow-Headers","Content-Type,access-control-allow-origin, access-control-allow-headers")
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// UpdateConfirmationStatus godoc
+// @Id UpdateConfirmationStatus
+// @Summary Update Confirmation Status
+// @Description Moves the confirmation through its lifecycle: draft, submitted, locked and archived. A draft can be submitted, a submitted confirmation locked or sent back to draft,
+// @Description a locked one submitted again to be corrected, and any of them archived. Details can only be added while the confirmation is draft or submitted.
+// @Tags confirmation
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param requestBody body dto.UpdateConfirmationStatusRequest true "UpdateConfirmationStatusRequest entity"
+// @Success 200 {object} dto.Confirmation "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /confirmation/{id}/status [patch]
+func (m *ConfirmationHandler) UpdateConfirmationStatus(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, fmt.Errorf("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.UpdateConfirmationStatusRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		m.util.HTTPError(rw, fmt.Errorf("error deserializing confirmation status %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		m.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	response, err := m.confirmationService.UpdateConfirmationStatus(id, req, security.RequestUser(r))
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(response)
+	if err != nil {
+		m.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
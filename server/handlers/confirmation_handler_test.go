@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+func TestConfirmationHandler_ImportConfirmationDetails(t *testing.T) {
+	l := util.NewLogger(true)
+	var gotRows []*dto.ConfirmationDetailRow
+	var gotDryRun bool
+	importSuccessful := ConfirmationServiceStub{
+		importConfirmationDetails: func(id string, rows []*dto.ConfirmationDetailRow, dryRun bool) (*dto.ImportResult, error) {
+			gotRows, gotDryRun = rows, dryRun
+			res := dto.NewImportResult(len(rows), dryRun)
+			res.Imported = len(rows)
+			return res, nil
+		},
+	}
+	csv := "Student Name,Level,Subject Detail Id,Day,Period\n" +
+		"alice,P.4,\"piano; violin\",Mon,P1\n" +
+		"bob,P.5,guitar,tuesday,two\n"
+
+	tests := []struct {
+		name                string
+		query               string
+		field               string
+		filename            string
+		content             string
+		confirmationService service.ConfirmationServiceInterface
+		expStatus           int
+		expDryRun           bool
+	}{
+		{
+			name:                "importSuccessful",
+			field:               "file",
+			filename:            "details.csv",
+			content:             csv,
+			confirmationService: importSuccessful,
+			expStatus:           http.StatusOK,
+		},
+		{
+			name:                "importDryRun",
+			query:               "?dryRun=true",
+			field:               "file",
+			filename:            "details.csv",
+			content:             csv,
+			confirmationService: importSuccessful,
+			expStatus:           http.StatusOK,
+			expDryRun:           true,
+		},
+		{
+			name:      "importFailedWithoutFile",
+			field:     "upload",
+			filename:  "details.csv",
+			content:   csv,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "importFailedWithUnknownFormat",
+			field:     "file",
+			filename:  "details.ods",
+			content:   csv,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "importFailedWithUnknownColumn",
+			field:     "file",
+			filename:  "details.csv",
+			content:   "studentName,teacher,subjectDetailId,day,period\nalice,t1,piano,monday,1\n",
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "importFailedWithoutStudentColumn",
+			field:     "file",
+			filename:  "details.csv",
+			content:   "level,subjectDetailId,day,period\nP.4,piano,monday,1\n",
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "importFailedWithUnknownQueryParam",
+			query:     "?dryrun=true",
+			field:     "file",
+			filename:  "details.csv",
+			content:   csv,
+			expStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRows = nil
+			h := NewConfirmationHandler(util.NewHandlerUtil(l), tt.confirmationService)
+
+			req := newUploadRequest(t, "/confirmation/c1/import"+tt.query, tt.field, tt.filename, tt.content)
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodPost).Path("/confirmation/{id}/import").HandlerFunc(h.ImportConfirmationDetails)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Fatalf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			res := &dto.ImportResult{}
+			if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+				t.Fatalf("unable to decode response: %v", err)
+			}
+			if res.Rows != 2 || gotDryRun != tt.expDryRun {
+				t.Errorf("unexpected import: got %v rows dry run %v", res.Rows, gotDryRun)
+			}
+
+			first := gotRows[0]
+			if first.Line != 2 || first.Err != nil || first.Detail.ConfirmationID != "c1" || first.Detail.Day != "monday" ||
+				first.Detail.Period != 1 || len(first.Detail.SubjectDetailID) != 2 || first.Detail.SubjectDetailID[1] != "violin" {
+				t.Errorf("unexpected first row: %+v %+v", first, first.Detail)
+			}
+			if gotRows[1].Err == nil {
+				t.Errorf("expecting the unreadable period of the second row to be reported")
+			}
+		})
+	}
+}
+
+func TestConfirmationHandler_UpdateConfirmationStatus(t *testing.T) {
+	l := util.NewLogger(true)
+	var gotBy string
+	updateSuccessful := ConfirmationServiceStub{
+		updateConfirmationStatus: func(id string, request *dto.UpdateConfirmationStatusRequest, changedBy string) (*dto.Confirmation, error) {
+			gotBy = changedBy
+			return &dto.Confirmation{ID: id, Status: request.Status, StatusChangedBy: changedBy}, nil
+		},
+	}
+	userInfo := base64.URLEncoding.EncodeToString([]byte(`{"id":"u1","email":"a@school.ac.th"}`))
+
+	tests := []struct {
+		name                string
+		reqBody             string
+		userInfo            string
+		confirmationService service.ConfirmationServiceInterface
+		expStatus           int
+		expBy               string
+	}{
+		{
+			name:                "updateStatusSuccessful",
+			reqBody:             `{"status": "submitted"}`,
+			userInfo:            userInfo,
+			confirmationService: updateSuccessful,
+			expStatus:           http.StatusOK,
+			expBy:               "a@school.ac.th",
+		},
+		{
+			name:                "updateStatusSuccessfulWithoutUser",
+			reqBody:             `{"status": "submitted"}`,
+			confirmationService: updateSuccessful,
+			expStatus:           http.StatusOK,
+		},
+		{
+			name:      "updateStatusFailedWithUnknownStatus",
+			reqBody:   `{"status": "cancelled"}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "updateStatusFailedWithTransitionNotAllowed",
+			reqBody: `{"status": "locked"}`,
+			confirmationService: ConfirmationServiceStub{
+				updateConfirmationStatus: func(id string, request *dto.UpdateConfirmationStatusRequest, changedBy string) (*dto.Confirmation, error) {
+					return nil, fmt.Errorf("%w: confirmation c1 cannot become locked while it is draft", c.ErrConflict)
+				},
+			},
+			expStatus: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBy = ""
+			h := NewConfirmationHandler(util.NewHandlerUtil(l), tt.confirmationService)
+
+			req := httptest.NewRequest(http.MethodPatch, "/confirmation/c1/status", strings.NewReader(tt.reqBody))
+			if len(tt.userInfo) > 0 {
+				req.Header.Set("X-Endpoint-API-UserInfo", tt.userInfo)
+			}
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodPatch).Path("/confirmation/{id}/status").HandlerFunc(h.UpdateConfirmationStatus)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Fatalf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			confirmation := &dto.Confirmation{}
+			if err := json.NewDecoder(resp.Body).Decode(confirmation); err != nil {
+				t.Fatalf("unable to decode response: %v", err)
+			}
+			if confirmation.Status != "submitted" || gotBy != tt.expBy {
+				t.Errorf("unexpected status change: %v by %q", confirmation.Status, gotBy)
+			}
+		})
+	}
+}
+
+// ConfirmationServiceStub is a stub struct that proxies method calls to function fields, the methods of
+// the embedded interface are not expected to be called.
+type ConfirmationServiceStub struct {
+	service.ConfirmationServiceInterface
+
+	importConfirmationDetails func(id string, rows []*dto.ConfirmationDetailRow, dryRun bool) (*dto.ImportResult, error)
+
+	exportConfirmationDetails func(id string) (*dto.Export, error)
+
+	updateConfirmationStatus func(id string, request *dto.UpdateConfirmationStatusRequest, changedBy string) (*dto.Confirmation, error)
+}
+
+func (stub ConfirmationServiceStub) ImportConfirmationDetails(id string, rows []*dto.ConfirmationDetailRow, dryRun bool) (*dto.ImportResult, error) {
+	return stub.importConfirmationDetails(id, rows, dryRun)
+}
+
+func (stub ConfirmationServiceStub) ExportConfirmationDetails(id string) (*dto.Export, error) {
+	return stub.exportConfirmationDetails(id)
+}
+
+func (stub ConfirmationServiceStub) UpdateConfirmationStatus(id string, request *dto.UpdateConfirmationStatusRequest, changedBy string) (*dto.Confirmation, error) {
+	return stub.updateConfirmationStatus(id, request, changedBy)
+}
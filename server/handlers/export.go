@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+const (
+	exportCSV  = "csv"
+	exportXLSX = "xlsx"
+)
+
+// exportFormatParam reads the optional format query parameter of read endpoints which can be downloaded
+// as a spreadsheet. It is empty when the json response is wanted, other formats the endpoint supports
+// itself may be allowed.
+func exportFormatParam(r *http.Request, allowed ...string) (string, error) {
+	format := r.URL.Query().Get("format")
+	if len(format) == 0 || format == exportCSV || format == exportXLSX {
+		return format, nil
+	}
+	for _, a := range allowed {
+		if format == a {
+			return format, nil
+		}
+	}
+	return "", &c.ErrValidation{Violations: fmt.Errorf("invalid format value %q, allowed are %v", format, append([]string{exportCSV, exportXLSX}, allowed...))}
+}
+
+// isExportFormat tells whether the format read by exportFormatParam asks for a spreadsheet
+func isExportFormat(format string) bool {
+	return format == exportCSV || format == exportXLSX
+}
+
+// writeExport writes the export as a csv or xlsx attachment. The rows of exports which are not paged are
+// read first, so a failing lookup is answered with an error instead of a truncated download. Paged exports
+// are streamed, the response is committed once the first row is written and errors reading later rows
+// can only be logged.
+func writeExport(hu *util.HandlerUtil, rw http.ResponseWriter, format string, export *dto.Export) {
+	rows := export.Rows
+	if !export.Paged {
+		buffered := make([][]string, 0)
+		err := export.Rows(func(row []string) error {
+			buffered = append(buffered, row)
+			return nil
+		})
+		if err != nil {
+			hu.WrappedError(rw, err)
+			return
+		}
+		rows = func(write func(row []string) error) error {
+			for _, row := range buffered {
+				if err := write(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	contentType := "text/csv;charset=utf-8"
+	if format == exportXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	rw.Header().Set("Content-Type", contentType)
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Name+"."+format))
+	rw.WriteHeader(http.StatusOK)
+
+	err := func() error {
+		var w util.SheetWriter
+		if format == exportXLSX {
+			var err error
+			if w, err = util.NewXLSXWriter(rw, export.Name); err != nil {
+				return err
+			}
+		} else {
+			w = util.NewCSVWriter(rw)
+		}
+
+		if err := w.Write(export.Columns); err != nil {
+			return err
+		}
+		if err := rows(w.Write); err != nil {
+			return err
+		}
+		return w.Close()
+	}()
+	if err != nil {
+		hu.Log.Error().
+			Err(err).
+			Str("export", export.Name).
+			Msg("export interrupted")
+	}
+}
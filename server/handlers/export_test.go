@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+func TestConfirmationHandler_ExportConfirmationDetails(t *testing.T) {
+	l := util.NewLogger(true)
+	exportSuccessful := ConfirmationServiceStub{
+		exportConfirmationDetails: func(id string) (*dto.Export, error) {
+			return &dto.Export{
+				Name:    "confirmation-" + id,
+				Columns: dto.ExportColumns,
+				Rows: func(write func(row []string) error) error {
+					if err := write([]string{"alice", "Piano", "Music", "", "monday", "1"}); err != nil {
+						return err
+					}
+					return write([]string{"bob, jr", "Violin", "Music", "", "tuesday", "2"})
+				},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		confirmationService service.ConfirmationServiceInterface
+		expStatus           int
+		expContentType      string
+		expFilename         string
+	}{
+		{
+			name:                "exportCSVSuccessful",
+			query:               "?format=csv",
+			confirmationService: exportSuccessful,
+			expStatus:           http.StatusOK,
+			expContentType:      "text/csv;charset=utf-8",
+			expFilename:         `attachment; filename="confirmation-c1.csv"`,
+		},
+		{
+			name:                "exportXLSXSuccessful",
+			query:               "?format=xlsx",
+			confirmationService: exportSuccessful,
+			expStatus:           http.StatusOK,
+			expContentType:      "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			expFilename:         `attachment; filename="confirmation-c1.xlsx"`,
+		},
+		{
+			name:      "exportFailedWithUnknownFormat",
+			query:     "?format=pdf",
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "exportFailedWithDBNoSuchEntity",
+			query: "?format=csv",
+			confirmationService: ConfirmationServiceStub{
+				exportConfirmationDetails: func(id string) (*dto.Export, error) {
+					return nil, c.ErrDBNoSuchEntity
+				},
+			},
+			expStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewConfirmationHandler(util.NewHandlerUtil(l), tt.confirmationService)
+
+			req := httptest.NewRequest(http.MethodGet, "/confirmation/c1"+tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodGet).Path("/confirmation/{id}").HandlerFunc(h.GetAllConfirmationDetail)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Fatalf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+			if got := resp.Header.Get("Content-Type"); got != tt.expContentType {
+				t.Errorf("unexpected content type: got %v want %v", got, tt.expContentType)
+			}
+			if got := resp.Header.Get("Content-Disposition"); got != tt.expFilename {
+				t.Errorf("unexpected content disposition: got %v want %v", got, tt.expFilename)
+			}
+
+			var sheet *util.Sheet
+			var err error
+			if tt.expContentType == "text/csv;charset=utf-8" {
+				sheet, err = util.ReadCSV(rr.Body)
+			} else {
+				sheet, err = util.ReadXLSX(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+			}
+			if err != nil {
+				t.Fatalf("unable to read export: %v", err)
+			}
+			if len(sheet.Columns) != 6 || sheet.Columns[2] != "mainsubject" || len(sheet.Rows) != 2 {
+				t.Fatalf("unexpected export: %v with %d rows", sheet.Columns, len(sheet.Rows))
+			}
+			if row := sheet.Rows[1]; row.Get("student") != "bob, jr" || row.Get("period") != "2" {
+				t.Errorf("unexpected second row: %v", row.Values)
+			}
+		})
+	}
+}
+
+func TestScheduleHandler_ExportScheduleSessions(t *testing.T) {
+	l := util.NewLogger(true)
+	var gotTeacherID string
+	h := NewScheduleHandler(util.NewHandlerUtil(l), ScheduleServiceStub{
+		exportScheduleSessions: func(id string, teacherID string, studentID string) (*dto.Export, error) {
+			gotTeacherID = teacherID
+			return &dto.Export{
+				Name:    "sessions-" + id,
+				Columns: dto.SessionExportColumns,
+				Rows: func(write func(row []string) error) error {
+					return write([]string{"2022-05-16", "alice", "Piano", "Music", "Jane Doe", "monday", "1"})
+				},
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/schedule/s1/sessions?teacherId=t1&format=csv", nil)
+	rr := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.Methods(http.MethodGet).Path("/schedule/{id}/sessions").HandlerFunc(h.GetScheduleSessions)
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	if gotTeacherID != "t1" {
+		t.Errorf("unexpected teacher filter: %q", gotTeacherID)
+	}
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("unable to read export: %v", err)
+	}
+	if len(records) != 2 || records[0][0] != "Date" || records[1][4] != "Jane Doe" {
+		t.Errorf("unexpected export: %v", records)
+	}
+}
+
+func TestScheduleHandler_ExportScheduleFailedLookup(t *testing.T) {
+	l := util.NewLogger(true)
+	h := NewScheduleHandler(util.NewHandlerUtil(l), ScheduleServiceStub{
+		exportSchedule: func(id string, teacherID string, studentID string) (*dto.Export, error) {
+			return &dto.Export{
+				Name:    "schedule-" + id,
+				Columns: dto.ExportColumns,
+				Rows: func(write func(row []string) error) error {
+					if err := write([]string{"alice", "Piano", "Music", "Jane Doe", "monday", "1"}); err != nil {
+						return err
+					}
+					return errors.New("teacher lookup failed")
+				},
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/schedule/s1?format=csv", nil)
+	rr := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.Methods(http.MethodGet).Path("/schedule/{id}").HandlerFunc(h.GetSchedule)
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("unexpected status code: got %v want %v", rr.Code, http.StatusInternalServerError)
+	}
+	if got := rr.Header().Get("Content-Disposition"); len(got) > 0 {
+		t.Errorf("unexpected attachment of a failed export: %v", got)
+	}
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+// icsProductID identifies this service as the producer of the calendars, see RFC 5545 section 3.7.3
+const icsProductID = "-//schedule-school-teaching//teacher calendar//EN"
+
+const (
+	icsDateFormat     = "20060102"
+	icsLocalFormat    = "20060102T150405"
+	icsUTCFormat      = "20060102T150405Z"
+	icsMaxLineOctets  = 75
+	icsTimezoneMargin = 24 * time.Hour
+)
+
+// icsWriter writes the content lines of an iCalendar, folding them at 75 octets
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (iw *icsWriter) line(format string, args ...interface{}) {
+	if iw.err != nil {
+		return
+	}
+	l := fmt.Sprintf(format, args...)
+	var b strings.Builder
+	n := 0
+	for _, r := range l {
+		size := utf8.RuneLen(r)
+		// continuation lines start with a space which counts towards their length
+		if n+size > icsMaxLineOctets {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	_, iw.err = io.WriteString(iw.w, b.String())
+}
+
+// writeTeacherCalendar writes the calendar as an RFC 5545 iCalendar object with a weekly recurring VEVENT per class
+func writeTeacherCalendar(w io.Writer, cal *dto.TeacherCalendar) error {
+	iw := &icsWriter{w: w}
+	iw.line("BEGIN:VCALENDAR")
+	iw.line("VERSION:2.0")
+	iw.line("PRODID:%v", icsProductID)
+	iw.line("CALSCALE:GREGORIAN")
+	iw.line("METHOD:PUBLISH")
+	iw.line("X-WR-CALNAME:%v", icsText(cal.TeacherName))
+
+	tzid := cal.Location.String()
+	if from, to, ok := timedEventRange(cal.Events); ok {
+		writeTimezone(iw, cal.Location, from.Add(-icsTimezoneMargin), to.Add(icsTimezoneMargin))
+	}
+
+	for _, e := range cal.Events {
+		iw.line("BEGIN:VEVENT")
+		iw.line("UID:%v", icsText(e.UID))
+		iw.line("DTSTAMP:%v", cal.Generated.UTC().Format(icsUTCFormat))
+		if e.AllDay {
+			iw.line("DTSTART;VALUE=DATE:%v", e.Start.Format(icsDateFormat))
+			iw.line("RRULE:FREQ=WEEKLY;UNTIL=%v", e.Until.Format(icsDateFormat))
+			if len(e.ExDates) > 0 {
+				iw.line("EXDATE;VALUE=DATE:%v", icsTimes(e.ExDates, icsDateFormat, nil))
+			}
+		} else {
+			iw.line("DTSTART;TZID=%v:%v", tzid, e.Start.In(cal.Location).Format(icsLocalFormat))
+			iw.line("DTEND;TZID=%v:%v", tzid, e.End.In(cal.Location).Format(icsLocalFormat))
+			// UNTIL has to be in UTC when DTSTART has a time zone
+			iw.line("RRULE:FREQ=WEEKLY;UNTIL=%v", e.Until.UTC().Format(icsUTCFormat))
+			if len(e.ExDates) > 0 {
+				iw.line("EXDATE;TZID=%v:%v", tzid, icsTimes(e.ExDates, icsLocalFormat, cal.Location))
+			}
+		}
+		iw.line("SUMMARY:%v", icsText(e.Summary))
+		iw.line("DESCRIPTION:%v", icsText(e.Description))
+		if len(e.Location) > 0 {
+			iw.line("LOCATION:%v", icsText(e.Location))
+		}
+		iw.line("END:VEVENT")
+	}
+
+	iw.line("END:VCALENDAR")
+	return iw.err
+}
+
+// timedEventRange is the span from the first to the last class of the events having a time
+func timedEventRange(events []*dto.CalendarEvent) (time.Time, time.Time, bool) {
+	var from, to time.Time
+	found := false
+	for _, e := range events {
+		if e.AllDay {
+			continue
+		}
+		if !found || e.Start.Before(from) {
+			from = e.Start
+		}
+		if !found || e.Until.After(to) {
+			to = e.Until
+		}
+		found = true
+	}
+	return from, to, found
+}
+
+// writeTimezone writes the VTIMEZONE of the location, holding the offset in effect at from and every
+// change of offset until to. RFC 5545 requires one for each TZID the events refer to.
+func writeTimezone(iw *icsWriter, loc *time.Location, from time.Time, to time.Time) {
+	iw.line("BEGIN:VTIMEZONE")
+	iw.line("TZID:%v", loc.String())
+
+	name, offset := from.In(loc).Zone()
+	writeTimezoneRule(iw, "STANDARD", from.In(loc), offset, offset, name)
+
+	// offsets change at most a few times a year, days without a change are skipped over
+	for day := from; day.Before(to); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+		_, nextOffset := next.In(loc).Zone()
+		if nextOffset == offset {
+			continue
+		}
+		lo, hi := day, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if _, o := mid.In(loc).Zone(); o == offset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		kind := "STANDARD"
+		if nextOffset > offset {
+			kind = "DAYLIGHT"
+		}
+		nextName, _ := hi.In(loc).Zone()
+		// the onset is given in the local time in effect before the change
+		writeTimezoneRule(iw, kind, hi.In(time.FixedZone("", offset)), offset, nextOffset, nextName)
+		offset = nextOffset
+	}
+
+	iw.line("END:VTIMEZONE")
+}
+
+func writeTimezoneRule(iw *icsWriter, kind string, onset time.Time, from int, to int, name string) {
+	iw.line("BEGIN:%v", kind)
+	iw.line("DTSTART:%v", onset.Format(icsLocalFormat))
+	iw.line("TZOFFSETFROM:%v", icsOffset(from))
+	iw.line("TZOFFSETTO:%v", icsOffset(to))
+	if len(name) > 0 {
+		iw.line("TZNAME:%v", icsText(name))
+	}
+	iw.line("END:%v", kind)
+}
+
+// icsOffset formats an offset from UTC in seconds as +HHMM
+func icsOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%v%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// icsTimes formats a list of times in the location, or as they are when loc is nil
+func icsTimes(times []time.Time, layout string, loc *time.Location) string {
+	values := make([]string, 0, len(times))
+	for _, t := range times {
+		if loc != nil {
+			t = t.In(loc)
+		}
+		values = append(values, t.Format(layout))
+	}
+	return strings.Join(values, ",")
+}
+
+// icsText escapes a TEXT value, see RFC 5545 section 3.3.11
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icsText(s string) string {
+	return icsTextEscaper.Replace(s)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+func TestWriteTeacherCalendar(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	cal := &dto.TeacherCalendar{
+		TeacherID:   "t1",
+		TeacherName: "Alice Smith",
+		ScheduleID:  "sc1",
+		Generated:   time.Date(2022, 2, 1, 3, 4, 5, 0, time.UTC),
+		Location:    newYork,
+		Events: []*dto.CalendarEvent{
+			{
+				UID:         "s1@sc1",
+				Summary:     "Piano",
+				Description: "Subject: Piano\nStudents: alice, bob; " + strings.Repeat("carol, ", 10),
+				Location:    "Music room",
+				Start:       time.Date(2022, 3, 7, 8, 30, 0, 0, newYork),
+				End:         time.Date(2022, 3, 7, 9, 20, 0, 0, newYork),
+				Until:       time.Date(2022, 3, 28, 8, 30, 0, 0, newYork),
+				ExDates:     []time.Time{time.Date(2022, 3, 14, 8, 30, 0, 0, newYork)},
+			},
+			{
+				UID:     "s2@sc1",
+				Summary: "Math",
+				AllDay:  true,
+				Start:   time.Date(2022, 3, 11, 0, 0, 0, 0, newYork),
+				Until:   time.Date(2022, 3, 25, 0, 0, 0, 0, newYork),
+				ExDates: []time.Time{time.Date(2022, 3, 18, 0, 0, 0, 0, newYork)},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := writeTeacherCalendar(&b, cal); err != nil {
+		t.Fatalf("writeTeacherCalendar() error = %v", err)
+	}
+	ics := b.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Alice Smith\r\n",
+		"TZID:America/New_York\r\n",
+		// daylight saving time starts on 2022-03-13 at 2am
+		"BEGIN:DAYLIGHT\r\nDTSTART:20220313T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nTZNAME:EDT\r\nEND:DAYLIGHT\r\n",
+		"DTSTAMP:20220201T030405Z\r\n",
+		"DTSTART;TZID=America/New_York:20220307T083000\r\n",
+		"DTEND;TZID=America/New_York:20220307T092000\r\n",
+		"RRULE:FREQ=WEEKLY;UNTIL=20220328T123000Z\r\n",
+		"EXDATE;TZID=America/New_York:20220314T083000\r\n",
+		"LOCATION:Music room\r\n",
+		"DTSTART;VALUE=DATE:20220311\r\n",
+		"RRULE:FREQ=WEEKLY;UNTIL=20220325\r\n",
+		"EXDATE;VALUE=DATE:20220318\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expecting calendar to contain %q, got\n%v", want, ics)
+		}
+	}
+
+	if !strings.Contains(ics, `DESCRIPTION:Subject: Piano\nStudents: alice\, bob\; carol\,`) {
+		t.Errorf("expecting the description to be escaped, got\n%v", ics)
+	}
+	for _, l := range strings.Split(ics, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("expecting lines to be folded at 75 octets, got %d: %q", len(l), l)
+		}
+	}
+}
+
+func TestICSLineFoldingKeepsRunesWhole(t *testing.T) {
+	var b bytes.Buffer
+	iw := &icsWriter{w: &b}
+	iw.line("SUMMARY:%v", strings.Repeat("ดนตรี", 10))
+
+	for _, l := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("expecting lines of at most 75 octets, got %d", len(l))
+		}
+		if !strings.HasPrefix(l, "SUMMARY:") && !strings.HasPrefix(l, " ") {
+			t.Errorf("expecting continuation lines to start with a space, got %q", l)
+		}
+	}
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	if unfolded != "SUMMARY:"+strings.Repeat("ดนตรี", 10)+"\r\n" {
+		t.Errorf("unexpected unfolded line %q", unfolded)
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+// maxImportSize is the largest spreadsheet accepted by the import endpoints
+const maxImportSize = 10 << 20
+
+// importParam reads the spreadsheet uploaded as the file field of a multipart form and the optional
+// dryRun query parameter of import requests, the import may allow other query parameters
+func importParam(rw http.ResponseWriter, r *http.Request, allowed ...string) (*util.Sheet, bool, error) {
+	if err := checkQueryParams(r, append(allowed, "dryRun")...); err != nil {
+		return nil, false, err
+	}
+
+	dryRun, err := boolParam(r, "dryRun")
+	if err != nil {
+		return nil, false, err
+	}
+
+	r.Body = http.MaxBytesReader(rw, r.Body, maxImportSize)
+	file, header, err := r.FormFile("file")
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, false, &c.ErrValidation{Violations: errors.New("upload the spreadsheet as the file field of a multipart form")}
+	}
+	if err != nil {
+		return nil, false, &c.ErrValidation{Violations: fmt.Errorf("error reading upload %w", err)}
+	}
+	defer file.Close()
+
+	sheet, err := util.ReadSpreadsheet(header.Filename, file, header.Size)
+	if err != nil {
+		return nil, false, &c.ErrValidation{Violations: err}
+	}
+	return sheet, dryRun, nil
+}
+
+// boolParam reads an optional boolean query parameter, it is false when not given
+func boolParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if len(value) == 0 {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &c.ErrValidation{Violations: fmt.Errorf("invalid %v value %q", name, value)}
+	}
+	return b, nil
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newUploadRequest builds a POST request uploading content as the named file field of a multipart form
+func newUploadRequest(t *testing.T, target string, field string, filename string, content string) *http.Request {
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	w, err := mw.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatalf("unable to create form file: %v", err)
+	}
+	w.Write([]byte(content))
+	if err := mw.Close(); err != nil {
+		t.Fatalf("unable to close multipart form: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, target, &b)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
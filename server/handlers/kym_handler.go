@@ -35,7 +35,9 @@ func NewKymHandler(util *util.HandlerUtil, ra security.RoleAuthenticator, kymSer
 // @Produce json
 // @Accept json
 // @Param status query string false "string enums" Enums("approved", "rejected", "pending")
-// @Success 200 {object} []dto.KymResponse "success"
+// @Param pageSize query int false "number of items per page, defaults to 30 and is at most 100"
+// @Param pageToken query string false "nextPageToken of the previous page"
+// @Success 200 {object} dto.Page{items=[]dto.KymResponse} "success"
 // @Failure default {object} util.APIResponse "fail"
 // @Router /kym [get]
 func (h *KymHandler) GetAllKym(rw http.ResponseWriter, r *http.Request) {
@@ -47,7 +49,13 @@ func (h *KymHandler) GetAllKym(rw http.ResponseWriter, r *http.Request) {
 
 	status := r.URL.Query().Get("status")
 
-	kymResponse, err := h.kymService.GetAllKym(status)
+	page, err := pageParam(r)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	kymResponse, err := h.kymService.GetAllKym(status, page)
 	if err != nil {
 		h.util.WrappedError(rw, err)
 		return
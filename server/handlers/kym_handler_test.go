@@ -273,6 +273,7 @@ func TestKymHandler_GetAllKym(t *testing.T) {
 	l := util.NewLogger(true)
 	tests := []struct {
 		name      string
+		query     string
 		fields    fields
 		expStatus int
 	}{
@@ -282,8 +283,8 @@ func TestKymHandler_GetAllKym(t *testing.T) {
 				util: util.NewHandlerUtil(l),
 				ra:   &RoleAuthenticatorStub{},
 				kymService: KymServiceStub{
-					getAllKym: func(status string) ([]*dto.KymResponse, error) {
-						return []*dto.KymResponse{
+					getAllKym: func(status string, page *dto.PageRequest) (*dto.Page, error) {
+						return dto.NewPage([]*dto.KymResponse{
 							{
 								ID:                  "GS-16SZ-oOu5iFpn9SMlj",
 								BusinessName:        "business-name-example",
@@ -293,7 +294,7 @@ func TestKymHandler_GetAllKym(t *testing.T) {
 								DocumentDownloadURL: "test-url",
 								Status:              "approved",
 							},
-						}, nil
+						}, "next"), nil
 					},
 				},
 			},
@@ -305,8 +306,8 @@ func TestKymHandler_GetAllKym(t *testing.T) {
 				util: util.NewHandlerUtil(l),
 				ra:   &RoleAuthenticatorStub{},
 				kymService: KymServiceStub{
-					getAllKym: func(status string) ([]*dto.KymResponse, error) {
-						return []*dto.KymResponse{}, c.ErrDBNoSuchEntity
+					getAllKym: func(status string, page *dto.PageRequest) (*dto.Page, error) {
+						return nil, c.ErrDBNoSuchEntity
 					},
 				},
 			},
@@ -318,13 +319,48 @@ func TestKymHandler_GetAllKym(t *testing.T) {
 				util: util.NewHandlerUtil(l),
 				ra:   &RoleAuthenticatorStub{},
 				kymService: KymServiceStub{
-					getAllKym: func(status string) ([]*dto.KymResponse, error) {
+					getAllKym: func(status string, page *dto.PageRequest) (*dto.Page, error) {
 						return nil, errors.New("some internal db failure")
 					},
 				},
 			},
 			expStatus: http.StatusInternalServerError,
 		},
+		{
+			name:  "getAllKymWithPageParams",
+			query: "?pageSize=5&pageToken=abc",
+			fields: fields{
+				util: util.NewHandlerUtil(l),
+				ra:   &RoleAuthenticatorStub{},
+				kymService: KymServiceStub{
+					getAllKym: func(status string, page *dto.PageRequest) (*dto.Page, error) {
+						if page.PageSize != 5 || page.PageToken != "abc" {
+							t.Errorf("unexpected page request: %+v", page)
+						}
+						return dto.NewPage([]*dto.KymResponse{}, ""), nil
+					},
+				},
+			},
+			expStatus: http.StatusOK,
+		},
+		{
+			name:  "getAllKymFailedWithPageSizeTooLarge",
+			query: "?pageSize=1000",
+			fields: fields{
+				util: util.NewHandlerUtil(l),
+				ra:   &RoleAuthenticatorStub{},
+			},
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "getAllKymFailedWithInvalidPageSize",
+			query: "?pageSize=ten",
+			fields: fields{
+				util: util.NewHandlerUtil(l),
+				ra:   &RoleAuthenticatorStub{},
+			},
+			expStatus: http.StatusBadRequest,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -334,7 +370,7 @@ func TestKymHandler_GetAllKym(t *testing.T) {
 				kymService: tt.fields.kymService,
 			}
 
-			req := httptest.NewRequest(http.MethodGet, "/kym", nil)
+			req := httptest.NewRequest(http.MethodGet, "/kym"+tt.query, nil)
 			rr := httptest.NewRecorder()
 
 			router := mux.NewRouter()
@@ -585,7 +621,7 @@ func TestKymHandler_UpdateKymStatus(t *testing.T) {
 type KymServiceStub struct {
 	addKym          func(kymRequest *dto.NewKym) error
 	getKym          func(id string) (*dto.KymFullDetailResponse, error)
-	getAllKym       func(status string) ([]*dto.KymResponse, error)
+	getAllKym       func(status string, page *dto.PageRequest) (*dto.Page, error)
 	updateKymStatus func(id string, kymStatusReq *dto.UpdateKymStatusRequest, userInfo string) error
 }
 
@@ -597,8 +633,8 @@ func (stub KymServiceStub) GetKym(id string) (*dto.KymFullDetailResponse, error)
 	return stub.getKym(id)
 }
 
-func (stub KymServiceStub) GetAllKym(status string) ([]*dto.KymResponse, error) {
-	return stub.getAllKym(status)
+func (stub KymServiceStub) GetAllKym(status string, page *dto.PageRequest) (*dto.Page, error) {
+	return stub.getAllKym(status, page)
 }
 
 func (stub KymServiceStub) UpdateKymStatus(id string, kymStatusReq *dto.UpdateKymStatusRequest, userInfo string) error {
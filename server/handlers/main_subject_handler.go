@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/gorilla/mux"
+
 	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
@@ -22,19 +25,26 @@ func NewMainSubjectHandler(util *util.HandlerUtil, mainSubjectService service.Ma
 }
 
 // GetAllMainSubject godoc
-// @Id GetAllKym
-// @Summary Get All Kym
-// @Description Returns all Kym detail only necessary field
-// @Tags kym
+// @Id GetAllMainSubject
+// @Summary Get All Main Subject
+// @Description Returns a page of main subjects
+// @Tags mainsubject
 // @Produce json
 // @Accept json
-// @Param status query string false "string enums" Enums("approved", "rejected", "pending")
-// @Success 200 {object} []dto.KymResponse "success"
+// @Param pageSize query int false "number of items per page, defaults to 30 and is at most 100"
+// @Param pageToken query string false "nextPageToken of the previous page"
+// @Success 200 {object} dto.Page{items=[]dto.MainSubject} "success"
 // @Failure default {object} util.APIResponse "fail"
-// @Router /kym [get]
+// @Router /mainsubject [get]
 func (m *MainSubjectHandler) GetAllMainSubject(rw http.ResponseWriter, r *http.Request) {
 
-	mainSubjectResponse, err := m.mainSubjectService.GetAllMainSubject()
+	page, err := pageParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	mainSubjectResponse, err := m.mainSubjectService.GetAllMainSubject(page)
 	if err != nil {
 		m.util.WrappedError(rw, err)
 		return
@@ -81,3 +91,148 @@ func (m *MainSubjectHandler) AddMainSubject(rw http.ResponseWriter, r *http.Requ
 
 	m.util.HTTPSuccess(rw, "success", http.StatusCreated)
 }
+
+// GetMainSubject godoc
+// @Id GetMainSubject
+// @Summary Get Main Subject
+// @Description Returns a single main subject
+// @Tags mainsubject
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} dto.MainSubject "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /mainsubject/{id} [get]
+func (m *MainSubjectHandler) GetMainSubject(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	res, err := m.mainSubjectService.GetMainSubject(id)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		m.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// UpdateMainSubject godoc
+// @Id UpdateMainSubject
+// @Summary Replace Main Subject
+// @Description Replaces every field of an existing main subject
+// @Tags mainsubject
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.NewMainSubject true "NewMainSubject entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /mainsubject/{id} [put]
+func (m *MainSubjectHandler) UpdateMainSubject(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.NewMainSubject{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		m.util.HTTPError(rw, fmt.Errorf("error deserializing main subject : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		m.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := m.mainSubjectService.UpdateMainSubject(id, req); err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	m.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// PatchMainSubject godoc
+// @Id PatchMainSubject
+// @Summary Update Main Subject
+// @Description Updates only the fields present in the request body, the rest of the main subject is kept
+// @Tags mainsubject
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.PatchMainSubject true "PatchMainSubject entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /mainsubject/{id} [patch]
+func (m *MainSubjectHandler) PatchMainSubject(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.PatchMainSubject{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		m.util.HTTPError(rw, fmt.Errorf("error deserializing main subject : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		m.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := m.mainSubjectService.PatchMainSubject(id, req); err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	m.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// DeleteMainSubject godoc
+// @Id DeleteMainSubject
+// @Summary Delete Main Subject
+// @Description Deletes a main subject, fails while subjects or teachers still use it unless cascade is set, and always while confirmation details ask for one of its subjects
+// @Tags mainsubject
+// @Produce json
+// @Param id path string true "id"
+// @Param cascade query bool false "also delete the subjects and teachers using the main subject"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /mainsubject/{id} [delete]
+func (m *MainSubjectHandler) DeleteMainSubject(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	cascade, err := cascadeParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	if err := m.mainSubjectService.DeleteMainSubject(id, cascade); err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	m.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
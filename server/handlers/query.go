@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+// cascadeParam reads the optional cascade query parameter of delete requests.
+// Deletes are blocked by entities still referring to the deleted one unless cascade=true is given.
+func cascadeParam(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("cascade")
+	if len(value) == 0 {
+		return false, nil
+	}
+
+	cascade, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &c.ErrValidation{Violations: fmt.Errorf("invalid cascade value %q", value)}
+	}
+	return cascade, nil
+}
+
+// checkQueryParams rejects query parameters other than the allowed ones
+func checkQueryParams(r *http.Request, allowed ...string) error {
+	unknown := make([]string, 0)
+	for name := range r.URL.Query() {
+		found := false
+		for _, a := range allowed {
+			if name == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return &c.ErrValidation{Violations: fmt.Errorf("unknown query parameters %v, allowed are %v", unknown, allowed)}
+	}
+	return nil
+}
+
+// pageParam reads the optional pageSize and pageToken query parameters of list requests
+func pageParam(r *http.Request) (*dto.PageRequest, error) {
+	page := dto.NewPageRequest()
+	page.PageToken = r.URL.Query().Get("pageToken")
+
+	if value := r.URL.Query().Get("pageSize"); len(value) > 0 {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, &c.ErrValidation{Violations: fmt.Errorf("invalid pageSize value %q", value)}
+		}
+		page.PageSize = size
+	}
+
+	if err := page.Validate(); err != nil {
+		return nil, &c.ErrValidation{Violations: err}
+	}
+	return page, nil
+}
+
+// teacherQueryParam reads the filter and sort query parameters of GET /teacher
+func teacherQueryParam(r *http.Request) (*dto.TeacherQuery, error) {
+	if err := checkQueryParams(r, "subjectId", "mainSubjectId", "q", "sort", "pageSize", "pageToken"); err != nil {
+		return nil, err
+	}
+
+	values := r.URL.Query()
+	query := &dto.TeacherQuery{
+		SubjectID:     values.Get("subjectId"),
+		MainSubjectID: values.Get("mainSubjectId"),
+		Q:             values.Get("q"),
+		Sort:          values.Get("sort"),
+	}
+
+	if err := query.Validate(); err != nil {
+		return nil, &c.ErrValidation{Violations: err}
+	}
+	return query, nil
+}
+
+// subjectQueryParam reads the filter query parameters of GET /subject
+func subjectQueryParam(r *http.Request) (*dto.SubjectQuery, error) {
+	if err := checkQueryParams(r, "teacherId", "mainSubjectId", "minStudentsGte", "pageSize", "pageToken"); err != nil {
+		return nil, err
+	}
+
+	values := r.URL.Query()
+	query := &dto.SubjectQuery{
+		TeacherID:     values.Get("teacherId"),
+		MainSubjectID: values.Get("mainSubjectId"),
+	}
+
+	if value := values.Get("minStudentsGte"); len(value) > 0 {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, &c.ErrValidation{Violations: fmt.Errorf("invalid minStudentsGte value %q", value)}
+		}
+		query.MinStudentsGte = n
+	}
+
+	if err := query.Validate(); err != nil {
+		return nil, &c.ErrValidation{Violations: err}
+	}
+	return query, nil
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+// RoomHandler is a handler for /room path
+type RoomHandler struct {
+	util        *util.HandlerUtil
+	roomService service.RoomServiceInterface
+}
+
+func NewRoomHandler(util *util.HandlerUtil, roomService service.RoomServiceInterface) *RoomHandler {
+	return &RoomHandler{util: util, roomService: roomService}
+}
+
+// GetAllRoom godoc
+// @Id GetAllRoom
+// @Summary Get All Room
+// @Description Returns a page of rooms
+// @Tags room
+// @Produce json
+// @Param pageSize query int false "number of items per page, defaults to 30 and is at most 100"
+// @Param pageToken query string false "nextPageToken of the previous page"
+// @Success 200 {object} dto.Page{items=[]dto.Room} "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /room [get]
+func (h *RoomHandler) GetAllRoom(rw http.ResponseWriter, r *http.Request) {
+
+	page, err := pageParam(r)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	res, err := h.roomService.GetAllRoom(page)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// AddRoom godoc
+// @Id AddRoom
+// @Summary Add Room
+// @Description Creates a room classes can be scheduled in
+// @Tags room
+// @Produce json
+// @Accept json
+// @Param requestBody body dto.NewRoom true "NewRoom entity"
+// @Success 201 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /room [post]
+func (h *RoomHandler) AddRoom(rw http.ResponseWriter, r *http.Request) {
+
+	req := &dto.NewRoom{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing room : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.roomService.AddRoom(req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusCreated)
+}
+
+// GetRoom godoc
+// @Id GetRoom
+// @Summary Get Room
+// @Description Returns a single room
+// @Tags room
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} dto.Room "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /room/{id} [get]
+func (h *RoomHandler) GetRoom(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.roomService.GetRoom(id)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// UpdateRoom godoc
+// @Id UpdateRoom
+// @Summary Replace Room
+// @Description Replaces every field of an existing room
+// @Tags room
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.NewRoom true "NewRoom entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /room/{id} [put]
+func (h *RoomHandler) UpdateRoom(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.NewRoom{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing room : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.roomService.UpdateRoom(id, req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// DeleteRoom godoc
+// @Id DeleteRoom
+// @Summary Delete Room
+// @Description Deletes a room, schedules generated earlier keep referring to it
+// @Tags room
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /room/{id} [delete]
+func (h *RoomHandler) DeleteRoom(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.roomService.DeleteRoom(id); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+func TestRoomHandler_AddRoom(t *testing.T) {
+	l := util.NewLogger(true)
+	addSuccessful := RoomServiceStub{
+		addRoom: func(nr *dto.NewRoom) error {
+			return nil
+		},
+	}
+	tests := []struct {
+		name        string
+		reqBody     string
+		roomService service.RoomServiceInterface
+		expStatus   int
+	}{
+		{
+			name:        "addRoomSuccessful",
+			reqBody:     `{"name": "Music room 1", "capacity": 12, "features": ["piano"]}`,
+			roomService: addSuccessful,
+			expStatus:   http.StatusCreated,
+		},
+		{
+			name:        "addRoomSuccessfulWithoutFeatures",
+			reqBody:     `{"name": "Classroom 1", "capacity": 30}`,
+			roomService: addSuccessful,
+			expStatus:   http.StatusCreated,
+		},
+		{
+			name:      "addRoomFailedWithoutCapacity",
+			reqBody:   `{"name": "Music room 1", "features": ["piano"]}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "addRoomFailedWithEmptyFeature",
+			reqBody:   `{"name": "Music room 1", "capacity": 12, "features": [""]}`,
+			expStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewRoomHandler(util.NewHandlerUtil(l), tt.roomService)
+
+			req := httptest.NewRequest(http.MethodPost, "/room", strings.NewReader(tt.reqBody))
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodPost).Path("/room").HandlerFunc(h.AddRoom)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+// RoomServiceStub is a stub struct that proxies method calls to function fields.
+type RoomServiceStub struct {
+	addRoom func(nr *dto.NewRoom) error
+
+	getAllRoom func(page *dto.PageRequest) (*dto.Page, error)
+
+	getRoom func(id string) (*dto.Room, error)
+
+	updateRoom func(id string, nr *dto.NewRoom) error
+
+	deleteRoom func(id string) error
+}
+
+func (s RoomServiceStub) AddRoom(nr *dto.NewRoom) error {
+	return s.addRoom(nr)
+}
+
+func (s RoomServiceStub) GetAllRoom(page *dto.PageRequest) (*dto.Page, error) {
+	return s.getAllRoom(page)
+}
+
+func (s RoomServiceStub) GetRoom(id string) (*dto.Room, error) {
+	return s.getRoom(id)
+}
+
+func (s RoomServiceStub) UpdateRoom(id string, nr *dto.NewRoom) error {
+	return s.updateRoom(id, nr)
+}
+
+func (s RoomServiceStub) DeleteRoom(id string) error {
+	return s.deleteRoom(id)
+}
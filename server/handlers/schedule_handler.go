@@ -1,27 +1,297 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/schedule-school-teachning-bsd13-backend/util"
 	"net/http"
-)
 
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
 
+// ScheduleHandler is a handler for /create-schedule and /schedule paths
 type ScheduleHandler struct {
-	util       *util.HandlerUtil
+	util            *util.HandlerUtil
+	scheduleService service.ScheduleServiceInterface
 }
 
-func NewScheduleHandler(util *util.HandlerUtil) *ScheduleHandler {
-	return &ScheduleHandler{util: util}
+func NewScheduleHandler(util *util.HandlerUtil, scheduleService service.ScheduleServiceInterface) *ScheduleHandler {
+	return &ScheduleHandler{util: util, scheduleService: scheduleService}
 }
 
+// ScheduleClass godoc
+// @Id ScheduleClass
+// @Summary Generate a timetable
+// @Description Assigns a teacher and time slot to every detail of a submitted or locked confirmation and stores the resulting timetable
+// @Tags schedule
+// @Produce json
+// @Accept json
+// @Param requestBody body dto.NewSchedule true "NewSchedule entity"
+// @Success 201 {object} dto.Schedule "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /create-schedule [post]
 func (m *ScheduleHandler) ScheduleClass(rw http.ResponseWriter, r *http.Request) {
+	req := &dto.NewSchedule{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		m.util.HTTPError(rw, fmt.Errorf("error deserializing schedule %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		m.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	schedule, err := m.scheduleService.CreateSchedule(req)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(schedule)
+	if err != nil {
+		m.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusCreated)
+	rw.Write(resp)
+}
+
+// GetSchedule godoc
+// @Id GetSchedule
+// @Summary Get a generated timetable
+// @Description Returns the timetable with all of its slots and the lessons that could not be placed
+// @Tags schedule
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "id"
+// @Param format query string false "download the rows as a spreadsheet instead" Enums(csv, xlsx)
+// @Success 200 {object} dto.Schedule "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /schedule/{id} [get]
+func (m *ScheduleHandler) GetSchedule(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
 
+	if m.exportSchedule(rw, r, id, "", "") {
+		return
+	}
 
-	membershipNumber := r.FormValue("memberkey")
-	fmt.Println("GET params were:", membershipNumber)
+	schedule, err := m.scheduleService.GetSchedule(id)
+	m.writeSchedule(rw, schedule, err)
+}
+
+// GetTeacherSchedule godoc
+// @Id GetTeacherSchedule
+// @Summary Get a teacher's view of a generated timetable
+// @Description Returns only the slots of the timetable taught by the teacher
+// @Tags schedule
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "id"
+// @Param teacherId path string true "teacherId"
+// @Param format query string false "download the rows as a spreadsheet instead" Enums(csv, xlsx)
+// @Success 200 {object} dto.Schedule "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /schedule/{id}/teacher/{teacherId} [get]
+func (m *ScheduleHandler) GetTeacherSchedule(rw http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+	teacherID, ok := vars["teacherId"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid teacherId in path"), http.StatusBadRequest)
+		return
+	}
+
+	if m.exportSchedule(rw, r, id, teacherID, "") {
+		return
+	}
+
+	schedule, err := m.scheduleService.GetTeacherSchedule(id, teacherID)
+	m.writeSchedule(rw, schedule, err)
+}
+
+// GetStudentSchedule godoc
+// @Id GetStudentSchedule
+// @Summary Get a student's view of a generated timetable
+// @Description Returns only the slots attended by the student and the student's lessons that could not be placed
+// @Tags schedule
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "id"
+// @Param studentId path string true "studentId"
+// @Param format query string false "download the rows as a spreadsheet instead" Enums(csv, xlsx)
+// @Success 200 {object} dto.Schedule "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /schedule/{id}/student/{studentId} [get]
+func (m *ScheduleHandler) GetStudentSchedule(rw http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+	studentID, ok := vars["studentId"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid studentId in path"), http.StatusBadRequest)
+		return
+	}
 
-	m.util.HTTPSuccess(rw, "success", http.StatusCreated)
+	if m.exportSchedule(rw, r, id, "", studentID) {
+		return
+	}
+
+	schedule, err := m.scheduleService.GetStudentSchedule(id, studentID)
+	m.writeSchedule(rw, schedule, err)
 }
 
+// GetScheduleSessions godoc
+// @Id GetScheduleSessions
+// @Summary Get the dated sessions of a generated timetable
+// @Description Expands the weekly slots into a session on every date of the term, holidays and the dates a teacher is away on are skipped
+// @Tags schedule
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "id"
+// @Param teacherId query string false "only the sessions taught by the teacher"
+// @Param studentId query string false "only the sessions attended by the student"
+// @Param format query string false "download the sessions as a spreadsheet instead" Enums(csv, xlsx)
+// @Success 200 {object} dto.ScheduleSessions "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /schedule/{id}/sessions [get]
+func (m *ScheduleHandler) GetScheduleSessions(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
 
+	if err := checkQueryParams(r, "teacherId", "studentId", "format"); err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+	q := r.URL.Query()
+
+	format, err := exportFormatParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+	if isExportFormat(format) {
+		export, err := m.scheduleService.ExportScheduleSessions(id, q.Get("teacherId"), q.Get("studentId"))
+		if err != nil {
+			m.util.WrappedError(rw, err)
+			return
+		}
+		writeExport(m.util, rw, format, export)
+		return
+	}
+
+	sessions, err := m.scheduleService.GetScheduleSessions(id, q.Get("teacherId"), q.Get("studentId"))
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(sessions)
+	if err != nil {
+		m.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// exportSchedule writes the slots of the schedule as a spreadsheet when the format query parameter asks
+// for one, it tells whether the request was handled
+func (m *ScheduleHandler) exportSchedule(rw http.ResponseWriter, r *http.Request, id string, teacherID string, studentID string) bool {
+	format, err := exportFormatParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return true
+	}
+	if !isExportFormat(format) {
+		return false
+	}
+
+	export, err := m.scheduleService.ExportSchedule(id, teacherID, studentID)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return true
+	}
+	writeExport(m.util, rw, format, export)
+	return true
+}
+
+// writeSchedule writes the schedule or the error returned by the service
+func (m *ScheduleHandler) writeSchedule(rw http.ResponseWriter, schedule *dto.Schedule, err error) {
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(schedule)
+	if err != nil {
+		m.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// GetTeacherCalendar godoc
+// @Id GetTeacherCalendar
+// @Summary Get Teacher Calendar
+// @Description Returns the classes of the teacher as an iCalendar file with an event repeating every week of the term, holidays and the teacher's absences are excluded
+// @Tags teacher
+// @Produce text/calendar
+// @Param id path string true "id"
+// @Param scheduleId query string true "schedule to read the classes from"
+// @Success 200 {string} string "iCalendar file"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id}/calendar.ics [get]
+func (h *ScheduleHandler) GetTeacherCalendar(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	if err := checkQueryParams(r, "scheduleId"); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	cal, err := h.scheduleService.GetTeacherCalendar(id, r.URL.Query().Get("scheduleId"))
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	var ics bytes.Buffer
+	if err := writeTeacherCalendar(&ics, cal); err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to write calendar: %w", err))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/calendar;charset=utf-8")
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(ics.Bytes())
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+func TestScheduleHandler_ScheduleClass(t *testing.T) {
+	l := util.NewLogger(true)
+	tests := []struct {
+		name            string
+		reqBody         string
+		scheduleService service.ScheduleServiceInterface
+		expStatus       int
+	}{
+		{
+			name:    "createScheduleSuccessful",
+			reqBody: `{"confirmationId": "c1"}`,
+			scheduleService: ScheduleServiceStub{
+				createSchedule: func(request *dto.NewSchedule) (*dto.Schedule, error) {
+					return &dto.Schedule{ID: "s1", ConfirmationID: request.ConfirmationID}, nil
+				},
+			},
+			expStatus: http.StatusCreated,
+		},
+		{
+			name:      "createScheduleFailedWithMissingConfirmationID",
+			reqBody:   `{}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "createScheduleFailedWithNoDetails",
+			reqBody: `{"confirmationId": "c1"}`,
+			scheduleService: ScheduleServiceStub{
+				createSchedule: func(request *dto.NewSchedule) (*dto.Schedule, error) {
+					return nil, &c.ErrValidation{Violations: errors.New("confirmation c1 has no details to schedule")}
+				},
+			},
+			expStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewScheduleHandler(util.NewHandlerUtil(l), tt.scheduleService)
+
+			req := httptest.NewRequest(http.MethodPost, "/create-schedule", strings.NewReader(tt.reqBody))
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodPost).Path("/create-schedule").HandlerFunc(h.ScheduleClass)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+func TestScheduleHandler_GetStudentSchedule(t *testing.T) {
+	l := util.NewLogger(true)
+	tests := []struct {
+		name            string
+		scheduleService service.ScheduleServiceInterface
+		expStatus       int
+	}{
+		{
+			name: "getStudentScheduleSuccessful",
+			scheduleService: ScheduleServiceStub{
+				getStudentSchedule: func(id string, studentID string) (*dto.Schedule, error) {
+					if studentID != "st1" {
+						return nil, c.ErrDBNoSuchEntity
+					}
+					return &dto.Schedule{
+						ID: id,
+						Slots: []*dto.ScheduleSlot{
+							{ID: "slot1", TeacherID: "t1", SubjectID: "math", Day: "monday", Period: 1, StudentNames: []string{"alice"}, StudentIDs: []string{studentID}},
+						},
+					}, nil
+				},
+			},
+			expStatus: http.StatusOK,
+		},
+		{
+			name: "getStudentScheduleFailedWithDBNoSuchEntity",
+			scheduleService: ScheduleServiceStub{
+				getStudentSchedule: func(id string, studentID string) (*dto.Schedule, error) {
+					return nil, c.ErrDBNoSuchEntity
+				},
+			},
+			expStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewScheduleHandler(util.NewHandlerUtil(l), tt.scheduleService)
+
+			req := httptest.NewRequest(http.MethodGet, "/schedule/s1/student/st1", nil)
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodGet).Path("/schedule/{id}/student/{studentId}").HandlerFunc(h.GetStudentSchedule)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+
+			if isHTTPSuccess(resp.StatusCode) {
+				schedule := &dto.Schedule{}
+				if err := json.NewDecoder(rr.Body).Decode(schedule); err != nil {
+					t.Errorf("error unmarshelling response: %v", err)
+				}
+				if schedule.ID != "s1" || len(schedule.Slots) != 1 {
+					t.Errorf("unexpected schedule: %+v", schedule)
+				}
+			}
+		})
+	}
+}
+
+// ScheduleServiceStub is a stub struct that proxies method calls to function fields.
+type ScheduleServiceStub struct {
+	createSchedule func(request *dto.NewSchedule) (*dto.Schedule, error)
+
+	getSchedule func(id string) (*dto.Schedule, error)
+
+	getTeacherSchedule func(id string, teacherID string) (*dto.Schedule, error)
+
+	getStudentSchedule func(id string, studentID string) (*dto.Schedule, error)
+
+	getScheduleSessions func(id string, teacherID string, studentID string) (*dto.ScheduleSessions, error)
+
+	getTeacherCalendar func(teacherID string, scheduleID string) (*dto.TeacherCalendar, error)
+
+	exportSchedule func(id string, teacherID string, studentID string) (*dto.Export, error)
+
+	exportScheduleSessions func(id string, teacherID string, studentID string) (*dto.Export, error)
+}
+
+func (stub ScheduleServiceStub) CreateSchedule(request *dto.NewSchedule) (*dto.Schedule, error) {
+	return stub.createSchedule(request)
+}
+
+func (stub ScheduleServiceStub) GetSchedule(id string) (*dto.Schedule, error) {
+	return stub.getSchedule(id)
+}
+
+func (stub ScheduleServiceStub) GetTeacherSchedule(id string, teacherID string) (*dto.Schedule, error) {
+	return stub.getTeacherSchedule(id, teacherID)
+}
+
+func (stub ScheduleServiceStub) GetStudentSchedule(id string, studentID string) (*dto.Schedule, error) {
+	return stub.getStudentSchedule(id, studentID)
+}
+
+func (stub ScheduleServiceStub) GetScheduleSessions(id string, teacherID string, studentID string) (*dto.ScheduleSessions, error) {
+	return stub.getScheduleSessions(id, teacherID, studentID)
+}
+
+func (stub ScheduleServiceStub) GetTeacherCalendar(teacherID string, scheduleID string) (*dto.TeacherCalendar, error) {
+	return stub.getTeacherCalendar(teacherID, scheduleID)
+}
+
+func (stub ScheduleServiceStub) ExportSchedule(id string, teacherID string, studentID string) (*dto.Export, error) {
+	return stub.exportSchedule(id, teacherID, studentID)
+}
+
+func (stub ScheduleServiceStub) ExportScheduleSessions(id string, teacherID string, studentID string) (*dto.Export, error) {
+	return stub.exportScheduleSessions(id, teacherID, studentID)
+}
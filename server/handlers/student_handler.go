@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+// StudentHandler is a handler for /student path
+type StudentHandler struct {
+	util           *util.HandlerUtil
+	studentService service.StudentServiceInterface
+}
+
+func NewStudentHandler(util *util.HandlerUtil, studentService service.StudentServiceInterface) *StudentHandler {
+	return &StudentHandler{util: util, studentService: studentService}
+}
+
+// GetAllStudent godoc
+// @Id GetAllStudent
+// @Summary Get All Student
+// @Description Returns a page of students ordered by name
+// @Tags student
+// @Produce json
+// @Param pageSize query int false "number of items per page, defaults to 30 and is at most 100"
+// @Param pageToken query string false "nextPageToken of the previous page"
+// @Success 200 {object} dto.Page{items=[]dto.Student} "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /student [get]
+func (h *StudentHandler) GetAllStudent(rw http.ResponseWriter, r *http.Request) {
+
+	page, err := pageParam(r)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	res, err := h.studentService.GetAllStudent(page)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// AddStudent godoc
+// @Id AddStudent
+// @Summary Add Student
+// @Description Creates a student confirmation details can refer to
+// @Tags student
+// @Produce json
+// @Accept json
+// @Param requestBody body dto.NewStudent true "NewStudent entity"
+// @Success 201 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /student [post]
+func (h *StudentHandler) AddStudent(rw http.ResponseWriter, r *http.Request) {
+
+	req := &dto.NewStudent{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing student : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.studentService.AddStudent(req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusCreated)
+}
+
+// GetStudent godoc
+// @Id GetStudent
+// @Summary Get Student
+// @Description Returns a single student
+// @Tags student
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} dto.Student "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /student/{id} [get]
+func (h *StudentHandler) GetStudent(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.studentService.GetStudent(id)
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// UpdateStudent godoc
+// @Id UpdateStudent
+// @Summary Replace Student
+// @Description Replaces every field of an existing student
+// @Tags student
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.NewStudent true "NewStudent entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /student/{id} [put]
+func (h *StudentHandler) UpdateStudent(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.NewStudent{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		h.util.HTTPError(rw, fmt.Errorf("error deserializing student : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := h.studentService.UpdateStudent(id, req); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// DeleteStudent godoc
+// @Id DeleteStudent
+// @Summary Delete Student
+// @Description Deletes a student, fails while confirmation details still refer to it
+// @Tags student
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /student/{id} [delete]
+func (h *StudentHandler) DeleteStudent(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.studentService.DeleteStudent(id); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	h.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// GetStudentTimetable godoc
+// @Id GetStudentTimetable
+// @Summary Get Student Timetable
+// @Description Returns the lessons of the student in a schedule grouped by day and period, as json, as a printable html page or as a spreadsheet
+// @Tags student
+// @Produce json,html,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "id"
+// @Param scheduleId query string true "schedule to read the timetable from"
+// @Param format query string false "format of the response, defaults to json" Enums(json, html, csv, xlsx)
+// @Success 200 {object} dto.StudentTimetable "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /student/{id}/timetable [get]
+func (h *StudentHandler) GetStudentTimetable(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		h.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	if err := checkQueryParams(r, "scheduleId", "format"); err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+	format, err := exportFormatParam(r, "json", "html")
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+	if isExportFormat(format) {
+		export, err := h.studentService.ExportStudentTimetable(id, r.URL.Query().Get("scheduleId"))
+		if err != nil {
+			h.util.WrappedError(rw, err)
+			return
+		}
+		writeExport(h.util, rw, format, export)
+		return
+	}
+
+	timetable, err := h.studentService.GetStudentTimetable(id, r.URL.Query().Get("scheduleId"))
+	if err != nil {
+		h.util.WrappedError(rw, err)
+		return
+	}
+
+	if format == "html" {
+		var page bytes.Buffer
+		if err := renderStudentTimetable(&page, timetable); err != nil {
+			h.util.WrappedError(rw, fmt.Errorf("unable to render timetable: %w", err))
+			return
+		}
+		rw.Header().Set("Content-Type", "text/html;charset=utf8")
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(page.Bytes())
+		return
+	}
+
+	resp, err := json.Marshal(timetable)
+	if err != nil {
+		h.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
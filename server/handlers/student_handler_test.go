@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+func TestStudentHandler_AddStudent(t *testing.T) {
+	l := util.NewLogger(true)
+	addSuccessful := StudentServiceStub{
+		addStudent: func(ns *dto.NewStudent) error {
+			return nil
+		},
+	}
+	tests := []struct {
+		name           string
+		reqBody        string
+		studentService service.StudentServiceInterface
+		expStatus      int
+	}{
+		{
+			name:           "addStudentSuccessful",
+			reqBody:        `{"name": "Somchai Jaidee", "level": "P.4", "guardianName": "Somsri Jaidee", "guardianEmail": "somsri@example.com"}`,
+			studentService: addSuccessful,
+			expStatus:      http.StatusCreated,
+		},
+		{
+			name:      "addStudentFailedWithoutLevel",
+			reqBody:   `{"name": "Somchai Jaidee"}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "addStudentFailedWithInvalidGuardianEmail",
+			reqBody:   `{"name": "Somchai Jaidee", "level": "P.4", "guardianEmail": "somsri"}`,
+			expStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewStudentHandler(util.NewHandlerUtil(l), tt.studentService)
+
+			req := httptest.NewRequest(http.MethodPost, "/student", strings.NewReader(tt.reqBody))
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodPost).Path("/student").HandlerFunc(h.AddStudent)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+func TestStudentHandler_GetStudentTimetable(t *testing.T) {
+	l := util.NewLogger(true)
+	timetable := StudentServiceStub{
+		getStudentTimetable: func(id string, scheduleID string) (*dto.StudentTimetable, error) {
+			return &dto.StudentTimetable{
+				StudentID:   id,
+				StudentName: "Somchai <Jaidee>",
+				Level:       "P.4",
+				ScheduleID:  "sc1",
+				Days: []*dto.TimetableDay{
+					{Day: model.Tuesday, Periods: []*dto.TimetablePeriod{
+						{Period: 2, SubjectID: "piano", SubjectName: "Piano", TeacherName: "Alice", RoomName: "Music room",
+							PeriodTime: dto.PeriodTime{StartTime: "09:40", EndTime: "10:30"}},
+					}},
+				},
+				Unscheduled: []*dto.UnscheduledLesson{},
+			}, nil
+		},
+	}
+	tests := []struct {
+		name           string
+		query          string
+		studentService service.StudentServiceInterface
+		expStatus      int
+		expContentType string
+		expBody        []string
+	}{
+		{
+			name:           "getTimetableAsJSON",
+			studentService: timetable,
+			expStatus:      http.StatusOK,
+			expBody:        []string{`"subjectName":"Piano"`, `"day":"tuesday"`},
+		},
+		{
+			name:           "getTimetableAsHTML",
+			query:          "?format=html",
+			studentService: timetable,
+			expStatus:      http.StatusOK,
+			expContentType: "text/html",
+			expBody:        []string{"Somchai &lt;Jaidee&gt;", "<th>Tuesday</th>", "<th>Friday</th>", "Piano", "09:40 - 10:30", "Alice, Music room"},
+		},
+		{
+			name:      "getTimetableFailedWithUnknownFormat",
+			query:     "?format=pdf",
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "getTimetableFailedWithUnknownParameter",
+			query:     "?week=1",
+			expStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewStudentHandler(util.NewHandlerUtil(l), tt.studentService)
+
+			req := httptest.NewRequest(http.MethodGet, "/student/s1/timetable"+tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodGet).Path("/student/{id}/timetable").HandlerFunc(h.GetStudentTimetable)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+			if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, tt.expContentType) {
+				t.Errorf("unexpected content type: got %v want %v", ct, tt.expContentType)
+			}
+			for _, want := range tt.expBody {
+				if !strings.Contains(rr.Body.String(), want) {
+					t.Errorf("expecting body to contain %q, got %v", want, rr.Body.String())
+				}
+			}
+		})
+	}
+}
+
+// StudentServiceStub is a stub struct that proxies method calls to function fields.
+type StudentServiceStub struct {
+	addStudent func(ns *dto.NewStudent) error
+
+	getAllStudent func(page *dto.PageRequest) (*dto.Page, error)
+
+	getStudent func(id string) (*dto.Student, error)
+
+	updateStudent func(id string, ns *dto.NewStudent) error
+
+	deleteStudent func(id string) error
+
+	getStudentTimetable func(id string, scheduleID string) (*dto.StudentTimetable, error)
+
+	exportStudentTimetable func(id string, scheduleID string) (*dto.Export, error)
+}
+
+func (s StudentServiceStub) AddStudent(ns *dto.NewStudent) error {
+	return s.addStudent(ns)
+}
+
+func (s StudentServiceStub) GetAllStudent(page *dto.PageRequest) (*dto.Page, error) {
+	return s.getAllStudent(page)
+}
+
+func (s StudentServiceStub) GetStudent(id string) (*dto.Student, error) {
+	return s.getStudent(id)
+}
+
+func (s StudentServiceStub) UpdateStudent(id string, ns *dto.NewStudent) error {
+	return s.updateStudent(id, ns)
+}
+
+func (s StudentServiceStub) DeleteStudent(id string) error {
+	return s.deleteStudent(id)
+}
+
+func (s StudentServiceStub) GetStudentTimetable(id string, scheduleID string) (*dto.StudentTimetable, error) {
+	return s.getStudentTimetable(id, scheduleID)
+}
+
+func (s StudentServiceStub) ExportStudentTimetable(id string, scheduleID string) (*dto.Export, error) {
+	return s.exportStudentTimetable(id, scheduleID)
+}
@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/gorilla/mux"
+
 	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
@@ -24,7 +27,19 @@ func NewSubjectHandler(util *util.HandlerUtil, subjectService service.SubjectSer
 
 func (m *SubjectHandler) GetAllSubject(rw http.ResponseWriter, r *http.Request) {
 
-	subjectResponse, err := m.subjectService.GetAllSubject()
+	query, err := subjectQueryParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	page, err := pageParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	subjectResponse, err := m.subjectService.GetAllSubject(query, page)
 	if err != nil {
 		m.util.WrappedError(rw, err)
 		return
@@ -61,3 +76,153 @@ func (m *SubjectHandler) AddSubject(rw http.ResponseWriter, r *http.Request) {
 
 	m.util.HTTPSuccess(rw, "success", http.StatusCreated)
 }
+
+func (m *SubjectHandler) GetSubject(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	res, err := m.subjectService.GetSubject(id)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		m.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+func (m *SubjectHandler) UpdateSubject(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.NewSubject{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		m.util.HTTPError(rw, fmt.Errorf("error deserializing subject : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		m.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := m.subjectService.UpdateSubject(id, req); err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	m.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+func (m *SubjectHandler) PatchSubject(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.PatchSubject{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		m.util.HTTPError(rw, fmt.Errorf("error deserializing subject : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		m.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := m.subjectService.PatchSubject(id, req); err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	m.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+func (m *SubjectHandler) DeleteSubject(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		m.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	cascade, err := cascadeParam(r)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	if err := m.subjectService.DeleteSubject(id, cascade); err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	m.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// ImportSubjects godoc
+// @Id ImportSubjects
+// @Summary Import Subjects
+// @Description Adds or replaces the subjects of a csv or xlsx spreadsheet with the columns id, subjectName, mainSubject, minOfStudent and requiredFeatures.
+// @Description A row replaces the subject with its id, or without an id the subject with the same name. Main subjects are given by name.
+// @Tags subject
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "spreadsheet, .csv or .xlsx"
+// @Param createMainSubjects query bool false "create the main subjects which do not exist yet"
+// @Param dryRun query bool false "only check the rows, nothing is written"
+// @Success 200 {object} dto.ImportSummary "import summary"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /subject/import [post]
+func (m *SubjectHandler) ImportSubjects(rw http.ResponseWriter, r *http.Request) {
+
+	sheet, dryRun, err := importParam(rw, r, "createMainSubjects")
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	createMainSubjects, err := boolParam(r, "createMainSubjects")
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	rows, err := dto.ToSubjectRows(sheet)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	summary, err := m.subjectService.ImportSubjects(rows, createMainSubjects, dryRun)
+	if err != nil {
+		m.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(summary)
+	if err != nil {
+		m.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/gorilla/mux"
+
 	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
@@ -22,19 +25,36 @@ func NewTeacherHandler(util *util.HandlerUtil, teacherService service.TeacherSer
 }
 
 // GetAllTeacher godoc
-// @Id GetAllKym
-// @Summary Get All Kym
-// @Description Returns all Kym detail only necessary field
-// @Tags kym
+// @Id GetAllTeacher
+// @Summary Get All Teacher
+// @Description Returns a page of teachers, optionally filtered and sorted. Unknown query parameters are rejected.
+// @Tags teacher
 // @Produce json
 // @Accept json
-// @Param status query string false "string enums" Enums("approved", "rejected", "pending")
-// @Success 200 {object} []dto.KymResponse "success"
+// @Param subjectId query string false "only teachers qualified for this subject, cannot be combined with the other filters"
+// @Param mainSubjectId query string false "only teachers of this main subject"
+// @Param q query string false "only teachers whose first, nick or last name starts with this text"
+// @Param sort query string false "order of the teachers" Enums(firstName, nickName, lastName)
+// @Param pageSize query int false "number of items per page, defaults to 30 and is at most 100"
+// @Param pageToken query string false "nextPageToken of the previous page"
+// @Success 200 {object} dto.Page{items=[]dto.Teacher} "success"
 // @Failure default {object} util.APIResponse "fail"
-// @Router /kym [get]
+// @Router /teacher [get]
 func (t *TeacherHandler) GetAllTeacher(rw http.ResponseWriter, r *http.Request) {
 
-	teacherResponse, err := t.teacherService.GetAllTeacher()
+	query, err := teacherQueryParam(r)
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	page, err := pageParam(r)
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	teacherResponse, err := t.teacherService.GetAllTeacher(query, page)
 	if err != nil {
 		t.util.WrappedError(rw, err)
 		return
@@ -81,3 +101,454 @@ func (t *TeacherHandler) AddTeacher(rw http.ResponseWriter, r *http.Request) {
 
 	t.util.HTTPSuccess(rw, "success", http.StatusCreated)
 }
+
+// GetTeacherAvailability godoc
+// @Id GetTeacherAvailability
+// @Summary Get Teacher Availability
+// @Description Returns the weekly windows and date exceptions of a teacher
+// @Tags teacher
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} dto.TeacherAvailability "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id}/availability [get]
+func (t *TeacherHandler) GetTeacherAvailability(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	availability, err := t.teacherService.GetTeacherAvailability(id)
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(availability)
+	if err != nil {
+		t.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// UpdateTeacherAvailability godoc
+// @Id UpdateTeacherAvailability
+// @Summary Replace Teacher Availability
+// @Description Replaces the weekly windows and date exceptions of a teacher, the scheduler will not place the teacher outside of them
+// @Tags teacher
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.TeacherAvailability true "TeacherAvailability entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id}/availability [put]
+func (t *TeacherHandler) UpdateTeacherAvailability(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	availability := &dto.TeacherAvailability{}
+	if err := json.NewDecoder(r.Body).Decode(availability); err != nil {
+		t.util.HTTPError(rw, fmt.Errorf("error deserializing teacher availability : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := availability.Validate(); err != nil {
+		t.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := t.teacherService.UpdateTeacherAvailability(id, availability); err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	t.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// GetTeacherSubjects godoc
+// @Id GetTeacherSubjects
+// @Summary Get Teacher Subjects
+// @Description Returns the subjects a teacher is explicitly qualified for on top of the teacher's main subject
+// @Tags teacher
+// @Produce json
+// @Param id path string true "id"
+// @Param pageSize query int false "number of items per page, defaults to 30 and is at most 100"
+// @Param pageToken query string false "nextPageToken of the previous page"
+// @Success 200 {object} dto.Page{items=[]dto.TeacherResponsibility} "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id}/subjects [get]
+func (t *TeacherHandler) GetTeacherSubjects(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	page, err := pageParam(r)
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	subjects, err := t.teacherService.GetTeacherSubjects(id, page)
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(subjects)
+	if err != nil {
+		t.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// AddTeacherSubject godoc
+// @Id AddTeacherSubject
+// @Summary Qualify Teacher for a Subject
+// @Description Qualifies the teacher to teach an existing subject
+// @Tags teacher
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.NewTeacherSubject true "NewTeacherSubject entity"
+// @Success 201 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id}/subjects [post]
+func (t *TeacherHandler) AddTeacherSubject(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	ts := &dto.NewTeacherSubject{}
+	if err := json.NewDecoder(r.Body).Decode(ts); err != nil {
+		t.util.HTTPError(rw, fmt.Errorf("error deserializing teacher subject : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := ts.Validate(); err != nil {
+		t.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := t.teacherService.AddTeacherSubject(id, ts); err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	t.util.HTTPSuccess(rw, "success", http.StatusCreated)
+}
+
+// UpdateTeacherSubjects godoc
+// @Id UpdateTeacherSubjects
+// @Summary Replace Teacher Subjects
+// @Description Replaces all subjects the teacher is explicitly qualified for
+// @Tags teacher
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.TeacherSubjects true "TeacherSubjects entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id}/subjects [put]
+func (t *TeacherHandler) UpdateTeacherSubjects(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	ts := &dto.TeacherSubjects{}
+	if err := json.NewDecoder(r.Body).Decode(ts); err != nil {
+		t.util.HTTPError(rw, fmt.Errorf("error deserializing teacher subjects : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := ts.Validate(); err != nil {
+		t.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := t.teacherService.UpdateTeacherSubjects(id, ts); err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	t.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// DeleteTeacherSubject godoc
+// @Id DeleteTeacherSubject
+// @Summary Remove Teacher Subject
+// @Description Removes a subject the teacher was explicitly qualified for
+// @Tags teacher
+// @Produce json
+// @Param id path string true "id"
+// @Param subjectId path string true "subjectId"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id}/subjects/{subjectId} [delete]
+func (t *TeacherHandler) DeleteTeacherSubject(rw http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+	subjectID, ok := vars["subjectId"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid subjectId in path"), http.StatusBadRequest)
+		return
+	}
+
+	if err := t.teacherService.DeleteTeacherSubject(id, subjectID); err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	t.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// GetTeacherWorkload godoc
+// @Id GetTeacherWorkload
+// @Summary Get Teacher Workload
+// @Description Returns the periods and students booked for the teacher in a schedule against the teacher's limits
+// @Tags teacher
+// @Produce json
+// @Param id path string true "id"
+// @Param scheduleId query string true "schedule to compute the workload from"
+// @Success 200 {object} dto.TeacherWorkload "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id}/workload [get]
+func (t *TeacherHandler) GetTeacherWorkload(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	scheduleID := r.URL.Query().Get("scheduleId")
+
+	workload, err := t.teacherService.GetTeacherWorkload(id, scheduleID)
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(workload)
+	if err != nil {
+		t.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// GetTeacher godoc
+// @Id GetTeacher
+// @Summary Get Teacher
+// @Description Returns a single teacher
+// @Tags teacher
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} dto.Teacher "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id} [get]
+func (t *TeacherHandler) GetTeacher(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	res, err := t.teacherService.GetTeacher(id)
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		t.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
+
+// UpdateTeacher godoc
+// @Id UpdateTeacher
+// @Summary Replace Teacher
+// @Description Replaces every field of an existing teacher
+// @Tags teacher
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.NewTeacher true "NewTeacher entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id} [put]
+func (t *TeacherHandler) UpdateTeacher(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.NewTeacher{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		t.util.HTTPError(rw, fmt.Errorf("error deserializing teacher : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		t.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := t.teacherService.UpdateTeacher(id, req); err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	t.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// PatchTeacher godoc
+// @Id PatchTeacher
+// @Summary Update Teacher
+// @Description Updates only the fields present in the request body, the rest of the teacher is kept
+// @Tags teacher
+// @Produce json
+// @Accept json
+// @Param id path string true "id"
+// @Param requestBody body dto.PatchTeacher true "PatchTeacher entity"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id} [patch]
+func (t *TeacherHandler) PatchTeacher(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	req := &dto.PatchTeacher{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		t.util.HTTPError(rw, fmt.Errorf("error deserializing teacher : %w", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		t.util.WrappedError(rw, &c.ErrValidation{Violations: err})
+		return
+	}
+
+	if err := t.teacherService.PatchTeacher(id, req); err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	t.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// DeleteTeacher godoc
+// @Id DeleteTeacher
+// @Summary Delete Teacher
+// @Description Deletes a teacher
+// @Tags teacher
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} util.APIResponse "success"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/{id} [delete]
+func (t *TeacherHandler) DeleteTeacher(rw http.ResponseWriter, r *http.Request) {
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		t.util.HTTPError(rw, errors.New("invalid id in path"), http.StatusBadRequest)
+		return
+	}
+
+	if err := t.teacherService.DeleteTeacher(id); err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	t.util.HTTPSuccess(rw, "success", http.StatusOK)
+}
+
+// ImportTeachers godoc
+// @Id ImportTeachers
+// @Summary Import Teachers
+// @Description Adds or replaces the teachers of a csv or xlsx spreadsheet with the columns id, firstName, nickName, lastName, contactNumber, mainSubject, maxPeriodsPerDay, maxPeriodsPerWeek and maxStudentsPerPeriod.
+// @Description A row replaces the teacher with its id, or without an id the teacher with the same first and last name. Main subjects are given by name.
+// @Tags teacher
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "spreadsheet, .csv or .xlsx"
+// @Param createMainSubjects query bool false "create the main subjects which do not exist yet"
+// @Param dryRun query bool false "only check the rows, nothing is written"
+// @Success 200 {object} dto.ImportSummary "import summary"
+// @Failure default {object} util.APIResponse "fail"
+// @Router /teacher/import [post]
+func (t *TeacherHandler) ImportTeachers(rw http.ResponseWriter, r *http.Request) {
+
+	sheet, dryRun, err := importParam(rw, r, "createMainSubjects")
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	createMainSubjects, err := boolParam(r, "createMainSubjects")
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	rows, err := dto.ToTeacherRows(sheet)
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	summary, err := t.teacherService.ImportTeachers(rows, createMainSubjects, dryRun)
+	if err != nil {
+		t.util.WrappedError(rw, err)
+		return
+	}
+
+	resp, err := json.Marshal(summary)
+	if err != nil {
+		t.util.WrappedError(rw, fmt.Errorf("unable to marshal json: %w", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(resp)
+}
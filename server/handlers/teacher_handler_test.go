@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/service"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
+)
+
+func TestTeacherHandler_GetAllTeacher(t *testing.T) {
+	l := util.NewLogger(true)
+	tests := []struct {
+		name           string
+		query          string
+		teacherService service.TeacherServiceInterface
+		expStatus      int
+	}{
+		{
+			name:  "getAllTeacherWithFilters",
+			query: "?mainSubjectId=music&q=som&sort=lastName&pageSize=10",
+			teacherService: TeacherServiceStub{
+				getAllTeacher: func(query *dto.TeacherQuery, page *dto.PageRequest) (*dto.Page, error) {
+					want := dto.TeacherQuery{MainSubjectID: "music", Q: "som", Sort: "lastName"}
+					if *query != want || page.PageSize != 10 {
+						t.Errorf("unexpected query %+v and page %+v", query, page)
+					}
+					return dto.NewPage([]*dto.Teacher{}, ""), nil
+				},
+			},
+			expStatus: http.StatusOK,
+		},
+		{
+			name:      "getAllTeacherFailedWithUnknownFilter",
+			query:     "?nickName=Pond",
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "getAllTeacherFailedWithUnknownSort",
+			query:     "?sort=contactNumber",
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "getAllTeacherFailedWithSubjectAndOtherFilters",
+			query:     "?subjectId=piano&mainSubjectId=music",
+			expStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewTeacherHandler(util.NewHandlerUtil(l), tt.teacherService)
+
+			req := httptest.NewRequest(http.MethodGet, "/teacher"+tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodGet).Path("/teacher").HandlerFunc(h.GetAllTeacher)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+func TestTeacherHandler_PatchTeacher(t *testing.T) {
+	l := util.NewLogger(true)
+	tests := []struct {
+		name           string
+		reqBody        string
+		teacherService service.TeacherServiceInterface
+		expStatus      int
+	}{
+		{
+			name:    "patchTeacherSuccessful",
+			reqBody: `{"nickName": "Pond"}`,
+			teacherService: TeacherServiceStub{
+				patchTeacher: func(id string, pt *dto.PatchTeacher) error {
+					if id != "t1" || pt.NickName == nil || *pt.NickName != "Pond" || pt.FirstName != nil {
+						t.Errorf("unexpected patch for %v: %+v", id, pt)
+					}
+					return nil
+				},
+			},
+			expStatus: http.StatusOK,
+		},
+		{
+			name:      "patchTeacherFailedWithEmptyField",
+			reqBody:   `{"firstName": ""}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "patchTeacherFailedWithInvalidCapacity",
+			reqBody:   `{"capacity": {"maxPeriodsPerDay": 5, "maxPeriodsPerWeek": 4, "maxStudentsPerPeriod": 8}}`,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "patchTeacherFailedWithDBNoSuchEntity",
+			reqBody: `{"nickName": "Pond"}`,
+			teacherService: TeacherServiceStub{
+				patchTeacher: func(id string, pt *dto.PatchTeacher) error {
+					return c.ErrDBNoSuchEntity
+				},
+			},
+			expStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewTeacherHandler(util.NewHandlerUtil(l), tt.teacherService)
+
+			req := httptest.NewRequest(http.MethodPatch, "/teacher/t1", strings.NewReader(tt.reqBody))
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodPatch).Path("/teacher/{id}").HandlerFunc(h.PatchTeacher)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+func TestTeacherHandler_DeleteTeacher(t *testing.T) {
+	l := util.NewLogger(true)
+	tests := []struct {
+		name           string
+		teacherService service.TeacherServiceInterface
+		expStatus      int
+	}{
+		{
+			name: "deleteTeacherSuccessful",
+			teacherService: TeacherServiceStub{
+				deleteTeacher: func(id string) error {
+					return nil
+				},
+			},
+			expStatus: http.StatusOK,
+		},
+		{
+			name: "deleteTeacherFailedWithDBNoSuchEntity",
+			teacherService: TeacherServiceStub{
+				deleteTeacher: func(id string) error {
+					return c.ErrDBNoSuchEntity
+				},
+			},
+			expStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewTeacherHandler(util.NewHandlerUtil(l), tt.teacherService)
+
+			req := httptest.NewRequest(http.MethodDelete, "/teacher/t1", nil)
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodDelete).Path("/teacher/{id}").HandlerFunc(h.DeleteTeacher)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Errorf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+		})
+	}
+}
+
+func TestTeacherHandler_ImportTeachers(t *testing.T) {
+	l := util.NewLogger(true)
+	csv := "First Name,Nick Name,Last Name,Contact Number,Main Subject,Max Periods Per Day,Max Periods Per Week,Max Students Per Period\n" +
+		"Somchai,Chai,Dee,0812345678,Music,4,18,8\n" +
+		"Suda,Da,Jai,0898765432,Art,four,18,8\n"
+	tests := []struct {
+		name                  string
+		query                 string
+		content               string
+		expStatus             int
+		expCreateMainSubjects bool
+	}{
+		{
+			name:      "importTeachersSuccessful",
+			content:   csv,
+			expStatus: http.StatusOK,
+		},
+		{
+			name:                  "importTeachersCreatingMainSubjects",
+			query:                 "?createMainSubjects=true&dryRun=1",
+			content:               csv,
+			expStatus:             http.StatusOK,
+			expCreateMainSubjects: true,
+		},
+		{
+			name:      "importTeachersFailedWithInvalidCreateMainSubjects",
+			query:     "?createMainSubjects=maybe",
+			content:   csv,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "importTeachersFailedWithMissingColumn",
+			content:   "firstName,nickName,lastName,contactNumber,mainSubject\nSomchai,Chai,Dee,0812345678,Music\n",
+			expStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRows []*dto.TeacherRow
+			var gotCreateMainSubjects bool
+			h := NewTeacherHandler(util.NewHandlerUtil(l), TeacherServiceStub{
+				importTeachers: func(rows []*dto.TeacherRow, createMainSubjects bool, dryRun bool) (*dto.ImportSummary, error) {
+					gotRows, gotCreateMainSubjects = rows, createMainSubjects
+					return dto.NewImportSummary(len(rows), dryRun), nil
+				},
+			})
+
+			req := newUploadRequest(t, "/teacher/import"+tt.query, "file", "teachers.csv", tt.content)
+			rr := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.Methods(http.MethodPost).Path("/teacher/import").HandlerFunc(h.ImportTeachers)
+			router.ServeHTTP(rr, req)
+			resp := rr.Result()
+
+			if tt.expStatus != resp.StatusCode {
+				t.Fatalf("unexpected status code: got %v want %v", resp.StatusCode, tt.expStatus)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			if gotCreateMainSubjects != tt.expCreateMainSubjects {
+				t.Errorf("unexpected createMainSubjects: got %v want %v", gotCreateMainSubjects, tt.expCreateMainSubjects)
+			}
+			if len(gotRows) != 2 {
+				t.Fatalf("expecting 2 rows, got %v", len(gotRows))
+			}
+			want := dto.TeacherCapacity{MaxPeriodsPerDay: 4, MaxPeriodsPerWeek: 18, MaxStudentsPerPeriod: 8}
+			if gotRows[0].Err != nil || gotRows[0].Teacher.MainSubjectID != "Music" || gotRows[0].Teacher.Capacity != want {
+				t.Errorf("unexpected first row: %+v %+v", gotRows[0], gotRows[0].Teacher)
+			}
+			if gotRows[1].Err == nil {
+				t.Errorf("expecting the unreadable capacity of the second row to be reported")
+			}
+		})
+	}
+}
+
+// TeacherServiceStub is a stub struct that proxies method calls to function fields.
+type TeacherServiceStub struct {
+	addTeacher func(nt *dto.NewTeacher) error
+
+	getAllTeacher func(query *dto.TeacherQuery, page *dto.PageRequest) (*dto.Page, error)
+
+	getTeacher func(id string) (*dto.Teacher, error)
+
+	updateTeacher func(id string, nt *dto.NewTeacher) error
+
+	patchTeacher func(id string, pt *dto.PatchTeacher) error
+
+	deleteTeacher func(id string) error
+
+	getTeacherAvailability func(id string) (*dto.TeacherAvailability, error)
+
+	updateTeacherAvailability func(id string, availability *dto.TeacherAvailability) error
+
+	getTeacherSubjects func(id string, page *dto.PageRequest) (*dto.Page, error)
+
+	addTeacherSubject func(id string, ts *dto.NewTeacherSubject) error
+
+	updateTeacherSubjects func(id string, ts *dto.TeacherSubjects) error
+
+	deleteTeacherSubject func(id string, subjectID string) error
+
+	getTeacherWorkload func(id string, scheduleID string) (*dto.TeacherWorkload, error)
+
+	importTeachers func(rows []*dto.TeacherRow, createMainSubjects bool, dryRun bool) (*dto.ImportSummary, error)
+}
+
+func (stub TeacherServiceStub) AddTeacher(nt *dto.NewTeacher) error {
+	return stub.addTeacher(nt)
+}
+
+func (stub TeacherServiceStub) GetAllTeacher(query *dto.TeacherQuery, page *dto.PageRequest) (*dto.Page, error) {
+	return stub.getAllTeacher(query, page)
+}
+
+func (stub TeacherServiceStub) GetTeacher(id string) (*dto.Teacher, error) {
+	return stub.getTeacher(id)
+}
+
+func (stub TeacherServiceStub) UpdateTeacher(id string, nt *dto.NewTeacher) error {
+	return stub.updateTeacher(id, nt)
+}
+
+func (stub TeacherServiceStub) PatchTeacher(id string, pt *dto.PatchTeacher) error {
+	return stub.patchTeacher(id, pt)
+}
+
+func (stub TeacherServiceStub) DeleteTeacher(id string) error {
+	return stub.deleteTeacher(id)
+}
+
+func (stub TeacherServiceStub) GetTeacherAvailability(id string) (*dto.TeacherAvailability, error) {
+	return stub.getTeacherAvailability(id)
+}
+
+func (stub TeacherServiceStub) UpdateTeacherAvailability(id string, availability *dto.TeacherAvailability) error {
+	return stub.updateTeacherAvailability(id, availability)
+}
+
+func (stub TeacherServiceStub) GetTeacherSubjects(id string, page *dto.PageRequest) (*dto.Page, error) {
+	return stub.getTeacherSubjects(id, page)
+}
+
+func (stub TeacherServiceStub) AddTeacherSubject(id string, ts *dto.NewTeacherSubject) error {
+	return stub.addTeacherSubject(id, ts)
+}
+
+func (stub TeacherServiceStub) UpdateTeacherSubjects(id string, ts *dto.TeacherSubjects) error {
+	return stub.updateTeacherSubjects(id, ts)
+}
+
+func (stub TeacherServiceStub) DeleteTeacherSubject(id string, subjectID string) error {
+	return stub.deleteTeacherSubject(id, subjectID)
+}
+
+func (stub TeacherServiceStub) GetTeacherWorkload(id string, scheduleID string) (*dto.TeacherWorkload, error) {
+	return stub.getTeacherWorkload(id, scheduleID)
+}
+
+func (stub TeacherServiceStub) ImportTeachers(rows []*dto.TeacherRow, createMainSubjects bool, dryRun bool) (*dto.ImportSummary, error) {
+	return stub.importTeachers(rows, createMainSubjects, dryRun)
+}
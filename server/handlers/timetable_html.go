@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+//go:embed templates/student_timetable.html
+var studentTimetableHTML string
+
+var studentTimetableTemplate = template.Must(template.New("student_timetable").Funcs(template.FuncMap{
+	"dayName": func(d model.Day) string {
+		if len(d) == 0 {
+			return ""
+		}
+		return strings.ToUpper(string(d[:1])) + string(d[1:])
+	},
+}).Parse(studentTimetableHTML))
+
+// timetableGrid lays a StudentTimetable out as a printable table with a column per day and a row per period
+type timetableGrid struct {
+	*dto.StudentTimetable
+	Days []model.Day
+	Rows []*timetableRow
+}
+
+// timetableRow is a period of the timetableGrid, Cells holds the lesson of each day or nil
+type timetableRow struct {
+	Period model.Period
+	Time   string
+	Cells  []*dto.TimetablePeriod
+}
+
+// newTimetableGrid always shows Monday to Friday, weekend days only when the student has lessons on them
+func newTimetableGrid(t *dto.StudentTimetable) *timetableGrid {
+	lessons := make(map[model.Day]map[model.Period]*dto.TimetablePeriod)
+	rowByPeriod := make(map[model.Period]*timetableRow)
+	for _, day := range t.Days {
+		lessons[day.Day] = make(map[model.Period]*dto.TimetablePeriod)
+		for _, p := range day.Periods {
+			lessons[day.Day][p.Period] = p
+			row, ok := rowByPeriod[p.Period]
+			if !ok {
+				row = &timetableRow{Period: p.Period}
+				rowByPeriod[p.Period] = row
+			}
+			if len(row.Time) == 0 && len(p.StartTime) > 0 {
+				row.Time = p.StartTime + " - " + p.EndTime
+			}
+		}
+	}
+
+	grid := &timetableGrid{StudentTimetable: t, Days: make([]model.Day, 0, len(model.Days)), Rows: make([]*timetableRow, 0, len(rowByPeriod))}
+	for _, day := range model.Days {
+		if _, ok := lessons[day]; ok || !(day == model.Saturday || day == model.Sunday) {
+			grid.Days = append(grid.Days, day)
+		}
+	}
+	for _, row := range rowByPeriod {
+		for _, day := range grid.Days {
+			row.Cells = append(row.Cells, lessons[day][row.Period])
+		}
+		grid.Rows = append(grid.Rows, row)
+	}
+	sort.Slice(grid.Rows, func(i, j int) bool { return grid.Rows[i].Period < grid.Rows[j].Period })
+	return grid
+}
+
+// renderStudentTimetable writes the timetable as a printable html page
+func renderStudentTimetable(w io.Writer, t *dto.StudentTimetable) error {
+	return studentTimetableTemplate.Execute(w, newTimetableGrid(t))
+}
@@ -2,9 +2,9 @@ package router
 
 import (
 	"github.com/gorilla/mux"
-	"github.com/schedule-school-teachning-bsd13-backend/server/handlers"
-	"github.com/schedule-school-teachning-bsd13-backend/server/middleware"
-	"github.com/schedule-school-teachning-bsd13-backend/util"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/handlers"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/middleware"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/util"
 	"net/http"
 )
 
@@ -16,6 +16,10 @@ func NewRouter(
 	sh *handlers.SubjectHandler,
 	ch *handlers.ConfirmationHandler,
 	sch *handlers.ScheduleHandler,
+	bsh *handlers.BellScheduleHandler,
+	calh *handlers.CalendarHandler,
+	rh *handlers.RoomHandler,
+	sth *handlers.StudentHandler,
 ) http.Handler {
 
 	r := mux.NewRouter()
@@ -26,6 +30,19 @@ func NewRouter(
 	str.Use(middleware.NewRequestLogger(logger).LogRequest)
 	str.Methods(http.MethodPost).Path("").HandlerFunc(th.AddTeacher)
 	str.Methods(http.MethodGet).Path("").HandlerFunc(th.GetAllTeacher)
+	str.Methods(http.MethodPost).Path("/import").HandlerFunc(th.ImportTeachers)
+	str.Methods(http.MethodGet).Path("/{id}").HandlerFunc(th.GetTeacher)
+	str.Methods(http.MethodPut).Path("/{id}").HandlerFunc(th.UpdateTeacher)
+	str.Methods(http.MethodPatch).Path("/{id}").HandlerFunc(th.PatchTeacher)
+	str.Methods(http.MethodDelete).Path("/{id}").HandlerFunc(th.DeleteTeacher)
+	str.Methods(http.MethodGet).Path("/{id}/availability").HandlerFunc(th.GetTeacherAvailability)
+	str.Methods(http.MethodPut).Path("/{id}/availability").HandlerFunc(th.UpdateTeacherAvailability)
+	str.Methods(http.MethodGet).Path("/{id}/subjects").HandlerFunc(th.GetTeacherSubjects)
+	str.Methods(http.MethodPost).Path("/{id}/subjects").HandlerFunc(th.AddTeacherSubject)
+	str.Methods(http.MethodPut).Path("/{id}/subjects").HandlerFunc(th.UpdateTeacherSubjects)
+	str.Methods(http.MethodDelete).Path("/{id}/subjects/{subjectId}").HandlerFunc(th.DeleteTeacherSubject)
+	str.Methods(http.MethodGet).Path("/{id}/workload").HandlerFunc(th.GetTeacherWorkload)
+	str.Methods(http.MethodGet).Path("/{id}/calendar.ics").HandlerFunc(sch.GetTeacherCalendar)
 
 
 	// subrouter for /mainsubject
@@ -34,6 +51,10 @@ func NewRouter(
 	msjr.Use(middleware.NewRequestLogger(logger).LogRequest)
 	msjr.Methods(http.MethodPost).Path("").HandlerFunc(msh.AddMainSubject)
 	msjr.Methods(http.MethodGet).Path("").HandlerFunc(msh.GetAllMainSubject)
+	msjr.Methods(http.MethodGet).Path("/{id}").HandlerFunc(msh.GetMainSubject)
+	msjr.Methods(http.MethodPut).Path("/{id}").HandlerFunc(msh.UpdateMainSubject)
+	msjr.Methods(http.MethodPatch).Path("/{id}").HandlerFunc(msh.PatchMainSubject)
+	msjr.Methods(http.MethodDelete).Path("/{id}").HandlerFunc(msh.DeleteMainSubject)
 
 
 	// subrouter for /mainsubject
@@ -42,6 +63,11 @@ func NewRouter(
 	sr.Use(middleware.NewRequestLogger(logger).LogRequest)
 	sr.Methods(http.MethodPost).Path("").HandlerFunc(sh.AddSubject)
 	sr.Methods(http.MethodGet).Path("").HandlerFunc(sh.GetAllSubject)
+	sr.Methods(http.MethodPost).Path("/import").HandlerFunc(sh.ImportSubjects)
+	sr.Methods(http.MethodGet).Path("/{id}").HandlerFunc(sh.GetSubject)
+	sr.Methods(http.MethodPut).Path("/{id}").HandlerFunc(sh.UpdateSubject)
+	sr.Methods(http.MethodPatch).Path("/{id}").HandlerFunc(sh.PatchSubject)
+	sr.Methods(http.MethodDelete).Path("/{id}").HandlerFunc(sh.DeleteSubject)
 
 
 	// subrouter for /mainsubject
@@ -53,9 +79,74 @@ func NewRouter(
 
 	cr.Methods(http.MethodPost).Path("/{id}").HandlerFunc(ch.AddConfirmationDetail)
 	cr.Methods(http.MethodGet).Path("/{id}").HandlerFunc(ch.GetAllConfirmationDetail)
+	cr.Methods(http.MethodGet).Path("/{id}/conflicts").HandlerFunc(ch.GetConfirmationConflicts)
+	cr.Methods(http.MethodPost).Path("/{id}/import").HandlerFunc(ch.ImportConfirmationDetails)
+	cr.Methods(http.MethodPatch).Path("/{id}/status").HandlerFunc(ch.UpdateConfirmationStatus)
 
 	scr := r.PathPrefix("/create-schedule").Subrouter()
+	scr.Use(middleware.ContentTypeJSON)
+	scr.Use(middleware.NewRequestLogger(logger).LogRequest)
 	scr.Methods(http.MethodPost).Path("").HandlerFunc(sch.ScheduleClass)
 
+	// subrouter for /schedule
+	sdr := r.PathPrefix("/schedule").Subrouter()
+	sdr.Use(middleware.ContentTypeJSON)
+	sdr.Use(middleware.NewRequestLogger(logger).LogRequest)
+	sdr.Methods(http.MethodGet).Path("/{id}").HandlerFunc(sch.GetSchedule)
+	sdr.Methods(http.MethodGet).Path("/{id}/teacher/{teacherId}").HandlerFunc(sch.GetTeacherSchedule)
+	sdr.Methods(http.MethodGet).Path("/{id}/student/{studentId}").HandlerFunc(sch.GetStudentSchedule)
+	sdr.Methods(http.MethodGet).Path("/{id}/sessions").HandlerFunc(sch.GetScheduleSessions)
+
+	// subrouter for /bell-schedule
+	bsr := r.PathPrefix("/bell-schedule").Subrouter()
+	bsr.Use(middleware.ContentTypeJSON)
+	bsr.Use(middleware.NewRequestLogger(logger).LogRequest)
+	bsr.Methods(http.MethodPost).Path("").HandlerFunc(bsh.AddBellSchedule)
+	bsr.Methods(http.MethodGet).Path("").HandlerFunc(bsh.GetAllBellSchedule)
+	bsr.Methods(http.MethodGet).Path("/{id}").HandlerFunc(bsh.GetBellSchedule)
+	bsr.Methods(http.MethodPut).Path("/{id}").HandlerFunc(bsh.UpdateBellSchedule)
+	bsr.Methods(http.MethodDelete).Path("/{id}").HandlerFunc(bsh.DeleteBellSchedule)
+
+	// subrouter for /term
+	tr := r.PathPrefix("/term").Subrouter()
+	tr.Use(middleware.ContentTypeJSON)
+	tr.Use(middleware.NewRequestLogger(logger).LogRequest)
+	tr.Methods(http.MethodPost).Path("").HandlerFunc(calh.AddTerm)
+	tr.Methods(http.MethodGet).Path("").HandlerFunc(calh.GetAllTerm)
+	tr.Methods(http.MethodGet).Path("/{id}").HandlerFunc(calh.GetTerm)
+	tr.Methods(http.MethodPut).Path("/{id}").HandlerFunc(calh.UpdateTerm)
+	tr.Methods(http.MethodDelete).Path("/{id}").HandlerFunc(calh.DeleteTerm)
+
+	// subrouter for /holiday
+	hr := r.PathPrefix("/holiday").Subrouter()
+	hr.Use(middleware.ContentTypeJSON)
+	hr.Use(middleware.NewRequestLogger(logger).LogRequest)
+	hr.Methods(http.MethodPost).Path("").HandlerFunc(calh.AddHoliday)
+	hr.Methods(http.MethodGet).Path("").HandlerFunc(calh.GetAllHoliday)
+	hr.Methods(http.MethodGet).Path("/{id}").HandlerFunc(calh.GetHoliday)
+	hr.Methods(http.MethodPut).Path("/{id}").HandlerFunc(calh.UpdateHoliday)
+	hr.Methods(http.MethodDelete).Path("/{id}").HandlerFunc(calh.DeleteHoliday)
+
+	// subrouter for /room
+	rr := r.PathPrefix("/room").Subrouter()
+	rr.Use(middleware.ContentTypeJSON)
+	rr.Use(middleware.NewRequestLogger(logger).LogRequest)
+	rr.Methods(http.MethodPost).Path("").HandlerFunc(rh.AddRoom)
+	rr.Methods(http.MethodGet).Path("").HandlerFunc(rh.GetAllRoom)
+	rr.Methods(http.MethodGet).Path("/{id}").HandlerFunc(rh.GetRoom)
+	rr.Methods(http.MethodPut).Path("/{id}").HandlerFunc(rh.UpdateRoom)
+	rr.Methods(http.MethodDelete).Path("/{id}").HandlerFunc(rh.DeleteRoom)
+
+	// subrouter for /student
+	stdr := r.PathPrefix("/student").Subrouter()
+	stdr.Use(middleware.ContentTypeJSON)
+	stdr.Use(middleware.NewRequestLogger(logger).LogRequest)
+	stdr.Methods(http.MethodPost).Path("").HandlerFunc(sth.AddStudent)
+	stdr.Methods(http.MethodGet).Path("").HandlerFunc(sth.GetAllStudent)
+	stdr.Methods(http.MethodGet).Path("/{id}").HandlerFunc(sth.GetStudent)
+	stdr.Methods(http.MethodGet).Path("/{id}/timetable").HandlerFunc(sth.GetStudentTimetable)
+	stdr.Methods(http.MethodPut).Path("/{id}").HandlerFunc(sth.UpdateStudent)
+	stdr.Methods(http.MethodDelete).Path("/{id}").HandlerFunc(sth.DeleteStudent)
+
 	return middleware.RemoveTrailingSlash(r)
 }
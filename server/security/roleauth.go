@@ -51,6 +51,19 @@ type authUserInfo struct {
 	Email  string `json:"email"`
 }
 
+// RequestUser returns the email of the authenticated user of the request, or the user id when the token
+// carries no email. It is empty for requests which did not pass through Google Cloud Endpoint ESP.
+func RequestUser(r *http.Request) string {
+	userInfo, err := getUserInfo(r)
+	if err != nil {
+		return ""
+	}
+	if len(userInfo.Email) > 0 {
+		return userInfo.Email
+	}
+	return userInfo.ID
+}
+
 // getUserInfo extract authenticated user information written to request header by Google Cloud Endpoint ESP
 func getUserInfo(r *http.Request) (*authUserInfo, error) {
 	encodedInfo := r.Header.Get("X-Endpoint-API-UserInfo")
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+type BellScheduleService struct {
+	db db.BellScheduleDB
+}
+
+func NewBellScheduleService(db db.BellScheduleDB) *BellScheduleService {
+	return &BellScheduleService{db: db}
+}
+
+func (s *BellScheduleService) AddBellSchedule(nb *dto.NewBellSchedule) error {
+	return s.db.AddBellSchedule(nb.ToModel(uuid.New().String()))
+}
+
+func (s *BellScheduleService) GetAllBellSchedule(page *dto.PageRequest) (*dto.Page, error) {
+
+	list, next, err := s.db.GetAllBellSchedule(toDBPage(page))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewPage(dto.ToBellScheduleDTO(list), next), nil
+}
+
+func (s *BellScheduleService) GetBellSchedule(id string) (*dto.BellSchedule, error) {
+
+	b, err := s.db.GetBellSchedule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToBellSchedule(b), nil
+}
+
+func (s *BellScheduleService) UpdateBellSchedule(id string, nb *dto.NewBellSchedule) error {
+	return s.db.UpdateBellSchedule(nb.ToModel(id))
+}
+
+func (s *BellScheduleService) DeleteBellSchedule(id string) error {
+	return s.db.DeleteBellSchedule(id)
+}
+
+// resolveBellSchedule gets the bell schedule periods refer to, the default one when id is empty.
+// It returns nil when the school has not configured one, periods are then not resolved to times.
+func resolveBellSchedule(bellDB db.BellScheduleDB, id string) (*model.BellSchedule, error) {
+	var b *model.BellSchedule
+	var err error
+	if len(id) > 0 {
+		b, err = bellDB.GetBellSchedule(id)
+	} else {
+		b, err = bellDB.GetDefaultBellSchedule()
+	}
+	if errors.Is(err, c.ErrDBNoSuchEntity) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// checkPeriods returns a validation error when a period is not a teaching period of the bell schedule,
+// any period is accepted when bell is nil
+func checkPeriods(bell *model.BellSchedule, periods ...model.Period) error {
+	if bell == nil {
+		return nil
+	}
+	for _, p := range periods {
+		if _, ok := bell.TeachingPeriod(p); !ok {
+			return &c.ErrValidation{Violations: fmt.Errorf("period %d is not a teaching period of bell schedule %v", p, bell.Name)}
+		}
+	}
+	return nil
+}
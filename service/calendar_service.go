@@ -0,0 +1,93 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+// CalendarService manages the academic terms and the holidays lessons do not take place on
+type CalendarService struct {
+	termDB    db.TermDB
+	holidayDB db.HolidayDB
+}
+
+func NewCalendarService(termDB db.TermDB, holidayDB db.HolidayDB) *CalendarService {
+	return &CalendarService{termDB: termDB, holidayDB: holidayDB}
+}
+
+func (s *CalendarService) AddTerm(nt *dto.NewTerm) error {
+	return s.termDB.AddTerm(nt.ToModel(uuid.New().String()))
+}
+
+func (s *CalendarService) GetAllTerm(page *dto.PageRequest) (*dto.Page, error) {
+
+	list, next, err := s.termDB.GetAllTerm(toDBPage(page))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewPage(dto.ToTermDTO(list), next), nil
+}
+
+func (s *CalendarService) GetTerm(id string) (*dto.Term, error) {
+
+	t, err := s.termDB.GetTerm(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToTerm(t), nil
+}
+
+func (s *CalendarService) UpdateTerm(id string, nt *dto.NewTerm) error {
+	return s.termDB.UpdateTerm(nt.ToModel(id))
+}
+
+func (s *CalendarService) DeleteTerm(id string) error {
+	return s.termDB.DeleteTerm(id)
+}
+
+func (s *CalendarService) AddHoliday(nh *dto.NewHoliday) error {
+	return s.holidayDB.AddHoliday(nh.ToModel(uuid.New().String()))
+}
+
+func (s *CalendarService) GetAllHoliday(page *dto.PageRequest) (*dto.Page, error) {
+
+	list, next, err := s.holidayDB.GetAllHoliday(toDBPage(page))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewPage(dto.ToHolidayDTO(list), next), nil
+}
+
+func (s *CalendarService) GetHoliday(id string) (*dto.Holiday, error) {
+
+	h, err := s.holidayDB.GetHoliday(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToHoliday(h), nil
+}
+
+func (s *CalendarService) UpdateHoliday(id string, nh *dto.NewHoliday) error {
+	return s.holidayDB.UpdateHoliday(nh.ToModel(id))
+}
+
+func (s *CalendarService) DeleteHoliday(id string) error {
+	return s.holidayDB.DeleteHoliday(id)
+}
+
+// fetchAllHolidays reads the whole holiday calendar
+func fetchAllHolidays(holidayDB db.HolidayDB) ([]*model.Holiday, error) {
+	holidays := make([]*model.Holiday, 0)
+	err := fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := holidayDB.GetAllHoliday(page)
+		holidays = append(holidays, list...)
+		return next, err
+	})
+	return holidays, err
+}
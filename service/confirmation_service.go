@@ -1,17 +1,27 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/google/uuid"
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
 )
 
 type ConfirmationService struct {
-	db  db.ConfirmationDB
+	db            db.ConfirmationDB
+	subjectDB     db.SubjectDB
+	bellDB        db.BellScheduleDB
+	studentDB     db.StudentDB
+	mainSubjectDB db.MainSubjectDB
 }
 
-func NewConfirmationService(db db.ConfirmationDB) *ConfirmationService {
-	return &ConfirmationService{db: db}
+func NewConfirmationService(db db.ConfirmationDB, subjectDB db.SubjectDB, bellDB db.BellScheduleDB, studentDB db.StudentDB, mainSubjectDB db.MainSubjectDB) *ConfirmationService {
+	return &ConfirmationService{db: db, subjectDB: subjectDB, bellDB: bellDB, studentDB: studentDB, mainSubjectDB: mainSubjectDB}
 }
 
 
@@ -25,37 +35,316 @@ func (m *ConfirmationService) AddConfirmation(confirmRequest *dto.NewConfirmatio
 
 
 
-func (m *ConfirmationService) GetAllConfirmation() ([]*dto.Confirmation, error) {
+func (m *ConfirmationService) GetAllConfirmation(page *dto.PageRequest) (*dto.Page, error) {
 
-	list, err := m.db.GetAllConfirmation()
+	list, next, err := m.db.GetAllConfirmation(toDBPage(page))
 	if err != nil {
 		return nil, err
 	}
 
 	response := dto.ToConfirmationDTO(list)
 
-	return response, nil
+	return dto.NewPage(response, next), nil
+}
+
+// UpdateConfirmationStatus moves the confirmation to the requested stage of its lifecycle, changedBy is the
+// user making the change
+func (m *ConfirmationService) UpdateConfirmationStatus(id string, request *dto.UpdateConfirmationStatusRequest, changedBy string) (*dto.Confirmation, error) {
+
+	confirmation, err := m.db.UpdateConfirmationStatus(id, request.Status, changedBy, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToConfirmation(confirmation), nil
 }
 
+// AddConfirmationDetail adds a detail to an existing confirmation, its period has to be a teaching period
+// of the confirmation's bell schedule. The student name, and the level unless given, are copied from the
+// student the detail refers to. The subjects, and that the student has no other detail on the day and period,
+// are checked in the transaction storing the detail.
 func (m *ConfirmationService) AddConfirmationDetail(confirmRequest *dto.NewConfirmationDetail) error {
 
+	confirmation, err := m.db.GetConfirmation(confirmRequest.ConfirmationID)
+	if errors.Is(err, c.ErrDBNoSuchEntity) {
+		return &c.ErrValidation{Violations: fmt.Errorf("confirmation %v does not exist", confirmRequest.ConfirmationID)}
+	}
+	if err != nil {
+		return err
+	}
+	if err := confirmation.CheckAcceptsDetails(); err != nil {
+		return err
+	}
+
+	bell, err := resolveBellSchedule(m.bellDB, confirmation.BellScheduleID)
+	if err != nil {
+		return err
+	}
+	if err := checkPeriods(bell, confirmRequest.Period); err != nil {
+		return err
+	}
+
+	if err := m.fillStudent(confirmRequest, nil); err != nil {
+		return err
+	}
+
 	id := uuid.New()
 	confirmationDetail :=  confirmRequest.ToModel(id.String())
 
 	return m.db.AddConfirmationDetail(confirmationDetail)
 }
 
+// fillStudent copies the name, and the level unless given, of the student the detail refers to. The
+// students already looked up are kept in students when it is not nil.
+func (m *ConfirmationService) fillStudent(cd *dto.NewConfirmationDetail, students map[string]*model.Student) error {
+	if len(cd.StudentID) == 0 {
+		return nil
+	}
+
+	student, ok := students[cd.StudentID]
+	if !ok {
+		var err error
+		student, err = m.studentDB.GetStudent(cd.StudentID)
+		if errors.Is(err, c.ErrDBNoSuchEntity) {
+			return &c.ErrValidation{Violations: fmt.Errorf("student %v does not exist", cd.StudentID)}
+		}
+		if err != nil {
+			return err
+		}
+		if students != nil {
+			students[cd.StudentID] = student
+		}
+	}
+
+	cd.StudentName = student.Name
+	if len(cd.Level) == 0 {
+		cd.Level = student.Level
+	}
+	return nil
+}
+
+// ImportConfirmationDetails adds the details read from a spreadsheet to an existing confirmation. Every row
+// is checked the way AddConfirmationDetail checks a detail, including its subjects and a second detail of the
+// student on the same day and period, whether stored or in an earlier row. The rows which pass are written
+// together and the others are reported in the result. The store checks the confirmation and the slots again
+// while writing, so a detail added or a status changed meanwhile fails the import. Nothing is written when
+// dryRun is set.
+func (m *ConfirmationService) ImportConfirmationDetails(id string, rows []*dto.ConfirmationDetailRow, dryRun bool) (*dto.ImportResult, error) {
+
+	confirmation, err := m.db.GetConfirmation(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := confirmation.CheckAcceptsDetails(); err != nil {
+		return nil, err
+	}
+
+	bell, err := resolveBellSchedule(m.bellDB, confirmation.BellScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := m.takenDetailSlots(id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.NewImportResult(len(rows), dryRun)
+	students := make(map[string]*model.Student)
+	subjects := make(map[string]bool)
+	details := make([]*model.ConfirmationDetail, 0, len(rows))
+	for _, row := range rows {
+		if row.Err != nil {
+			res.Reject(row.Line, row.Err)
+			continue
+		}
+		if err := row.Detail.Validate(); err != nil {
+			res.Reject(row.Line, err)
+			continue
+		}
+		if err := checkPeriods(bell, row.Detail.Period); err != nil {
+			res.Reject(row.Line, err)
+			continue
+		}
+
+		err := m.fillStudent(row.Detail, students)
+		var errValidation *c.ErrValidation
+		if errors.As(err, &errValidation) {
+			res.Reject(row.Line, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		detail := row.Detail.ToModel(uuid.New().String())
+		err = m.checkSubjectsExist(detail.SubjectDetailID, subjects)
+		if errors.As(err, &errValidation) {
+			res.Reject(row.Line, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		slot := detailSlot(detail)
+		if other, ok := taken[slot]; ok {
+			res.Reject(row.Line, &c.ErrValidation{Violations: fmt.Errorf("student %v already has detail %v on %v period %d of confirmation %v",
+				detail.StudentKey(), other, detail.Day, detail.Period, id)})
+			continue
+		}
+		taken[slot] = detail.ID
+
+		details = append(details, detail)
+	}
+
+	res.Imported = len(details)
+	if dryRun {
+		return res, nil
+	}
+	if err := m.db.AddConfirmationDetails(details); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 
 
-func (m *ConfirmationService) GetAllConfirmationDetail(id string) ([]*dto.ConfirmationDetail, error) {
+func (m *ConfirmationService) GetAllConfirmationDetail(id string, page *dto.PageRequest) (*dto.Page, error) {
 
-	list, err := m.db.GetAllConfirmationDetail(id)
+	list, next, err := m.db.GetAllConfirmationDetail(id, toDBPage(page))
 	if err != nil {
 		return nil, err
 	}
 
-	response := dto.ToConfirmationDetailDTO(list)
+	// details of an unknown confirmation are listed against the default bell schedule
+	bellScheduleID := ""
+	confirmation, err := m.db.GetConfirmation(id)
+	switch {
+	case err == nil:
+		bellScheduleID = confirmation.BellScheduleID
+	case !errors.Is(err, c.ErrDBNoSuchEntity):
+		return nil, err
+	}
+	bell, err := resolveBellSchedule(m.bellDB, bellScheduleID)
+	if err != nil {
+		return nil, err
+	}
 
-	return response, nil
+	response := dto.ToConfirmationDetailDTO(list, bell)
+
+	return dto.NewPage(response, next), nil
 }
 
+// GetConfirmationConflicts analyses every detail of the confirmation and reports the problems to fix before scheduling
+func (m *ConfirmationService) GetConfirmationConflicts(id string) (*dto.ConfirmationConflicts, error) {
+
+	if _, err := m.db.GetConfirmation(id); err != nil {
+		return nil, err
+	}
+
+	details := make([]*model.ConfirmationDetail, 0)
+	err := fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := m.db.GetAllConfirmationDetail(id, page)
+		details = append(details, list...)
+		return next, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := make([]*model.Subject, 0)
+	err = fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := m.subjectDB.GetAllSubject(db.SubjectQuery{}, page)
+		subjects = append(subjects, list...)
+		return next, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return detectConflicts(id, details, subjects), nil
+}
+
+// ExportConfirmationDetails returns the details of the confirmation as a row for every subject a student
+// takes. The teacher column is empty, teachers are only assigned when a schedule is generated. The details
+// are read a page at a time while the rows are written.
+func (m *ConfirmationService) ExportConfirmationDetails(id string) (*dto.Export, error) {
+
+	if _, err := m.db.GetConfirmation(id); err != nil {
+		return nil, err
+	}
+
+	names := newExportNames(m.subjectDB, m.mainSubjectDB, nil)
+	rows := func(write func(row []string) error) error {
+		return fetchAllPages(func(page db.Page) (string, error) {
+			list, next, err := m.db.GetAllConfirmationDetail(id, page)
+			if err != nil {
+				return "", err
+			}
+			for _, cd := range list {
+				for _, subjectID := range cd.SubjectDetailID {
+					subject, mainSubject, err := names.subject(subjectID)
+					if err != nil {
+						return "", err
+					}
+					if err := write(exportRow(cd.StudentName, subject, mainSubject, "", cd.Day, cd.Period)); err != nil {
+						return "", err
+					}
+				}
+			}
+			return next, nil
+		})
+	}
+
+	return &dto.Export{Name: "confirmation-" + id, Columns: dto.ExportColumns, Rows: rows, Paged: true}, nil
+}
+
+// checkSubjectsExist fails with c.ErrValidation listing the subjects which do not exist, the subjects
+// already looked up are kept in known
+func (m *ConfirmationService) checkSubjectsExist(ids []string, known map[string]bool) error {
+	missing := make([]string, 0)
+	for _, id := range ids {
+		exists, ok := known[id]
+		if !ok {
+			_, err := m.subjectDB.GetSubject(id)
+			if err != nil && !errors.Is(err, c.ErrDBNoSuchEntity) {
+				return err
+			}
+			exists = err == nil
+			known[id] = exists
+		}
+		if !exists {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return &c.ErrValidation{Violations: fmt.Errorf("subjects %v do not exist", missing)}
+	}
+	return nil
+}
+
+// takenDetailSlots maps the student, day and period of every detail of the confirmation, see detailSlot,
+// to the id of the detail
+func (m *ConfirmationService) takenDetailSlots(id string) (map[string]string, error) {
+	taken := make(map[string]string)
+	err := fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := m.db.GetAllConfirmationDetail(id, page)
+		if err != nil {
+			return "", err
+		}
+		for _, cd := range list {
+			taken[detailSlot(cd)] = cd.ID
+		}
+		return next, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return taken, nil
+}
+
+// detailSlot identifies the student, day and period of a detail, a confirmation has one detail for each
+func detailSlot(cd *model.ConfirmationDetail) string {
+	return fmt.Sprintf("%v/%v/%d", cd.StudentKey(), cd.Day, cd.Period)
+}
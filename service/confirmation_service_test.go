@@ -0,0 +1,233 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+func TestImportConfirmationDetails(t *testing.T) {
+	bell := model.NewBellSchedule("b1", "Regular day", true, []model.BellPeriod{
+		{Period: 1, Name: "Period 1", Start: "08:30", End: "09:20"},
+		{Period: 0, Name: "Break", Start: "09:20", End: "09:40"},
+		{Period: 2, Name: "Period 2", Start: "09:40", End: "10:30"},
+	})
+	detail := func(studentID string, studentName string, level string, day model.Day, period model.Period) *dto.NewConfirmationDetail {
+		return &dto.NewConfirmationDetail{
+			ConfirmationID:  "c1",
+			SubjectDetailID: []string{"piano"},
+			StudentID:       studentID,
+			StudentName:     studentName,
+			Level:           level,
+			Day:             day,
+			Period:          period,
+		}
+	}
+	rows := []*dto.ConfirmationDetailRow{
+		{Line: 2, Detail: detail("s1", "", "", model.Monday, 1)},
+		{Line: 3, Detail: detail("", "bob", "P.5", model.Tuesday, 2)},
+		{Line: 4, Detail: detail("", "carol", "P.5", model.Monday, 3)},
+		{Line: 5, Detail: detail("s9", "", "", model.Monday, 1)},
+		{Line: 6, Detail: detail("", "dave", "P.5", "someday", 1)},
+		{Line: 7, Detail: detail("s1", "", "", model.Friday, 2)},
+		{Line: 8, Detail: detail("", "erin", "P.5", model.Friday, 0), Err: assert.AnError},
+		{Line: 9, Detail: detail("", "bob", "P.5", model.Tuesday, 2)},
+		{Line: 10, Detail: detail("", "frank", "P.5", model.Monday, 1)},
+		{Line: 11, Detail: detail("", "gina", "P.5", model.Monday, 1)},
+	}
+	rows[9].Detail.SubjectDetailID = []string{"piano", "cello"}
+
+	for _, dryRun := range []bool{false, true} {
+		written := make([]*model.ConfirmationDetail, 0)
+		studentLookups := 0
+		subjectLookups := 0
+		s := NewConfirmationService(
+			ConfirmationDBStub{
+				getConfirmation: func(id string) (*model.Confirmation, error) {
+					return model.NewConfirmation(id, "term 1", "2022-05-01", "", "t1"), nil
+				},
+				addConfirmationDetails: func(list []*model.ConfirmationDetail) error {
+					written = append(written, list...)
+					return nil
+				},
+				getAllConfirmationDetail: func(confirmationId string, page db.Page) ([]*model.ConfirmationDetail, string, error) {
+					return []*model.ConfirmationDetail{
+						model.NewConfirmationDetail("d1", confirmationId, []string{"piano"}, "", "frank", "P.5", 1, model.Monday),
+					}, "", nil
+				},
+			},
+			SubjectDBStub{
+				getSubject: func(id string) (*model.Subject, error) {
+					subjectLookups++
+					if id == "piano" {
+						return model.NewSubject(id, "Piano", "m1", 1, nil), nil
+					}
+					return nil, c.ErrDBNoSuchEntity
+				},
+			},
+			BellScheduleDBStub{
+				getDefaultBellSchedule: func() (*model.BellSchedule, error) { return bell, nil },
+			},
+			StudentDBStub{
+				getStudent: func(id string) (*model.Student, error) {
+					studentLookups++
+					if id == "s1" {
+						return model.NewStudent("s1", "alice", "P.4", "", "", "", ""), nil
+					}
+					return nil, c.ErrDBNoSuchEntity
+				},
+			},
+			nil,
+		)
+
+		res, err := s.ImportConfirmationDetails("c1", rows, dryRun)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, dryRun, res.DryRun)
+		assert.Equal(t, 10, res.Rows)
+		assert.Equal(t, 3, res.Imported)
+		lines := make([]int, 0)
+		for _, r := range res.Rejected {
+			lines = append(lines, r.Line)
+		}
+		assert.Equal(t, []int{4, 5, 6, 8, 9, 10, 11}, lines)
+		assert.Equal(t, "period 3 is not a teaching period of bell schedule Regular day", res.Rejected[0].Error)
+		assert.Equal(t, "student s9 does not exist", res.Rejected[1].Error)
+		assert.Regexp(t, "^student bob already has detail .+ on tuesday period 2 of confirmation c1$", res.Rejected[4].Error)
+		assert.Equal(t, "student frank already has detail d1 on monday period 1 of confirmation c1", res.Rejected[5].Error)
+		assert.Equal(t, "subjects [cello] do not exist", res.Rejected[6].Error)
+		assert.Equal(t, 2, studentLookups, "expecting each student to be looked up once")
+		assert.Equal(t, 2, subjectLookups, "expecting each subject to be looked up once")
+
+		if dryRun {
+			assert.Empty(t, written)
+			continue
+		}
+		if assert.Len(t, written, 3) {
+			assert.Equal(t, "alice", written[0].StudentName)
+			assert.Equal(t, "P.4", written[0].Level)
+			assert.Equal(t, "bob", written[1].StudentName)
+			assert.NotEqual(t, written[0].ID, written[2].ID)
+		}
+	}
+}
+
+func TestConfirmationDetailsOfLockedConfirmation(t *testing.T) {
+	for _, status := range []model.ConfirmationStatus{model.ConfirmationLocked, model.ConfirmationArchived} {
+		s := NewConfirmationService(
+			ConfirmationDBStub{
+				getConfirmation: func(id string) (*model.Confirmation, error) {
+					confirmation := model.NewConfirmation(id, "term 1", "2022-05-01", "", "t1")
+					confirmation.Status = status
+					return confirmation, nil
+				},
+			},
+			nil, nil, nil, nil,
+		)
+
+		err := s.AddConfirmationDetail(&dto.NewConfirmationDetail{
+			ConfirmationID:  "c1",
+			SubjectDetailID: []string{"piano"},
+			StudentName:     "alice",
+			Level:           "P.4",
+			Day:             model.Monday,
+			Period:          1,
+		})
+		assert.ErrorIs(t, err, c.ErrConflict)
+
+		_, err = s.ImportConfirmationDetails("c1", []*dto.ConfirmationDetailRow{}, true)
+		assert.ErrorIs(t, err, c.ErrConflict)
+	}
+}
+
+func TestUpdateConfirmationStatus(t *testing.T) {
+	var gotStatus model.ConfirmationStatus
+	var gotBy string
+	s := NewConfirmationService(
+		ConfirmationDBStub{
+			updateConfirmationStatus: func(id string, status model.ConfirmationStatus, changedBy string, changedAt time.Time) (*model.Confirmation, error) {
+				gotStatus, gotBy = status, changedBy
+				confirmation := model.NewConfirmation(id, "term 1", "2022-05-01", "", "t1")
+				confirmation.Status = status
+				confirmation.StatusChangedBy = changedBy
+				confirmation.StatusChangedAt = changedAt
+				return confirmation, nil
+			},
+		},
+		nil, nil, nil, nil,
+	)
+
+	res, err := s.UpdateConfirmationStatus("c1", &dto.UpdateConfirmationStatusRequest{Status: model.ConfirmationSubmitted}, "a@school.ac.th")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, model.ConfirmationSubmitted, gotStatus)
+	assert.Equal(t, "a@school.ac.th", gotBy)
+	assert.Equal(t, model.ConfirmationSubmitted, res.Status)
+	assert.Equal(t, "a@school.ac.th", res.StatusChangedBy)
+	if assert.NotNil(t, res.StatusChangedAt) {
+		assert.WithinDuration(t, time.Now(), *res.StatusChangedAt, time.Minute)
+	}
+}
+
+// ConfirmationDBStub is a stub struct that proxies method calls to function fields, the methods of
+// the embedded interface are not expected to be called.
+type ConfirmationDBStub struct {
+	db.ConfirmationDB
+
+	getConfirmation func(id string) (*model.Confirmation, error)
+
+	addConfirmationDetails func(list []*model.ConfirmationDetail) error
+
+	getAllConfirmationDetail func(confirmationId string, page db.Page) ([]*model.ConfirmationDetail, string, error)
+
+	updateConfirmationStatus func(id string, status model.ConfirmationStatus, changedBy string, changedAt time.Time) (*model.Confirmation, error)
+}
+
+func (stub ConfirmationDBStub) GetConfirmation(id string) (*model.Confirmation, error) {
+	return stub.getConfirmation(id)
+}
+
+func (stub ConfirmationDBStub) AddConfirmationDetails(list []*model.ConfirmationDetail) error {
+	return stub.addConfirmationDetails(list)
+}
+
+func (stub ConfirmationDBStub) GetAllConfirmationDetail(confirmationId string, page db.Page) ([]*model.ConfirmationDetail, string, error) {
+	return stub.getAllConfirmationDetail(confirmationId, page)
+}
+
+func (stub ConfirmationDBStub) UpdateConfirmationStatus(id string, status model.ConfirmationStatus, changedBy string, changedAt time.Time) (*model.Confirmation, error) {
+	return stub.updateConfirmationStatus(id, status, changedBy, changedAt)
+}
+
+// BellScheduleDBStub is a stub struct that proxies method calls to function fields, the methods of
+// the embedded interface are not expected to be called.
+type BellScheduleDBStub struct {
+	db.BellScheduleDB
+
+	getDefaultBellSchedule func() (*model.BellSchedule, error)
+}
+
+func (stub BellScheduleDBStub) GetDefaultBellSchedule() (*model.BellSchedule, error) {
+	return stub.getDefaultBellSchedule()
+}
+
+// StudentDBStub is a stub struct that proxies method calls to function fields, the methods of the
+// embedded interface are not expected to be called.
+type StudentDBStub struct {
+	db.StudentDB
+
+	getStudent func(id string) (*model.Student, error)
+}
+
+func (stub StudentDBStub) GetStudent(id string) (*model.Student, error) {
+	return stub.getStudent(id)
+}
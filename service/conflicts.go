@@ -0,0 +1,114 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+// studentSlot is a student in a single teaching period, see model.ConfirmationDetail.StudentKey
+type studentSlot struct {
+	Student string
+	timeSlot
+}
+
+// detectConflicts reports the problems in the confirmation details the scheduler would run into.
+//
+// It applies the rules of generateSchedule up front: a student can only attend one lesson per day
+// and period, a class needs at least Subject.MinOfStudent distinct students in its day and period,
+// and every requested subject has to exist. Lessons of unknown subjects are only reported as such.
+func detectConflicts(confirmationID string, details []*model.ConfirmationDetail, subjects []*model.Subject) *dto.ConfirmationConflicts {
+
+	subjectByID := make(map[string]*model.Subject, len(subjects))
+	for _, s := range subjects {
+		subjectByID[s.ID] = s
+	}
+
+	bookings := make(map[studentSlot][]*dto.ConflictLesson)
+	bookingOrder := make([]studentSlot, 0)
+	bookingDetail := make(map[studentSlot]*model.ConfirmationDetail)
+	classStudents := make(map[classKey]map[string]bool)
+	classDetails := make(map[classKey][]string)
+	classOrder := make([]classKey, 0)
+	unknown := make(map[string][]string)
+
+	for _, d := range sortConfirmationDetails(details) {
+		ts := timeSlot{Day: d.Day, Period: d.Period}
+		for _, subjectID := range d.SubjectDetailID {
+			if _, ok := subjectByID[subjectID]; !ok {
+				unknown[subjectID] = appendMissing(unknown[subjectID], d.ID)
+				continue
+			}
+
+			booking := studentSlot{Student: d.StudentKey(), timeSlot: ts}
+			if _, ok := bookings[booking]; !ok {
+				bookingOrder = append(bookingOrder, booking)
+				bookingDetail[booking] = d
+			}
+			bookings[booking] = append(bookings[booking], &dto.ConflictLesson{ConfirmationDetailID: d.ID, SubjectID: subjectID})
+
+			class := classKey{SubjectID: subjectID, timeSlot: ts}
+			if _, ok := classStudents[class]; !ok {
+				classStudents[class] = make(map[string]bool)
+				classOrder = append(classOrder, class)
+			}
+			classStudents[class][d.StudentKey()] = true
+			classDetails[class] = appendMissing(classDetails[class], d.ID)
+		}
+	}
+
+	res := &dto.ConfirmationConflicts{
+		ConfirmationID:      confirmationID,
+		DoubleBookings:      make([]*dto.DoubleBooking, 0),
+		UnderfilledSubjects: make([]*dto.UnderfilledSubject, 0),
+		UnknownSubjects:     make([]*dto.UnknownSubject, 0),
+	}
+
+	for _, booking := range bookingOrder {
+		if lessons := bookings[booking]; len(lessons) > 1 {
+			res.DoubleBookings = append(res.DoubleBookings, &dto.DoubleBooking{
+				StudentID:   bookingDetail[booking].StudentID,
+				StudentName: bookingDetail[booking].StudentName,
+				Day:         booking.Day,
+				Period:      booking.Period,
+				Lessons:     lessons,
+			})
+		}
+	}
+
+	for _, class := range classOrder {
+		subject := subjectByID[class.SubjectID]
+		if students := len(classStudents[class]); students < subject.MinOfStudent {
+			res.UnderfilledSubjects = append(res.UnderfilledSubjects, &dto.UnderfilledSubject{
+				SubjectID:             subject.ID,
+				SubjectName:           subject.SubjectName,
+				Day:                   class.Day,
+				Period:                class.Period,
+				Students:              students,
+				MinOfStudent:          subject.MinOfStudent,
+				ConfirmationDetailIDs: classDetails[class],
+			})
+		}
+	}
+
+	unknownIDs := make([]string, 0, len(unknown))
+	for id := range unknown {
+		unknownIDs = append(unknownIDs, id)
+	}
+	sort.Strings(unknownIDs)
+	for _, id := range unknownIDs {
+		res.UnknownSubjects = append(res.UnknownSubjects, &dto.UnknownSubject{SubjectID: id, ConfirmationDetailIDs: unknown[id]})
+	}
+
+	res.HasConflicts = len(res.DoubleBookings) > 0 || len(res.UnderfilledSubjects) > 0 || len(res.UnknownSubjects) > 0
+	return res
+}
+
+// appendMissing appends the value unless it is already the last element, values are added in order
+func appendMissing(list []string, value string) []string {
+	if len(list) > 0 && list[len(list)-1] == value {
+		return list
+	}
+	return append(list, value)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+func TestDetectConflicts(t *testing.T) {
+	subjects := []*model.Subject{
+		model.NewSubject("piano", "Piano", "music", 1, nil),
+		model.NewSubject("choir", "Choir", "music", 3, nil),
+	}
+
+	tests := []struct {
+		name    string
+		details []*model.ConfirmationDetail
+		want    *dto.ConfirmationConflicts
+	}{
+		{
+			name: "noConflicts",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"piano"}, "", "alice", "p1", 2, "monday"),
+			},
+			want: &dto.ConfirmationConflicts{
+				ConfirmationID:      "c1",
+				DoubleBookings:      []*dto.DoubleBooking{},
+				UnderfilledSubjects: []*dto.UnderfilledSubject{},
+				UnknownSubjects:     []*dto.UnknownSubject{},
+			},
+		},
+		{
+			name: "studentsSharingANameAreNotDoubleBooked",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "s1", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"piano"}, "s2", "alice", "p2", 1, "monday"),
+			},
+			want: &dto.ConfirmationConflicts{
+				ConfirmationID:      "c1",
+				DoubleBookings:      []*dto.DoubleBooking{},
+				UnderfilledSubjects: []*dto.UnderfilledSubject{},
+				UnknownSubjects:     []*dto.UnknownSubject{},
+			},
+		},
+		{
+			name: "studentBookedTwiceInOnePeriod",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d2", "c1", []string{"piano"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "", "alice", "p1", 1, "monday"),
+			},
+			want: &dto.ConfirmationConflicts{
+				ConfirmationID: "c1",
+				HasConflicts:   true,
+				DoubleBookings: []*dto.DoubleBooking{
+					{
+						StudentName: "alice",
+						Day:         "monday",
+						Period:      1,
+						Lessons: []*dto.ConflictLesson{
+							{ConfirmationDetailID: "d1", SubjectID: "piano"},
+							{ConfirmationDetailID: "d2", SubjectID: "piano"},
+						},
+					},
+				},
+				UnderfilledSubjects: []*dto.UnderfilledSubject{},
+				UnknownSubjects:     []*dto.UnknownSubject{},
+			},
+		},
+		{
+			name: "subjectBelowMinimumCountsDistinctStudents",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"choir"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"choir"}, "", "bob", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d3", "c1", []string{"choir"}, "", "bob", "p1", 1, "monday"),
+			},
+			want: &dto.ConfirmationConflicts{
+				ConfirmationID: "c1",
+				HasConflicts:   true,
+				DoubleBookings: []*dto.DoubleBooking{
+					{
+						StudentName: "bob",
+						Day:         "monday",
+						Period:      1,
+						Lessons: []*dto.ConflictLesson{
+							{ConfirmationDetailID: "d2", SubjectID: "choir"},
+							{ConfirmationDetailID: "d3", SubjectID: "choir"},
+						},
+					},
+				},
+				UnderfilledSubjects: []*dto.UnderfilledSubject{
+					{
+						SubjectID:             "choir",
+						SubjectName:           "Choir",
+						Day:                   "monday",
+						Period:                1,
+						Students:              2,
+						MinOfStudent:          3,
+						ConfirmationDetailIDs: []string{"d1", "d2", "d3"},
+					},
+				},
+				UnknownSubjects: []*dto.UnknownSubject{},
+			},
+		},
+		{
+			name: "unknownSubjects",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"piano", "history", "history"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"art"}, "", "bob", "p1", 2, "monday"),
+				model.NewConfirmationDetail("d3", "c1", []string{"history"}, "", "carol", "p1", 1, "tuesday"),
+			},
+			want: &dto.ConfirmationConflicts{
+				ConfirmationID:      "c1",
+				HasConflicts:        true,
+				DoubleBookings:      []*dto.DoubleBooking{},
+				UnderfilledSubjects: []*dto.UnderfilledSubject{},
+				UnknownSubjects: []*dto.UnknownSubject{
+					{SubjectID: "art", ConfirmationDetailIDs: []string{"d2"}},
+					{SubjectID: "history", ConfirmationDetailIDs: []string{"d1", "d3"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectConflicts("c1", tt.details, subjects))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// exportNames are the display names of the subjects, main subjects and teachers of an export by id, each is
+// read once. Entities deleted since the rows were stored have no name.
+type exportNames struct {
+	subjectDB     db.SubjectDB
+	mainSubjectDB db.MainSubjectDB
+	teacherDB     db.TeacherDB
+
+	subjects     map[string]*model.Subject
+	mainSubjects map[string]string
+	teachers     map[string]string
+}
+
+func newExportNames(subjectDB db.SubjectDB, mainSubjectDB db.MainSubjectDB, teacherDB db.TeacherDB) *exportNames {
+	return &exportNames{
+		subjectDB:     subjectDB,
+		mainSubjectDB: mainSubjectDB,
+		teacherDB:     teacherDB,
+		subjects:      make(map[string]*model.Subject),
+		mainSubjects:  make(map[string]string),
+		teachers:      make(map[string]string),
+	}
+}
+
+// subject returns the names of the subject and of its main subject
+func (n *exportNames) subject(id string) (string, string, error) {
+	subject, ok := n.subjects[id]
+	if !ok {
+		var err error
+		subject, err = n.subjectDB.GetSubject(id)
+		if err != nil && !errors.Is(err, c.ErrDBNoSuchEntity) {
+			return "", "", err
+		}
+		n.subjects[id] = subject
+	}
+	if subject == nil {
+		return "", "", nil
+	}
+
+	mainSubject, ok := n.mainSubjects[subject.MainSubjectId]
+	if !ok {
+		m, err := n.mainSubjectDB.GetMainSubject(subject.MainSubjectId)
+		if err != nil && !errors.Is(err, c.ErrDBNoSuchEntity) {
+			return "", "", err
+		}
+		if m != nil {
+			mainSubject = m.MainSubjectName
+		}
+		n.mainSubjects[subject.MainSubjectId] = mainSubject
+	}
+	return subject.SubjectName, mainSubject, nil
+}
+
+// teacher returns the first and last name of the teacher
+func (n *exportNames) teacher(id string) (string, error) {
+	if name, ok := n.teachers[id]; ok {
+		return name, nil
+	}
+
+	teacher, err := n.teacherDB.GetTeacher(id)
+	if err != nil && !errors.Is(err, c.ErrDBNoSuchEntity) {
+		return "", err
+	}
+	name := ""
+	if teacher != nil {
+		name = strings.TrimSpace(teacher.FirstName + " " + teacher.LastName)
+	}
+	n.teachers[id] = name
+	return name, nil
+}
+
+// exportSlot writes a row of dto.ExportColumns for the slot, which the student attends
+func exportSlot(write func(row []string) error, names *exportNames, student string, slot *model.ScheduleSlot) error {
+	subject, mainSubject, err := names.subject(slot.SubjectID)
+	if err != nil {
+		return err
+	}
+	teacher, err := names.teacher(slot.TeacherID)
+	if err != nil {
+		return err
+	}
+	return write(exportRow(student, subject, mainSubject, teacher, slot.Day, slot.Period))
+}
+
+// exportRow is a row of dto.ExportColumns
+func exportRow(student string, subject string, mainSubject string, teacher string, day model.Day, period model.Period) []string {
+	return []string{student, subject, mainSubject, teacher, string(day), strconv.Itoa(int(period))}
+}
+
+// exportsStudent tells whether the i-th student of a slot, whose ids are given, has to be exported. All
+// students are exported when studentID is empty.
+func exportsStudent(studentIDs []string, i int, studentID string) bool {
+	if len(studentID) == 0 {
+		return true
+	}
+	return i < len(studentIDs) && studentIDs[i] == studentID
+}
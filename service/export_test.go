@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+func TestExportConfirmationDetails(t *testing.T) {
+	pages := map[string][]*model.ConfirmationDetail{
+		"": {
+			model.NewConfirmationDetail("d1", "c1", []string{"piano", "violin"}, "s1", "alice", "P.4", 1, "monday"),
+		},
+		"next": {
+			model.NewConfirmationDetail("d2", "c1", []string{"piano", "deleted"}, "", "bob", "P.5", 2, "tuesday"),
+		},
+	}
+	subjectLookups := 0
+	s := NewConfirmationService(
+		ConfirmationDBStub{
+			getConfirmation: func(id string) (*model.Confirmation, error) {
+				if id != "c1" {
+					return nil, c.ErrDBNoSuchEntity
+				}
+				return model.NewConfirmation(id, "term 1", "2022-05-01", "", "t1"), nil
+			},
+			getAllConfirmationDetail: func(confirmationId string, page db.Page) ([]*model.ConfirmationDetail, string, error) {
+				if page.Token == "" {
+					return pages[""], "next", nil
+				}
+				return pages[page.Token], "", nil
+			},
+		},
+		SubjectDBStub{
+			getSubject: func(id string) (*model.Subject, error) {
+				subjectLookups++
+				switch id {
+				case "piano":
+					return model.NewSubject(id, "Piano", "m1", 1, nil), nil
+				case "violin":
+					return model.NewSubject(id, "Violin", "m2", 1, nil), nil
+				}
+				return nil, c.ErrDBNoSuchEntity
+			},
+		},
+		nil,
+		nil,
+		MainSubjectDBStub{
+			getMainSubject: func(id string) (*model.MainSubject, error) {
+				if id == "m1" {
+					return model.NewMainSubject(id, "Music"), nil
+				}
+				return nil, c.ErrDBNoSuchEntity
+			},
+		},
+	)
+
+	_, err := s.ExportConfirmationDetails("c2")
+	assert.ErrorIs(t, err, c.ErrDBNoSuchEntity)
+
+	export, err := s.ExportConfirmationDetails("c1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	rows := make([][]string, 0)
+	err = export.Rows(func(row []string) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "confirmation-c1", export.Name)
+	assert.Equal(t, [][]string{
+		{"alice", "Piano", "Music", "", "monday", "1"},
+		{"alice", "Violin", "", "", "monday", "1"},
+		{"bob", "Piano", "Music", "", "tuesday", "2"},
+		{"bob", "", "", "", "tuesday", "2"},
+	}, rows)
+	assert.Equal(t, 3, subjectLookups, "every subject is read once")
+}
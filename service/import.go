@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// mainSubjectsByName resolves the main subjects named in an imported spreadsheet, names are compared
+// ignoring case and surrounding spaces. Unknown main subjects are created when create is set, on a dry run
+// they are only given an id.
+type mainSubjectsByName struct {
+	db     db.MainSubjectDB
+	create bool
+	dryRun bool
+	// ids maps the names to the ids of the main subjects, it is empty for a name several main subjects have
+	ids map[string]string
+	// created are the names of the main subjects created
+	created []string
+}
+
+func newMainSubjectsByName(mainSubjectDB db.MainSubjectDB, create bool, dryRun bool) (*mainSubjectsByName, error) {
+	ms := &mainSubjectsByName{db: mainSubjectDB, create: create, dryRun: dryRun, ids: make(map[string]string), created: make([]string, 0)}
+	err := fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := mainSubjectDB.GetAllMainSubject(page)
+		for _, m := range list {
+			key := importKey(m.MainSubjectName)
+			if _, ok := ms.ids[key]; ok {
+				ms.ids[key] = ""
+				continue
+			}
+			ms.ids[key] = m.ID
+		}
+		return next, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// resolve returns the id of the main subject with the name
+func (ms *mainSubjectsByName) resolve(name string) (string, error) {
+	key := importKey(name)
+	if id, ok := ms.ids[key]; ok {
+		if len(id) == 0 {
+			return "", &c.ErrValidation{Violations: fmt.Errorf("there are several main subjects named %q, use a unique name", name)}
+		}
+		return id, nil
+	}
+	if !ms.create {
+		return "", &c.ErrValidation{Violations: fmt.Errorf("main subject %q does not exist", name)}
+	}
+
+	m := model.NewMainSubject(uuid.New().String(), strings.TrimSpace(name))
+	if !ms.dryRun {
+		if err := ms.db.AddMainSubject(m); err != nil {
+			return "", err
+		}
+	}
+	ms.ids[key] = m.ID
+	ms.created = append(ms.created, m.MainSubjectName)
+	return m.ID, nil
+}
+
+// importKey is how names are compared when matching the rows of an imported spreadsheet
+func importKey(names ...string) string {
+	for i, name := range names {
+		names[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	return strings.Join(names, "\x00")
+}
+
+// rowError reports whether err rejects the row being imported, any other error stops the import
+func rowError(err error) bool {
+	var errValidation *c.ErrValidation
+	return errors.As(err, &errValidation) || errors.Is(err, c.ErrDBNoSuchEntity) || errors.Is(err, c.ErrConflict) ||
+		errors.Is(err, c.ErrDBEntityAlreadyExists)
+}
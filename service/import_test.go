@@ -0,0 +1,250 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+func TestImportTeachers(t *testing.T) {
+	capacity := dto.TeacherCapacity{MaxPeriodsPerDay: 4, MaxPeriodsPerWeek: 18, MaxStudentsPerPeriod: 8}
+	row := func(line int, id string, firstName string, lastName string, mainSubject string) *dto.TeacherRow {
+		return &dto.TeacherRow{Line: line, ID: id, Teacher: &dto.NewTeacher{
+			FirstName:     firstName,
+			NickName:      firstName,
+			LastName:      lastName,
+			ContactNumber: "0812345678",
+			Capacity:      capacity,
+			MainSubjectID: mainSubject,
+		}}
+	}
+	availability := []model.AvailabilityWindow{{Day: model.Monday, StartPeriod: 1, EndPeriod: 4}}
+
+	for _, tt := range []struct {
+		name               string
+		createMainSubjects bool
+		dryRun             bool
+		wantCreated        int
+		wantRejected       []int
+		wantMainSubjects   []string
+	}{
+		{name: "unknownMainSubjectRejected", wantCreated: 1, wantRejected: []int{3, 5, 6, 7}, wantMainSubjects: []string{}},
+		{name: "unknownMainSubjectCreated", createMainSubjects: true, wantCreated: 2, wantRejected: []int{5, 6, 7}, wantMainSubjects: []string{"Art"}},
+		{name: "dryRun", createMainSubjects: true, dryRun: true, wantCreated: 2, wantRejected: []int{5, 6, 7}, wantMainSubjects: []string{"Art"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := []*dto.TeacherRow{
+				row(2, "", "Somchai", "Dee", " music "),
+				row(3, "", "Suda", "Jai", "Art"),
+				row(4, "", "SOMSRI", "rak", "Music"),
+				row(5, "t9", "Nok", "Fah", "Music"),
+				row(6, "", "Mana", "Sook", "Music"),
+				row(7, "", "Piti", "", "Music"),
+				row(8, "t1", "Wichai", "Dee", "Music"),
+			}
+
+			added := make([]*model.Teacher, 0)
+			updated := make([]*model.Teacher, 0)
+			mainSubjectsAdded := make([]*model.MainSubject, 0)
+			s := NewTeacherService(
+				TeacherDBStub{
+					getAllTeacher: func(tq db.TeacherQuery, page db.Page) ([]*model.Teacher, string, error) {
+						return []*model.Teacher{
+							model.NewTeacher("t1", "Wichai", "Chai", "Dee", "0", model.TeacherCapacity{}, "m1", availability, nil),
+							model.NewTeacher("t2", "Somsri", "Sri", "Rak", "0", model.TeacherCapacity{}, "m1", nil, nil),
+							model.NewTeacher("t3", "Mana", "Na", "Sook", "0", model.TeacherCapacity{}, "m1", nil, nil),
+							model.NewTeacher("t4", "Mana", "Ma", "Sook", "0", model.TeacherCapacity{}, "m1", nil, nil),
+						}, "", nil
+					},
+					addTeacher: func(t *model.Teacher) error {
+						added = append(added, t)
+						return nil
+					},
+					updateTeacher: func(t *model.Teacher) error {
+						updated = append(updated, t)
+						return nil
+					},
+				},
+				nil, nil, nil,
+				MainSubjectDBStub{
+					getAllMainSubject: func(page db.Page) ([]*model.MainSubject, string, error) {
+						return []*model.MainSubject{model.NewMainSubject("m1", "Music")}, "", nil
+					},
+					addMainSubject: func(m *model.MainSubject) error {
+						mainSubjectsAdded = append(mainSubjectsAdded, m)
+						return nil
+					},
+				},
+			)
+
+			res, err := s.ImportTeachers(rows, tt.createMainSubjects, tt.dryRun)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			lines := make([]int, 0)
+			for _, r := range res.Rejected {
+				lines = append(lines, r.Line)
+			}
+			assert.Equal(t, tt.wantRejected, lines)
+			assert.Equal(t, tt.wantCreated, res.Created)
+			assert.Equal(t, 2, res.Updated)
+			assert.Equal(t, tt.wantMainSubjects, res.MainSubjectsCreated)
+
+			if tt.dryRun {
+				assert.Empty(t, added)
+				assert.Empty(t, updated)
+				assert.Empty(t, mainSubjectsAdded)
+				return
+			}
+			assert.Len(t, added, tt.wantCreated)
+			assert.Len(t, mainSubjectsAdded, len(tt.wantMainSubjects))
+			assert.Equal(t, "m1", added[0].MainSubjectID)
+			if assert.Len(t, updated, 2) {
+				assert.Equal(t, "t2", updated[0].ID)
+				assert.Equal(t, "SOMSRI", updated[0].FirstName)
+				assert.Equal(t, "t1", updated[1].ID)
+				assert.Equal(t, availability, updated[1].Availability, "expecting the availability of a replaced teacher to be kept")
+			}
+		})
+	}
+}
+
+func TestImportSubjects(t *testing.T) {
+	rows := []*dto.SubjectRow{
+		{Line: 2, Subject: &dto.NewSubject{SubjectName: "Piano", MainSubjectId: "Music", MinOfStudent: 1}, KeepFeatures: true},
+		{Line: 3, Subject: &dto.NewSubject{SubjectName: "Violin", MainSubjectId: "Music", MinOfStudent: 1}, KeepFeatures: true},
+		{Line: 4, Subject: &dto.NewSubject{SubjectName: "Drawing", MainSubjectId: "Music"}, KeepFeatures: true},
+	}
+
+	added := make([]*model.Subject, 0)
+	updated := make([]*model.Subject, 0)
+	s := NewSubjectService(
+		SubjectDBStub{
+			getAllSubject: func(sq db.SubjectQuery, page db.Page) ([]*model.Subject, string, error) {
+				return []*model.Subject{model.NewSubject("s1", "piano", "m1", 2, []string{"piano"})}, "", nil
+			},
+			addSubject: func(m *model.Subject) error {
+				added = append(added, m)
+				return nil
+			},
+			updateSubject: func(m *model.Subject) error {
+				updated = append(updated, m)
+				return nil
+			},
+		},
+		nil,
+		MainSubjectDBStub{
+			getAllMainSubject: func(page db.Page) ([]*model.MainSubject, string, error) {
+				return []*model.MainSubject{model.NewMainSubject("m1", "Music")}, "", nil
+			},
+		},
+	)
+
+	res, err := s.ImportSubjects(rows, false, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 1, res.Created)
+	assert.Equal(t, 1, res.Updated)
+	if assert.Len(t, res.Rejected, 1) {
+		assert.Equal(t, 4, res.Rejected[0].Line)
+	}
+	if assert.Len(t, updated, 1) {
+		assert.Equal(t, "s1", updated[0].ID)
+		assert.Equal(t, []string{"piano"}, updated[0].RequiredFeatures, "expecting the features of a replaced subject to be kept")
+	}
+	if assert.Len(t, added, 1) {
+		assert.Equal(t, "Violin", added[0].SubjectName)
+		assert.Equal(t, "m1", added[0].MainSubjectId)
+	}
+}
+
+// TeacherDBStub is a stub struct that proxies method calls to function fields, the methods of the
+// embedded interface are not expected to be called.
+type TeacherDBStub struct {
+	db.TeacherDB
+
+	getAllTeacher func(tq db.TeacherQuery, page db.Page) ([]*model.Teacher, string, error)
+
+	addTeacher func(t *model.Teacher) error
+
+	updateTeacher func(t *model.Teacher) error
+
+	getTeacher func(id string) (*model.Teacher, error)
+}
+
+func (stub TeacherDBStub) GetAllTeacher(tq db.TeacherQuery, page db.Page) ([]*model.Teacher, string, error) {
+	return stub.getAllTeacher(tq, page)
+}
+
+func (stub TeacherDBStub) AddTeacher(t *model.Teacher) error {
+	return stub.addTeacher(t)
+}
+
+func (stub TeacherDBStub) UpdateTeacher(t *model.Teacher) error {
+	return stub.updateTeacher(t)
+}
+
+func (stub TeacherDBStub) GetTeacher(id string) (*model.Teacher, error) {
+	return stub.getTeacher(id)
+}
+
+// SubjectDBStub is a stub struct that proxies method calls to function fields, the methods of the
+// embedded interface are not expected to be called.
+type SubjectDBStub struct {
+	db.SubjectDB
+
+	getAllSubject func(sq db.SubjectQuery, page db.Page) ([]*model.Subject, string, error)
+
+	addSubject func(m *model.Subject) error
+
+	updateSubject func(m *model.Subject) error
+
+	getSubject func(id string) (*model.Subject, error)
+}
+
+func (stub SubjectDBStub) GetAllSubject(sq db.SubjectQuery, page db.Page) ([]*model.Subject, string, error) {
+	return stub.getAllSubject(sq, page)
+}
+
+func (stub SubjectDBStub) AddSubject(m *model.Subject) error {
+	return stub.addSubject(m)
+}
+
+func (stub SubjectDBStub) UpdateSubject(m *model.Subject) error {
+	return stub.updateSubject(m)
+}
+
+func (stub SubjectDBStub) GetSubject(id string) (*model.Subject, error) {
+	return stub.getSubject(id)
+}
+
+// MainSubjectDBStub is a stub struct that proxies method calls to function fields, the methods of the
+// embedded interface are not expected to be called.
+type MainSubjectDBStub struct {
+	db.MainSubjectDB
+
+	getAllMainSubject func(page db.Page) ([]*model.MainSubject, string, error)
+
+	addMainSubject func(m *model.MainSubject) error
+
+	getMainSubject func(id string) (*model.MainSubject, error)
+}
+
+func (stub MainSubjectDBStub) GetAllMainSubject(page db.Page) ([]*model.MainSubject, string, error) {
+	return stub.getAllMainSubject(page)
+}
+
+func (stub MainSubjectDBStub) AddMainSubject(m *model.MainSubject) error {
+	return stub.addMainSubject(m)
+}
+
+func (stub MainSubjectDBStub) GetMainSubject(id string) (*model.MainSubject, error) {
+	return stub.getMainSubject(id)
+}
@@ -23,16 +23,16 @@ func NewKymService(db db.KymDB, cs util.CloudStorageManager, ks thirdparty.KymSe
 	return &KymService{db: db, cs: cs, ks: ks, ms: ms}
 }
 
-func (s *KymService) GetAllKym(status string) ([]*dto.KymResponse, error) {
+func (s *KymService) GetAllKym(status string, page *dto.PageRequest) (*dto.Page, error) {
 
-	kymList, err := s.db.GetAllKym(status)
+	kymList, next, err := s.db.GetAllKym(status, toDBPage(page))
 	if err != nil {
 		return nil, err
 	}
 
 	klmResponse := dto.ToKymDTO(kymList)
 
-	return klmResponse, nil
+	return dto.NewPage(klmResponse, next), nil
 }
 
 func (s *KymService) GetKym(id string) (*dto.KymFullDetailResponse, error) {
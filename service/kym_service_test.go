@@ -193,14 +193,17 @@ func TestKymService_GetAllKym(t *testing.T) {
 		name    string
 		status  string
 		fields  fields
-		want    []*dto.KymResponse
+		want    *dto.Page
 		asserts func(t *testing.T, gotErr error)
 	}{
 		{
 			name: "getAllKymSuccessful",
 			fields: fields{
 				db: KymDBStub{
-					getAllKym: func(status string) ([]*model.Kym, error) {
+					getAllKym: func(status string, page db.Page) ([]*model.Kym, string, error) {
+						if page.Size != 10 || page.Token != "token" {
+							t.Errorf("unexpected page: %+v", page)
+						}
 						return []*model.Kym{
 							{
 								ID:             "GS-16SZ-oOu5iFpn9SMlj",
@@ -258,11 +261,11 @@ func TestKymService_GetAllKym(t *testing.T) {
 								Status: "approved",
 								Notes:  "",
 							},
-						}, nil
+						}, "next", nil
 					},
 				},
 			},
-			want: []*dto.KymResponse{
+			want: dto.NewPage([]*dto.KymResponse{
 				{
 					ID:                  "GS-16SZ-oOu5iFpn9SMlj",
 					BusinessName:        "Beam Data Company",
@@ -271,7 +274,7 @@ func TestKymService_GetAllKym(t *testing.T) {
 					DocumentDownloadURL: "https://test.com",
 					Status:              "approved",
 				},
-			},
+			}, "next"),
 			asserts: func(t *testing.T, gotErr error) {
 				assert.NoError(t, gotErr)
 			},
@@ -280,8 +283,8 @@ func TestKymService_GetAllKym(t *testing.T) {
 			name: "getAllKymFailedWithDBNoSuchEntity",
 			fields: fields{
 				db: KymDBStub{
-					getAllKym: func(status string) ([]*model.Kym, error) {
-						return nil, c.ErrDBNoSuchEntity
+					getAllKym: func(status string, page db.Page) ([]*model.Kym, string, error) {
+						return nil, "", c.ErrDBNoSuchEntity
 					},
 				},
 			},
@@ -300,7 +303,7 @@ func TestKymService_GetAllKym(t *testing.T) {
 				ks: tt.fields.ks,
 				ms: tt.fields.ms,
 			}
-			kymList, err := s.GetAllKym(tt.status)
+			kymList, err := s.GetAllKym(tt.status, &dto.PageRequest{PageSize: 10, PageToken: "token"})
 			if !reflect.DeepEqual(kymList, tt.want) {
 				t.Errorf("GetAllKym() got = %v, want %v", kymList, tt.want)
 			}
@@ -650,7 +653,7 @@ func TestKymService_UpdateKymStatus(t *testing.T) {
 
 // KymDBStub is a stub struct that proxies method calls to function fields.
 type KymDBStub struct {
-	getAllKym func(status string) ([]*model.Kym, error)
+	getAllKym func(status string, page db.Page) ([]*model.Kym, string, error)
 
 	getKym func(id string) (*model.Kym, error)
 
@@ -663,8 +666,8 @@ func (stub KymDBStub) AddKym(kym *model.Kym) error {
 	return stub.addKym(kym)
 }
 
-func (stub KymDBStub) GetAllKym(status string) ([]*model.Kym, error) {
-	return stub.getAllKym(status)
+func (stub KymDBStub) GetAllKym(status string, page db.Page) ([]*model.Kym, string, error) {
+	return stub.getAllKym(status, page)
 }
 
 func (stub KymDBStub) GetKym(id string) (*model.Kym, error) {
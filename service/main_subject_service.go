@@ -25,15 +25,44 @@ func (m *MainSubjectService) AddMainSubject(mainSubjectRequest *dto.NewMainSubje
 
 
 
-func (m *MainSubjectService) GetAllMainSubject() ([]*dto.MainSubject, error) {
+func (m *MainSubjectService) GetAllMainSubject(page *dto.PageRequest) (*dto.Page, error) {
 
-	mainSubjectList, err := m.db.GetAllMainSubject()
+	mainSubjectList, next, err := m.db.GetAllMainSubject(toDBPage(page))
 	if err != nil {
 		return nil, err
 	}
 
 	mainSubjectResponse := dto.ToMainSubjectDTO(mainSubjectList)
 
-	return mainSubjectResponse, nil
+	return dto.NewPage(mainSubjectResponse, next), nil
 }
 
+func (m *MainSubjectService) GetMainSubject(id string) (*dto.MainSubject, error) {
+
+	mainSubject, err := m.db.GetMainSubject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToMainSubject(mainSubject), nil
+}
+
+func (m *MainSubjectService) UpdateMainSubject(id string, nm *dto.NewMainSubject) error {
+	return m.db.UpdateMainSubject(nm.ToModel(id))
+}
+
+func (m *MainSubjectService) PatchMainSubject(id string, pm *dto.PatchMainSubject) error {
+
+	mainSubject, err := m.db.GetMainSubject(id)
+	if err != nil {
+		return err
+	}
+
+	pm.ApplyTo(mainSubject)
+
+	return m.db.UpdateMainSubject(mainSubject)
+}
+
+func (m *MainSubjectService) DeleteMainSubject(id string, cascade bool) error {
+	return m.db.DeleteMainSubject(id, cascade)
+}
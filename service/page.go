@@ -0,0 +1,27 @@
+package service
+
+import (
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+// toDBPage converts the page requested through the api to the page read from the db
+func toDBPage(page *dto.PageRequest) db.Page {
+	return db.Page{Size: page.PageSize, Token: page.PageToken}
+}
+
+// fetchAllPages calls fetch with successive pages until it returns an empty next page token.
+// It is used where the whole list is needed, such as when generating a schedule.
+func fetchAllPages(fetch func(page db.Page) (string, error)) error {
+	page := db.Page{Size: dto.MaxPageSize}
+	for {
+		next, err := fetch(page)
+		if err != nil {
+			return err
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		page.Token = next
+	}
+}
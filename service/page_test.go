@@ -0,0 +1,29 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+func TestFetchAllPages(t *testing.T) {
+	tokens := map[string]string{"": "p2", "p2": "p3", "p3": ""}
+
+	seen := make([]string, 0)
+	err := fetchAllPages(func(page db.Page) (string, error) {
+		assert.Equal(t, dto.MaxPageSize, page.Size)
+		seen = append(seen, page.Token)
+		return tokens[page.Token], nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "p2", "p3"}, seen)
+
+	err = fetchAllPages(func(page db.Page) (string, error) {
+		return "p2", errors.New("some internal db failure")
+	})
+	assert.EqualError(t, err, "some internal db failure")
+}
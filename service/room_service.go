@@ -0,0 +1,59 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+type RoomService struct {
+	db db.RoomDB
+}
+
+func NewRoomService(db db.RoomDB) *RoomService {
+	return &RoomService{db: db}
+}
+
+func (s *RoomService) AddRoom(nr *dto.NewRoom) error {
+	return s.db.AddRoom(nr.ToModel(uuid.New().String()))
+}
+
+func (s *RoomService) GetAllRoom(page *dto.PageRequest) (*dto.Page, error) {
+
+	list, next, err := s.db.GetAllRoom(toDBPage(page))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewPage(dto.ToRoomDTO(list), next), nil
+}
+
+func (s *RoomService) GetRoom(id string) (*dto.Room, error) {
+
+	r, err := s.db.GetRoom(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToRoom(r), nil
+}
+
+func (s *RoomService) UpdateRoom(id string, nr *dto.NewRoom) error {
+	return s.db.UpdateRoom(nr.ToModel(id))
+}
+
+func (s *RoomService) DeleteRoom(id string) error {
+	return s.db.DeleteRoom(id)
+}
+
+// fetchAllRooms reads every room, they are all considered when a schedule is generated
+func fetchAllRooms(roomDB db.RoomDB) ([]*model.Room, error) {
+	rooms := make([]*model.Room, 0)
+	err := fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := roomDB.GetAllRoom(page)
+		rooms = append(rooms, list...)
+		return next, err
+	})
+	return rooms, err
+}
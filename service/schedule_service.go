@@ -0,0 +1,353 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+type ScheduleService struct {
+	db               db.ScheduleDB
+	confirmationDB   db.ConfirmationDB
+	teacherDB        db.TeacherDB
+	subjectDB        db.SubjectDB
+	responsibilityDB db.TeacherResponsibilityDB
+	bellDB           db.BellScheduleDB
+	termDB           db.TermDB
+	holidayDB        db.HolidayDB
+	roomDB           db.RoomDB
+	mainSubjectDB    db.MainSubjectDB
+	// location is the school's time zone, the times of the bell schedule are in it
+	location *time.Location
+}
+
+func NewScheduleService(db db.ScheduleDB, confirmationDB db.ConfirmationDB, teacherDB db.TeacherDB, subjectDB db.SubjectDB, responsibilityDB db.TeacherResponsibilityDB, bellDB db.BellScheduleDB, termDB db.TermDB, holidayDB db.HolidayDB, roomDB db.RoomDB, mainSubjectDB db.MainSubjectDB, location *time.Location) *ScheduleService {
+	return &ScheduleService{db: db, confirmationDB: confirmationDB, teacherDB: teacherDB, subjectDB: subjectDB, responsibilityDB: responsibilityDB, bellDB: bellDB, termDB: termDB, holidayDB: holidayDB, roomDB: roomDB, mainSubjectDB: mainSubjectDB, location: location}
+}
+
+// CreateSchedule generates a timetable for every detail of the requested confirmation and persists it.
+// Only submitted and locked confirmations are scheduled.
+func (s *ScheduleService) CreateSchedule(scheduleRequest *dto.NewSchedule) (*dto.Schedule, error) {
+
+	confirmation, err := s.confirmationDB.GetConfirmation(scheduleRequest.ConfirmationID)
+	if errors.Is(err, c.ErrDBNoSuchEntity) {
+		return nil, &c.ErrValidation{Violations: fmt.Errorf("confirmation %v does not exist", scheduleRequest.ConfirmationID)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !confirmation.Status.Schedulable() {
+		return nil, &c.ErrValidation{Violations: fmt.Errorf("confirmation %v is %v, only %v or %v confirmations can be scheduled",
+			confirmation.ID, confirmation.Status, model.ConfirmationSubmitted, model.ConfirmationLocked)}
+	}
+	bell, err := resolveBellSchedule(s.bellDB, confirmation.BellScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	bellScheduleID := ""
+	if bell != nil {
+		bellScheduleID = bell.ID
+	}
+
+	details := make([]*model.ConfirmationDetail, 0)
+	err = fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := s.confirmationDB.GetAllConfirmationDetail(scheduleRequest.ConfirmationID, page)
+		details = append(details, list...)
+		return next, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(details) == 0 {
+		return nil, &c.ErrValidation{Violations: fmt.Errorf("confirmation %v has no details to schedule", scheduleRequest.ConfirmationID)}
+	}
+
+	teachers := make([]*model.Teacher, 0)
+	err = fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := s.teacherDB.GetAllTeacher(db.TeacherQuery{}, page)
+		teachers = append(teachers, list...)
+		return next, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := make([]*model.Subject, 0)
+	err = fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := s.subjectDB.GetAllSubject(db.SubjectQuery{}, page)
+		subjects = append(subjects, list...)
+		return next, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responsibilities := make([]*model.TeacherResponsibility, 0)
+	err = fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := s.responsibilityDB.GetAllTeacherResponsibility("", "", page)
+		responsibilities = append(responsibilities, list...)
+		return next, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rooms, err := fetchAllRooms(s.roomDB)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	slots, unscheduled := generateSchedule(id, details, teachers, subjects, responsibilities, rooms)
+
+	schedule := model.NewSchedule(id, scheduleRequest.ConfirmationID, bellScheduleID, confirmation.TermID, unscheduled)
+	if err := s.db.AddSchedule(schedule, slots); err != nil {
+		return nil, err
+	}
+
+	return dto.ToScheduleDTO(schedule, slots, bell), nil
+}
+
+// GetSchedule returns a stored timetable with all of its slots
+func (s *ScheduleService) GetSchedule(id string) (*dto.Schedule, error) {
+	return s.getSchedule(id, "", "")
+}
+
+// GetTeacherSchedule returns a stored timetable with only the slots taught by the teacher
+func (s *ScheduleService) GetTeacherSchedule(id string, teacherID string) (*dto.Schedule, error) {
+	return s.getSchedule(id, teacherID, "")
+}
+
+// GetStudentSchedule returns a stored timetable with only the slots and unscheduled lessons of the student.
+// Students are told apart by id, so namesakes in the same confirmation keep their own timetables.
+func (s *ScheduleService) GetStudentSchedule(id string, studentID string) (*dto.Schedule, error) {
+	return s.getSchedule(id, "", studentID)
+}
+
+func (s *ScheduleService) getSchedule(id string, teacherID string, studentID string) (*dto.Schedule, error) {
+
+	schedule, err := s.db.GetSchedule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := s.db.GetAllScheduleSlot(id, teacherID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	sortScheduleSlots(slots)
+
+	// unscheduled lessons only make sense for the whole schedule or for a single student
+	unscheduled := make([]model.UnscheduledLesson, 0)
+	for _, u := range schedule.Unscheduled {
+		if len(teacherID) > 0 {
+			break
+		}
+		if len(studentID) == 0 || u.StudentID == studentID {
+			unscheduled = append(unscheduled, u)
+		}
+	}
+	schedule.Unscheduled = unscheduled
+
+	// schedules generated before bell schedules existed are shown against the default one
+	bell, err := resolveBellSchedule(s.bellDB, schedule.BellScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToScheduleDTO(schedule, slots, bell), nil
+}
+
+// GetScheduleSessions expands the weekly slots of a stored timetable into the dated sessions of its term,
+// skipping holidays and the dates the teacher is away on. Only the sessions of the teacher and/or student are returned when they are given.
+func (s *ScheduleService) GetScheduleSessions(id string, teacherID string, studentID string) (*dto.ScheduleSessions, error) {
+
+	schedule, err := s.db.GetSchedule(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedule.TermID) == 0 {
+		return nil, &c.ErrValidation{Violations: fmt.Errorf("schedule %v was generated from a confirmation without a term", id)}
+	}
+
+	term, err := s.termDB.GetTerm(schedule.TermID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the term of schedule %v: %w", id, err)
+	}
+
+	holidays, err := fetchAllHolidays(s.holidayDB)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := s.db.GetAllScheduleSlot(id, teacherID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	bell, err := resolveBellSchedule(s.bellDB, schedule.BellScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	teachers, err := s.slotTeachers(slots)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ScheduleSessions{
+		ScheduleID: id,
+		TermID:     term.ID,
+		Sessions:   expandSessions(term, holidays, teachers, slots, bell),
+	}, nil
+}
+
+// slotTeachers gets the teachers of the slots by id, teachers deleted since the schedule was generated are
+// left out
+func (s *ScheduleService) slotTeachers(slots []*model.ScheduleSlot) (map[string]*model.Teacher, error) {
+	teachers := make(map[string]*model.Teacher)
+	for _, slot := range slots {
+		if _, ok := teachers[slot.TeacherID]; ok {
+			continue
+		}
+		teacher, err := s.teacherDB.GetTeacher(slot.TeacherID)
+		if err != nil && !errors.Is(err, c.ErrDBNoSuchEntity) {
+			return nil, err
+		}
+		teachers[slot.TeacherID] = teacher
+	}
+	return teachers, nil
+}
+
+// ExportSchedule returns the slots of a stored timetable as a row for every student of a slot. Only the
+// slots of the teacher and/or the rows of the student are exported when they are given.
+func (s *ScheduleService) ExportSchedule(id string, teacherID string, studentID string) (*dto.Export, error) {
+
+	if _, err := s.db.GetSchedule(id); err != nil {
+		return nil, err
+	}
+
+	slots, err := s.db.GetAllScheduleSlot(id, teacherID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	sortScheduleSlots(slots)
+
+	names := newExportNames(s.subjectDB, s.mainSubjectDB, s.teacherDB)
+	rows := func(write func(row []string) error) error {
+		for _, slot := range slots {
+			for i, student := range slot.StudentNames {
+				if !exportsStudent(slot.StudentIDs, i, studentID) {
+					continue
+				}
+				if err := exportSlot(write, names, student, slot); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	return &dto.Export{Name: "schedule-" + id, Columns: dto.ExportColumns, Rows: rows}, nil
+}
+
+// ExportScheduleSessions returns the dated sessions of a stored timetable as a row for every student of a
+// session, filtered like GetScheduleSessions
+func (s *ScheduleService) ExportScheduleSessions(id string, teacherID string, studentID string) (*dto.Export, error) {
+
+	sessions, err := s.GetScheduleSessions(id, teacherID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := newExportNames(s.subjectDB, s.mainSubjectDB, s.teacherDB)
+	rows := func(write func(row []string) error) error {
+		for _, session := range sessions.Sessions {
+			subject, mainSubject, err := names.subject(session.SubjectID)
+			if err != nil {
+				return err
+			}
+			teacher, err := names.teacher(session.TeacherID)
+			if err != nil {
+				return err
+			}
+			for i, student := range session.StudentNames {
+				if !exportsStudent(session.StudentIDs, i, studentID) {
+					continue
+				}
+				row := exportRow(student, subject, mainSubject, teacher, session.Day, session.Period)
+				if err := write(append([]string{session.Date}, row...)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	return &dto.Export{Name: "sessions-" + id, Columns: dto.SessionExportColumns, Rows: rows}, nil
+}
+
+// GetTeacherCalendar returns the classes of the teacher in a schedule as events repeating every week of the
+// schedule's term, the dates falling on a holiday or on the teacher's absences are excluded. Schedules of
+// different confirmations cover different terms, so scheduleID is required rather than guessed.
+func (s *ScheduleService) GetTeacherCalendar(teacherID string, scheduleID string) (*dto.TeacherCalendar, error) {
+
+	if len(scheduleID) == 0 {
+		return nil, &c.ErrValidation{Violations: errors.New("scheduleId is required to build a calendar")}
+	}
+
+	teacher, err := s.teacherDB.GetTeacher(teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.db.GetSchedule(scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find schedule for calendar: %w", err)
+	}
+	if len(schedule.TermID) == 0 {
+		return nil, &c.ErrValidation{Violations: fmt.Errorf("schedule %v was generated from a confirmation without a term", schedule.ID)}
+	}
+
+	term, err := s.termDB.GetTerm(schedule.TermID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the term of schedule %v: %w", schedule.ID, err)
+	}
+
+	holidays, err := fetchAllHolidays(s.holidayDB)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := s.db.GetAllScheduleSlot(schedule.ID, teacherID, "")
+	if err != nil {
+		return nil, err
+	}
+	sortScheduleSlots(slots)
+
+	bell, err := resolveBellSchedule(s.bellDB, schedule.BellScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := lookupTimetableNames(s.subjectDB, s.teacherDB, s.roomDB, slots)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TeacherCalendar{
+		TeacherID:   teacher.ID,
+		TeacherName: strings.TrimSpace(teacher.FirstName + " " + teacher.LastName),
+		ScheduleID:  schedule.ID,
+		Generated:   schedule.Created,
+		Location:    s.location,
+		Events:      calendarEvents(term, holidays, teacher, slots, bell, names, s.location),
+	}, nil
+}
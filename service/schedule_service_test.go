@@ -0,0 +1,145 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+func TestCreateScheduleConfirmationStatus(t *testing.T) {
+	tests := []struct {
+		status      model.ConfirmationStatus
+		schedulable bool
+	}{
+		{model.ConfirmationDraft, false},
+		{model.ConfirmationSubmitted, true},
+		{model.ConfirmationLocked, true},
+		{model.ConfirmationArchived, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			s := NewScheduleService(nil,
+				ConfirmationDBStub{
+					getConfirmation: func(id string) (*model.Confirmation, error) {
+						confirmation := model.NewConfirmation(id, "term 1", "2022-05-01", "", "t1")
+						confirmation.Status = tt.status
+						return confirmation, nil
+					},
+					// reading the details shows the status was accepted
+					getAllConfirmationDetail: func(confirmationId string, page db.Page) ([]*model.ConfirmationDetail, string, error) {
+						return nil, "", assert.AnError
+					},
+				},
+				nil, nil, nil,
+				BellScheduleDBStub{
+					getDefaultBellSchedule: func() (*model.BellSchedule, error) {
+						return nil, c.ErrDBNoSuchEntity
+					},
+				},
+				nil, nil, nil, nil, time.UTC)
+
+			_, err := s.CreateSchedule(&dto.NewSchedule{ConfirmationID: "c1"})
+			if tt.schedulable {
+				assert.ErrorIs(t, err, assert.AnError)
+			} else {
+				var errValidation *c.ErrValidation
+				assert.ErrorAs(t, err, &errValidation)
+			}
+		})
+	}
+}
+
+func TestStudentScheduleNamesakes(t *testing.T) {
+	// s1 and s2 are both called alice
+	schedule := model.NewSchedule("sc1", "c1", "", "", []model.UnscheduledLesson{
+		{StudentID: "s1", StudentName: "alice", SubjectID: "choir", Day: model.Friday, Period: 1, Reason: reasonNoTeacher},
+		{StudentID: "s2", StudentName: "alice", SubjectID: "choir", Day: model.Friday, Period: 2, Reason: reasonNoTeacher},
+	})
+	slots := []*model.ScheduleSlot{
+		{ID: "1", ScheduleID: "sc1", TeacherID: "t1", SubjectID: "piano", Day: model.Monday, Period: 1,
+			StudentNames: []string{"alice", "alice"}, StudentIDs: []string{"s1", "s2"}},
+		{ID: "2", ScheduleID: "sc1", TeacherID: "t1", SubjectID: "piano", Day: model.Tuesday, Period: 1,
+			StudentNames: []string{"alice"}, StudentIDs: []string{"s2"}},
+	}
+	s := NewScheduleService(
+		ScheduleDBStub{
+			getSchedule: func(id string) (*model.Schedule, error) {
+				return schedule, nil
+			},
+			getAllScheduleSlot: func(scheduleID string, teacherID string, studentID string) ([]*model.ScheduleSlot, error) {
+				list := make([]*model.ScheduleSlot, 0)
+				for _, slot := range slots {
+					for _, id := range slot.StudentIDs {
+						if id == studentID {
+							list = append(list, slot)
+							break
+						}
+					}
+				}
+				return list, nil
+			},
+		},
+		nil,
+		TeacherDBStub{
+			getTeacher: func(id string) (*model.Teacher, error) {
+				return nil, c.ErrDBNoSuchEntity
+			},
+		},
+		SubjectDBStub{
+			getSubject: func(id string) (*model.Subject, error) {
+				return nil, c.ErrDBNoSuchEntity
+			},
+		},
+		nil,
+		BellScheduleDBStub{
+			getDefaultBellSchedule: func() (*model.BellSchedule, error) {
+				return nil, c.ErrDBNoSuchEntity
+			},
+		},
+		nil, nil, nil, nil, time.UTC)
+
+	got, err := s.GetStudentSchedule("sc1", "s1")
+	if assert.NoError(t, err) {
+		assert.Len(t, got.Slots, 1)
+		if assert.Len(t, got.Unscheduled, 1) {
+			assert.Equal(t, "s1", got.Unscheduled[0].StudentID)
+		}
+	}
+
+	export, err := s.ExportSchedule("sc1", "", "s1")
+	if assert.NoError(t, err) {
+		rows := make([][]string, 0)
+		assert.NoError(t, export.Rows(func(row []string) error {
+			rows = append(rows, row)
+			return nil
+		}))
+		if assert.Len(t, rows, 1) {
+			assert.Equal(t, "alice", rows[0][0])
+			assert.Equal(t, string(model.Monday), rows[0][4])
+		}
+	}
+}
+
+// ScheduleDBStub is a stub struct that proxies method calls to function fields, the methods of the
+// embedded interface are not expected to be called.
+type ScheduleDBStub struct {
+	db.ScheduleDB
+
+	getSchedule func(id string) (*model.Schedule, error)
+
+	getAllScheduleSlot func(scheduleID string, teacherID string, studentID string) ([]*model.ScheduleSlot, error)
+}
+
+func (stub ScheduleDBStub) GetSchedule(id string) (*model.Schedule, error) {
+	return stub.getSchedule(id)
+}
+
+func (stub ScheduleDBStub) GetAllScheduleSlot(scheduleID string, teacherID string, studentID string) ([]*model.ScheduleSlot, error) {
+	return stub.getAllScheduleSlot(scheduleID, teacherID, studentID)
+}
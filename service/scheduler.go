@@ -0,0 +1,371 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+// reasons recorded against lessons the scheduler could not place
+const (
+	reasonUnknownSubject  = "subject does not exist"
+	reasonStudentBusy     = "student already has a lesson in this period"
+	reasonBelowMinimum    = "fewer than %d students requested this subject in this period"
+	reasonNoTeacher       = "no qualified teacher with free capacity in this period"
+	reasonSectionTooSmall = "remaining students are below the subject minimum of %d"
+	reasonNoRoom          = "no free room with the required features and capacity in this period"
+)
+
+// timeSlot is a single teaching period on a given day
+type timeSlot struct {
+	Day    model.Day
+	Period model.Period
+}
+
+// lessonRequest is one student asking for one subject in one time slot
+type lessonRequest struct {
+	detail  *model.ConfirmationDetail
+	subject *model.Subject
+}
+
+// classKey groups lesson requests that may be taught together
+type classKey struct {
+	SubjectID string
+	timeSlot
+}
+
+// generateSchedule assigns a teacher to every lesson requested by the confirmation details.
+//
+// Every subject listed on a ConfirmationDetail is a lesson the student wants in the detail's day
+// and period. Requests for the same subject in the same period are grouped into classes, and a
+// class only runs when at least Subject.MinOfStudent students asked for it. Each class is taught
+// by a teacher whose MainSubjectID matches the subject's main subject or who holds a
+// TeacherResponsibility for the subject, whose availability windows cover the period, who is not
+// already teaching in that period and who has periods left within the day and week limits of
+// Teacher.Capacity. A teacher takes at most Capacity.MaxStudentsPerPeriod students, or any number
+// when it is zero, larger groups are split across several teachers. When rooms are defined every class is also given a free room
+// having the subject's RequiredFeatures, and a class is never larger than its room's Capacity.
+// A student asking for several subjects in one period attends the first of those classes, in subject
+// id order, which is actually placed. Anything that cannot be placed is returned as unscheduled.
+func generateSchedule(scheduleID string, details []*model.ConfirmationDetail, teachers []*model.Teacher, subjects []*model.Subject, responsibilities []*model.TeacherResponsibility, rooms []*model.Room) ([]*model.ScheduleSlot, []model.UnscheduledLesson) {
+
+	subjectByID := make(map[string]*model.Subject, len(subjects))
+	for _, s := range subjects {
+		subjectByID[s.ID] = s
+	}
+
+	// responsible[teacherID][subjectID] is set when a teacher is explicitly qualified for a subject
+	responsible := make(map[string]map[string]bool)
+	for _, r := range responsibilities {
+		if responsible[r.TeacherId] == nil {
+			responsible[r.TeacherId] = make(map[string]bool)
+		}
+		responsible[r.TeacherId][r.SubjectId] = true
+	}
+
+	slots := make([]*model.ScheduleSlot, 0)
+	unscheduled := make([]model.UnscheduledLesson, 0)
+
+	sorted := sortConfirmationDetails(details)
+
+	// group lesson requests into classes, students asking for several subjects in one period are
+	// grouped into each of them and only kept busy by the first class actually placed
+	classes := make(map[classKey][]lessonRequest)
+	for _, d := range sorted {
+		ts := timeSlot{Day: d.Day, Period: d.Period}
+		for _, subjectID := range d.SubjectDetailID {
+			subject, ok := subjectByID[subjectID]
+			if !ok {
+				unscheduled = append(unscheduled, newUnscheduledLesson(d, subjectID, reasonUnknownSubject))
+				continue
+			}
+			key := classKey{SubjectID: subjectID, timeSlot: ts}
+			classes[key] = append(classes[key], lessonRequest{detail: d, subject: subject})
+		}
+	}
+
+	keys := make([]classKey, 0, len(classes))
+	for k := range classes {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Day != keys[j].Day {
+			return keys[i].Day.Before(keys[j].Day)
+		}
+		if keys[i].Period != keys[j].Period {
+			return keys[i].Period < keys[j].Period
+		}
+		return keys[i].SubjectID < keys[j].SubjectID
+	})
+
+	// a student can only attend one lesson per period, studentBusy is set once a lesson is assigned
+	studentBusy := make(map[string]map[timeSlot]bool)
+	teacherBusy := make(map[string]map[timeSlot]bool)
+	load := newTeacherLoad()
+	booking := newRoomBooking(rooms)
+	for _, key := range keys {
+		requests := make([]lessonRequest, 0, len(classes[key]))
+		requested := make(map[string]bool)
+		for _, req := range classes[key] {
+			student := req.detail.StudentKey()
+			if studentBusy[student][key.timeSlot] || requested[student] {
+				unscheduled = append(unscheduled, newUnscheduledLesson(req.detail, key.SubjectID, reasonStudentBusy))
+				continue
+			}
+			requested[student] = true
+			requests = append(requests, req)
+		}
+		if len(requests) == 0 {
+			continue
+		}
+		subject := requests[0].subject
+		minOfStudent := subject.MinOfStudent
+		if minOfStudent < 1 {
+			minOfStudent = 1
+		}
+
+		if len(requests) < minOfStudent {
+			for _, req := range requests {
+				unscheduled = append(unscheduled, newUnscheduledLesson(req.detail, key.SubjectID, fmt.Sprintf(reasonBelowMinimum, minOfStudent)))
+			}
+			continue
+		}
+
+		remaining := requests
+		noRoom := false
+		for _, t := range qualifiedTeachers(teachers, subject, responsible) {
+			if len(remaining) == 0 {
+				break
+			}
+			if teacherBusy[t.ID][key.timeSlot] || !t.AvailableAt(key.Day, key.Period) || !load.canTeach(t, key.Day) {
+				continue
+			}
+
+			n := teacherCapacity(t)
+			if n > len(remaining) {
+				n = len(remaining)
+			}
+			if n < minOfStudent {
+				continue
+			}
+
+			room, ok := booking.find(subject, key.timeSlot, n, minOfStudent)
+			if !ok {
+				noRoom = true
+				break
+			}
+			if room != nil && room.Capacity < n {
+				n = room.Capacity
+			}
+
+			slot := newScheduleSlot(scheduleID, t.ID, key, remaining[:n])
+			if room != nil {
+				slot.RoomID = room.ID
+				booking.book(room.ID, key.timeSlot)
+			}
+			slots = append(slots, slot)
+			for _, req := range remaining[:n] {
+				student := req.detail.StudentKey()
+				if studentBusy[student] == nil {
+					studentBusy[student] = make(map[timeSlot]bool)
+				}
+				studentBusy[student][key.timeSlot] = true
+			}
+			remaining = remaining[n:]
+
+			if teacherBusy[t.ID] == nil {
+				teacherBusy[t.ID] = make(map[timeSlot]bool)
+			}
+			teacherBusy[t.ID][key.timeSlot] = true
+			load.add(t.ID, key.Day)
+		}
+
+		reason := reasonNoTeacher
+		if noRoom {
+			reason = reasonNoRoom
+		} else if len(remaining) > 0 && len(remaining) < minOfStudent && len(remaining) < len(requests) {
+			reason = fmt.Sprintf(reasonSectionTooSmall, minOfStudent)
+		}
+		for _, req := range remaining {
+			unscheduled = append(unscheduled, newUnscheduledLesson(req.detail, key.SubjectID, reason))
+		}
+	}
+
+	return slots, unscheduled
+}
+
+// sortConfirmationDetails returns a copy of the details ordered by day, period, student then id,
+// so results do not depend on datastore ordering
+func sortConfirmationDetails(details []*model.ConfirmationDetail) []*model.ConfirmationDetail {
+	sorted := make([]*model.ConfirmationDetail, len(details))
+	copy(sorted, details)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Day != b.Day {
+			return a.Day.Before(b.Day)
+		}
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+		if a.StudentName != b.StudentName {
+			return a.StudentName < b.StudentName
+		}
+		return a.ID < b.ID
+	})
+	return sorted
+}
+
+// qualifiedTeachers returns the teachers able to teach the subject, largest capacity first.
+// A teacher is qualified through their main subject or an explicit TeacherResponsibility.
+func qualifiedTeachers(teachers []*model.Teacher, subject *model.Subject, responsible map[string]map[string]bool) []*model.Teacher {
+	res := make([]*model.Teacher, 0)
+	for _, t := range teachers {
+		qualified := t.MainSubjectID == subject.MainSubjectId || responsible[t.ID][subject.ID]
+		if qualified {
+			res = append(res, t)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		ci, cj := teacherCapacity(res[i]), teacherCapacity(res[j])
+		if ci != cj {
+			return ci > cj
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res
+}
+
+// unlimitedStudents is the capacity of a teacher without a limit on the students of a class
+const unlimitedStudents = math.MaxInt32
+
+// teacherCapacity is the number of students a teacher takes in a single class. Teachers stored before
+// the limits existed have none, they take any number.
+func teacherCapacity(t *model.Teacher) int {
+	if t.Capacity.MaxStudentsPerPeriod == 0 {
+		return unlimitedStudents
+	}
+	return t.Capacity.MaxStudentsPerPeriod
+}
+
+// teacherLoad counts the periods booked for each teacher while a schedule is generated
+type teacherLoad struct {
+	perDay  map[string]map[model.Day]int
+	perWeek map[string]int
+}
+
+func newTeacherLoad() *teacherLoad {
+	return &teacherLoad{perDay: make(map[string]map[model.Day]int), perWeek: make(map[string]int)}
+}
+
+// canTeach reports whether one more period on the day stays within the teacher's limits
+func (l *teacherLoad) canTeach(t *model.Teacher, day model.Day) bool {
+	if max := t.Capacity.MaxPeriodsPerDay; max > 0 && l.perDay[t.ID][day] >= max {
+		return false
+	}
+	if max := t.Capacity.MaxPeriodsPerWeek; max > 0 && l.perWeek[t.ID] >= max {
+		return false
+	}
+	return true
+}
+
+func (l *teacherLoad) add(teacherID string, day model.Day) {
+	if l.perDay[teacherID] == nil {
+		l.perDay[teacherID] = make(map[model.Day]int)
+	}
+	l.perDay[teacherID][day]++
+	l.perWeek[teacherID]++
+}
+
+// roomBooking tracks the rooms taken in each time slot while a schedule is generated
+type roomBooking struct {
+	rooms []*model.Room
+	busy  map[string]map[timeSlot]bool
+}
+
+// newRoomBooking orders the rooms smallest first, so classes take the smallest room they fit in
+func newRoomBooking(rooms []*model.Room) *roomBooking {
+	sorted := make([]*model.Room, len(rooms))
+	copy(sorted, rooms)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Capacity != sorted[j].Capacity {
+			return sorted[i].Capacity < sorted[j].Capacity
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return &roomBooking{rooms: sorted, busy: make(map[string]map[timeSlot]bool)}
+}
+
+// find picks a free room with the subject's required features for a class of n students, the smallest
+// room holding all of them or else the largest one holding at least min. It returns a nil room and true
+// when no rooms are defined, so schedules are generated without rooms as before rooms existed.
+func (b *roomBooking) find(subject *model.Subject, ts timeSlot, n int, min int) (*model.Room, bool) {
+	if len(b.rooms) == 0 {
+		return nil, true
+	}
+	var largest *model.Room
+	for _, r := range b.rooms {
+		if b.busy[r.ID][ts] || !r.HasFeatures(subject.RequiredFeatures) || r.Capacity < min {
+			continue
+		}
+		if r.Capacity >= n {
+			return r, true
+		}
+		largest = r
+	}
+	return largest, largest != nil
+}
+
+func (b *roomBooking) book(roomID string, ts timeSlot) {
+	if b.busy[roomID] == nil {
+		b.busy[roomID] = make(map[timeSlot]bool)
+	}
+	b.busy[roomID][ts] = true
+}
+
+func newScheduleSlot(scheduleID string, teacherID string, key classKey, requests []lessonRequest) *model.ScheduleSlot {
+	slot := &model.ScheduleSlot{
+		ID:                    uuid.New().String(),
+		ScheduleID:            scheduleID,
+		TeacherID:             teacherID,
+		SubjectID:             key.SubjectID,
+		Day:                   key.Day,
+		Period:                key.Period,
+		StudentNames:          make([]string, 0, len(requests)),
+		StudentIDs:            make([]string, 0, len(requests)),
+		ConfirmationDetailIDs: make([]string, 0, len(requests)),
+	}
+	for _, req := range requests {
+		slot.StudentNames = append(slot.StudentNames, req.detail.StudentName)
+		slot.StudentIDs = append(slot.StudentIDs, req.detail.StudentID)
+		slot.ConfirmationDetailIDs = append(slot.ConfirmationDetailIDs, req.detail.ID)
+	}
+	return slot
+}
+
+func newUnscheduledLesson(d *model.ConfirmationDetail, subjectID string, reason string) model.UnscheduledLesson {
+	return model.UnscheduledLesson{
+		ConfirmationDetailID: d.ID,
+		StudentID:            d.StudentID,
+		StudentName:          d.StudentName,
+		SubjectID:            subjectID,
+		Day:                  d.Day,
+		Period:               d.Period,
+		Reason:               reason,
+	}
+}
+
+// sortScheduleSlots orders slots by day, period then subject so timetables read chronologically
+func sortScheduleSlots(slots []*model.ScheduleSlot) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].Day != slots[j].Day {
+			return slots[i].Day.Before(slots[j].Day)
+		}
+		if slots[i].Period != slots[j].Period {
+			return slots[i].Period < slots[j].Period
+		}
+		return slots[i].SubjectID < slots[j].SubjectID
+	})
+}
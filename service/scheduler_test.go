@@ -0,0 +1,304 @@
+package service
+
+import (
+	"testing"
+
+	"cloud.google.com/go/datastore"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+func TestGenerateSchedule(t *testing.T) {
+	subjects := []*model.Subject{
+		model.NewSubject("piano", "Piano", "music", 1, []string{"Piano"}),
+		model.NewSubject("choir", "Choir", "music", 3, nil),
+		model.NewSubject("math", "Math", "science", 2, nil),
+	}
+
+	tests := []struct {
+		name        string
+		details     []*model.ConfirmationDetail
+		teachers    []*model.Teacher
+		duties      []*model.TeacherResponsibility
+		rooms       []*model.Room
+		wantSlots   int
+		wantReasons map[string]int
+		asserts     func(t *testing.T, slots []*model.ScheduleSlot)
+	}{
+		{
+			name: "assignsQualifiedTeacherWithinCapacity",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"math"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"math"}, "", "bob", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d3", "c1", []string{"math"}, "", "carol", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t-music", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "music", nil, nil),
+				model.NewTeacher("t-science", "b", "b", "b", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 5}, "science", nil, nil),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{},
+			asserts: func(t *testing.T, slots []*model.ScheduleSlot) {
+				assert.Equal(t, "t-science", slots[0].TeacherID)
+				assert.Equal(t, []string{"alice", "bob", "carol"}, slots[0].StudentNames)
+				assert.Equal(t, model.Day("monday"), slots[0].Day)
+				assert.Equal(t, model.Period(1), slots[0].Period)
+			},
+		},
+		{
+			name: "splitsGroupLargerThanCapacity",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"math"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"math"}, "", "bob", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d3", "c1", []string{"math"}, "", "carol", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d4", "c1", []string{"math"}, "", "dave", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 2}, "science", nil, nil),
+				model.NewTeacher("t2", "b", "b", "b", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 2}, "science", nil, nil),
+			},
+			wantSlots:   2,
+			wantReasons: map[string]int{},
+		},
+		{
+			name: "dropsClassesBelowMinimum",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"choir"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"choir"}, "", "bob", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "music", nil, nil),
+			},
+			wantSlots:   0,
+			wantReasons: map[string]int{"fewer than 3 students requested this subject in this period": 2},
+		},
+		{
+			name: "teacherTeachesOneClassPerPeriod",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"choir"}, "", "bob", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d3", "c1", []string{"choir"}, "", "carol", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d4", "c1", []string{"choir"}, "", "dave", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "music", nil, nil),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{reasonNoTeacher: 1},
+		},
+		{
+			name: "studentCannotAttendTwoLessonsInOnePeriod",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"piano", "math"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"math"}, "", "bob", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "music", nil, nil),
+				model.NewTeacher("t2", "b", "b", "b", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "science", nil, nil),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{reasonStudentBusy: 1},
+		},
+		{
+			name: "studentDroppedFromClassBelowMinimumKeepsOtherSubject",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"choir", "math"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"math"}, "", "bob", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "music", nil, nil),
+				model.NewTeacher("t2", "b", "b", "b", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "science", nil, nil),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{"fewer than 3 students requested this subject in this period": 1},
+			asserts: func(t *testing.T, slots []*model.ScheduleSlot) {
+				assert.Equal(t, "math", slots[0].SubjectID)
+				assert.Equal(t, []string{"alice", "bob"}, slots[0].StudentNames)
+			},
+		},
+		{
+			name: "teacherOnlyPlacedInsideAvailability",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "", "alice", "p1", 2, "tuesday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"piano"}, "", "bob", "p1", 5, "tuesday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "music", []model.AvailabilityWindow{
+					{Day: "tuesday", StartPeriod: 5, EndPeriod: 8},
+					{Day: "thursday", StartPeriod: 5, EndPeriod: 8},
+				}, nil),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{reasonNoTeacher: 1},
+			asserts: func(t *testing.T, slots []*model.ScheduleSlot) {
+				assert.Equal(t, model.Period(5), slots[0].Period)
+			},
+		},
+		{
+			name: "responsibilityQualifiesTeacherOutsideMainSubject",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "", "alice", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t-science", "b", "b", "b", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 5}, "science", nil, nil),
+			},
+			duties: []*model.TeacherResponsibility{
+				model.NewTeacherResponsibility("t-science-piano", "t-science", "piano"),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{},
+			asserts: func(t *testing.T, slots []*model.ScheduleSlot) {
+				assert.Equal(t, "t-science", slots[0].TeacherID)
+			},
+		},
+		{
+			name: "teacherPeriodLimitsAreRespected",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"piano"}, "", "bob", "p1", 2, "monday"),
+				model.NewConfirmationDetail("d3", "c1", []string{"piano"}, "", "carol", "p1", 1, "tuesday"),
+				model.NewConfirmationDetail("d4", "c1", []string{"piano"}, "", "dave", "p1", 1, "wednesday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxPeriodsPerDay: 1, MaxPeriodsPerWeek: 2, MaxStudentsPerPeriod: 5}, "music", nil, nil),
+			},
+			wantSlots:   2,
+			wantReasons: map[string]int{reasonNoTeacher: 2},
+			asserts: func(t *testing.T, slots []*model.ScheduleSlot) {
+				assert.Equal(t, model.Day("monday"), slots[0].Day)
+				assert.Equal(t, model.Day("tuesday"), slots[1].Day)
+			},
+		},
+		{
+			name: "unknownSubject",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"history"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"piano"}, "", "bob", "p1", 2, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "music", nil, nil),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{reasonUnknownSubject: 1},
+		},
+		{
+			name: "legacyTeacherWithoutStudentLimitTakesWholeClass",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"math"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"math"}, "", "bob", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d3", "c1", []string{"math"}, "", "carol", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				loadLegacyTeacher(t, "t-legacy", "science", "mornings only"),
+				model.NewTeacher("t-limited", "b", "b", "b", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 2}, "science", nil, nil),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{},
+			asserts: func(t *testing.T, slots []*model.ScheduleSlot) {
+				assert.Equal(t, "t-legacy", slots[0].TeacherID)
+				assert.Equal(t, []string{"alice", "bob", "carol"}, slots[0].StudentNames)
+			},
+		},
+		{
+			name: "tellsStudentsSharingANameApart",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"math"}, "s1", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"math"}, "s2", "alice", "p2", 1, "monday"),
+				model.NewConfirmationDetail("d3", "c1", []string{"piano"}, "s1", "alice", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 5}, "science", nil, nil),
+				model.NewTeacher("t2", "b", "b", "b", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 5}, "music", nil, nil),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{reasonStudentBusy: 1},
+			asserts: func(t *testing.T, slots []*model.ScheduleSlot) {
+				assert.Equal(t, []string{"alice", "alice"}, slots[0].StudentNames)
+				assert.Equal(t, []string{"s1", "s2"}, slots[0].StudentKeys())
+			},
+		},
+		{
+			name: "allocatesRoomWithRequiredFeatures",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"piano"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"piano"}, "", "bob", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 1}, "music", nil, nil),
+				model.NewTeacher("t2", "b", "b", "b", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 1}, "music", nil, nil),
+			},
+			rooms: []*model.Room{
+				model.NewRoom("r-hall", "Hall", 30, nil),
+				model.NewRoom("r-piano", "Piano room", 2, []string{"piano", "Lab"}),
+			},
+			wantSlots:   1,
+			wantReasons: map[string]int{reasonNoRoom: 1},
+			asserts: func(t *testing.T, slots []*model.ScheduleSlot) {
+				assert.Equal(t, "r-piano", slots[0].RoomID)
+			},
+		},
+		{
+			name: "splitsClassLargerThanRoom",
+			details: []*model.ConfirmationDetail{
+				model.NewConfirmationDetail("d1", "c1", []string{"math"}, "", "alice", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d2", "c1", []string{"math"}, "", "bob", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d3", "c1", []string{"math"}, "", "carol", "p1", 1, "monday"),
+				model.NewConfirmationDetail("d4", "c1", []string{"math"}, "", "dave", "p1", 1, "monday"),
+			},
+			teachers: []*model.Teacher{
+				model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "science", nil, nil),
+				model.NewTeacher("t2", "b", "b", "b", "0", model.TeacherCapacity{MaxStudentsPerPeriod: 10}, "science", nil, nil),
+			},
+			rooms: []*model.Room{
+				model.NewRoom("r1", "Room 1", 2, nil),
+				model.NewRoom("r2", "Room 2", 2, nil),
+				model.NewRoom("r3", "Room 3", 1, nil),
+			},
+			wantSlots:   2,
+			wantReasons: map[string]int{},
+			asserts: func(t *testing.T, slots []*model.ScheduleSlot) {
+				assert.NotEqual(t, slots[0].RoomID, slots[1].RoomID)
+				for _, slot := range slots {
+					assert.Len(t, slot.StudentNames, 2)
+					assert.NotEqual(t, "r3", slot.RoomID)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots, unscheduled := generateSchedule("s1", tt.details, tt.teachers, subjects, tt.duties, tt.rooms)
+
+			assert.Len(t, slots, tt.wantSlots)
+			for _, slot := range slots {
+				assert.Equal(t, "s1", slot.ScheduleID)
+			}
+
+			reasons := make(map[string]int)
+			for _, u := range unscheduled {
+				reasons[u.Reason]++
+			}
+			assert.Equal(t, tt.wantReasons, reasons)
+
+			if tt.asserts != nil {
+				tt.asserts(t, slots)
+			}
+		})
+	}
+}
+
+// loadLegacyTeacher loads a teacher stored before TeacherCapacity existed, with a free text Capacity
+func loadLegacyTeacher(t *testing.T, id string, mainSubjectID string, capacity string) *model.Teacher {
+	teacher := &model.Teacher{}
+	err := teacher.Load([]datastore.Property{
+		{Name: "ID", Value: id},
+		{Name: "MainSubjectID", Value: mainSubjectID},
+		{Name: "Capacity", Value: capacity},
+	})
+	if err != nil {
+		t.Fatalf("failed to load legacy teacher: %v", err)
+	}
+	return teacher
+}
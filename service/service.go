@@ -11,7 +11,7 @@ type KymServiceInterface interface {
 
 	GetKym(id string) (*dto.KymFullDetailResponse, error)
 
-	GetAllKym(status string) ([]*dto.KymResponse, error)
+	GetAllKym(status string, page *dto.PageRequest) (*dto.Page, error)
 
 	UpdateKymStatus(id string, kymStatusReq *dto.UpdateKymStatusRequest, userInfo string) error
 }
@@ -31,24 +31,156 @@ type MerchantServiceInterface interface {
 type TeacherServiceInterface interface {
 	AddTeacher(nt *dto.NewTeacher) error
 
-	GetAllTeacher() ([]*dto.Teacher, error)
+	GetAllTeacher(query *dto.TeacherQuery, page *dto.PageRequest) (*dto.Page, error)
+
+	GetTeacher(id string) (*dto.Teacher, error)
+
+	UpdateTeacher(id string, nt *dto.NewTeacher) error
+
+	PatchTeacher(id string, pt *dto.PatchTeacher) error
+
+	DeleteTeacher(id string) error
+
+	GetTeacherAvailability(id string) (*dto.TeacherAvailability, error)
+
+	UpdateTeacherAvailability(id string, availability *dto.TeacherAvailability) error
+
+	GetTeacherSubjects(id string, page *dto.PageRequest) (*dto.Page, error)
+
+	AddTeacherSubject(id string, ts *dto.NewTeacherSubject) error
+
+	UpdateTeacherSubjects(id string, ts *dto.TeacherSubjects) error
+
+	DeleteTeacherSubject(id string, subjectID string) error
+
+	GetTeacherWorkload(id string, scheduleID string) (*dto.TeacherWorkload, error)
+
+	ImportTeachers(rows []*dto.TeacherRow, createMainSubjects bool, dryRun bool) (*dto.ImportSummary, error)
 }
 
 type MainSubjectServiceInterface interface {
 	AddMainSubject(nt *dto.NewMainSubject) error
 
-	GetAllMainSubject() ([]*dto.MainSubject, error)
+	GetAllMainSubject(page *dto.PageRequest) (*dto.Page, error)
+
+	GetMainSubject(id string) (*dto.MainSubject, error)
+
+	UpdateMainSubject(id string, nm *dto.NewMainSubject) error
+
+	PatchMainSubject(id string, pm *dto.PatchMainSubject) error
+
+	DeleteMainSubject(id string, cascade bool) error
 }
 
 type SubjectServiceInterface interface {
 	AddSubject(nt *dto.NewSubject) error
 
-	GetAllSubject() ([]*dto.Subject, error)
+	GetAllSubject(query *dto.SubjectQuery, page *dto.PageRequest) (*dto.Page, error)
+
+	GetSubject(id string) (*dto.Subject, error)
+
+	UpdateSubject(id string, ns *dto.NewSubject) error
+
+	PatchSubject(id string, ps *dto.PatchSubject) error
+
+	DeleteSubject(id string, cascade bool) error
+
+	ImportSubjects(rows []*dto.SubjectRow, createMainSubjects bool, dryRun bool) (*dto.ImportSummary, error)
 }
 
 type ConfirmationServiceInterface interface {
 	AddConfirmation(request *dto.NewConfirmation) error
-	GetAllConfirmation() ([]*dto.Confirmation, error)
+	GetAllConfirmation(page *dto.PageRequest) (*dto.Page, error)
 	AddConfirmationDetail(request *dto.NewConfirmationDetail) error
-	GetAllConfirmationDetail(id string) ([]*dto.ConfirmationDetail, error)
+	ImportConfirmationDetails(id string, rows []*dto.ConfirmationDetailRow, dryRun bool) (*dto.ImportResult, error)
+	ExportConfirmationDetails(id string) (*dto.Export, error)
+	UpdateConfirmationStatus(id string, request *dto.UpdateConfirmationStatusRequest, changedBy string) (*dto.Confirmation, error)
+	GetAllConfirmationDetail(id string, page *dto.PageRequest) (*dto.Page, error)
+
+	GetConfirmationConflicts(id string) (*dto.ConfirmationConflicts, error)
+}
+
+// ScheduleServiceInterface defines business logic of schedule api
+type ScheduleServiceInterface interface {
+	CreateSchedule(request *dto.NewSchedule) (*dto.Schedule, error)
+
+	GetSchedule(id string) (*dto.Schedule, error)
+
+	GetTeacherSchedule(id string, teacherID string) (*dto.Schedule, error)
+
+	GetStudentSchedule(id string, studentID string) (*dto.Schedule, error)
+
+	GetScheduleSessions(id string, teacherID string, studentID string) (*dto.ScheduleSessions, error)
+
+	GetTeacherCalendar(teacherID string, scheduleID string) (*dto.TeacherCalendar, error)
+
+	ExportSchedule(id string, teacherID string, studentID string) (*dto.Export, error)
+
+	ExportScheduleSessions(id string, teacherID string, studentID string) (*dto.Export, error)
+}
+
+// BellScheduleServiceInterface defines business logic of bell schedule api
+type BellScheduleServiceInterface interface {
+	AddBellSchedule(nb *dto.NewBellSchedule) error
+
+	GetAllBellSchedule(page *dto.PageRequest) (*dto.Page, error)
+
+	GetBellSchedule(id string) (*dto.BellSchedule, error)
+
+	UpdateBellSchedule(id string, nb *dto.NewBellSchedule) error
+
+	DeleteBellSchedule(id string) error
+}
+
+// StudentServiceInterface defines business logic of student api
+type StudentServiceInterface interface {
+	AddStudent(ns *dto.NewStudent) error
+
+	GetAllStudent(page *dto.PageRequest) (*dto.Page, error)
+
+	GetStudent(id string) (*dto.Student, error)
+
+	UpdateStudent(id string, ns *dto.NewStudent) error
+
+	DeleteStudent(id string) error
+
+	GetStudentTimetable(id string, scheduleID string) (*dto.StudentTimetable, error)
+
+	ExportStudentTimetable(id string, scheduleID string) (*dto.Export, error)
+}
+
+// RoomServiceInterface defines business logic of room api
+type RoomServiceInterface interface {
+	AddRoom(nr *dto.NewRoom) error
+
+	GetAllRoom(page *dto.PageRequest) (*dto.Page, error)
+
+	GetRoom(id string) (*dto.Room, error)
+
+	UpdateRoom(id string, nr *dto.NewRoom) error
+
+	DeleteRoom(id string) error
+}
+
+// CalendarServiceInterface defines business logic of term and holiday api
+type CalendarServiceInterface interface {
+	AddTerm(nt *dto.NewTerm) error
+
+	GetAllTerm(page *dto.PageRequest) (*dto.Page, error)
+
+	GetTerm(id string) (*dto.Term, error)
+
+	UpdateTerm(id string, nt *dto.NewTerm) error
+
+	DeleteTerm(id string) error
+
+	AddHoliday(nh *dto.NewHoliday) error
+
+	GetAllHoliday(page *dto.PageRequest) (*dto.Page, error)
+
+	GetHoliday(id string) (*dto.Holiday, error)
+
+	UpdateHoliday(id string, nh *dto.NewHoliday) error
+
+	DeleteHoliday(id string) error
 }
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+// sessionDateFormat is the layout of the dates of sessions
+const sessionDateFormat = "2006-01-02"
+
+// expandSessions turns the weekly slots of a schedule into a session on every date of the term the slot's
+// day falls on. Dates covered by a holiday are skipped, and so are the dates the slot's teacher is away on
+// as listed in their availability exceptions. Teachers missing from teachers have no exceptions. Sessions
+// are ordered by date, period then subject.
+func expandSessions(term *model.Term, holidays []*model.Holiday, teachers map[string]*model.Teacher, slots []*model.ScheduleSlot, bell *model.BellSchedule) []*dto.Session {
+	// slots on the same day share the dates, they are only worked out once per day
+	datesByDay := make(map[model.Day][]time.Time)
+	sessions := make([]*dto.Session, 0)
+	for _, slot := range slots {
+		dates, ok := datesByDay[slot.Day]
+		if !ok {
+			dates = term.SessionDates(slot.Day, holidays)
+			datesByDay[slot.Day] = dates
+		}
+		teacher := teachers[slot.TeacherID]
+		for _, date := range dates {
+			if teacher != nil && !teacher.AvailableOn(date) {
+				continue
+			}
+			sessions = append(sessions, &dto.Session{
+				Date:           date.Format(sessionDateFormat),
+				Day:            slot.Day,
+				Period:         slot.Period,
+				ScheduleSlotID: slot.ID,
+				TeacherID:      slot.TeacherID,
+				SubjectID:      slot.SubjectID,
+				RoomID:         slot.RoomID,
+				StudentNames:   slot.StudentNames,
+				StudentIDs:     slot.StudentIDs,
+				PeriodTime:     dto.NewPeriodTime(bell, slot.Period),
+			})
+		}
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		a, b := sessions[i], sessions[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+		return a.SubjectID < b.SubjectID
+	})
+	return sessions
+}
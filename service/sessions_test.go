@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+func TestExpandSessions(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	term := model.NewTerm("t1", "Term 1", date("2022-05-16"), date("2022-05-29"))
+	holidays := []*model.Holiday{model.NewHoliday("h1", "Visakha Bucha", date("2022-05-16"), date("2022-05-16"))}
+	slots := []*model.ScheduleSlot{
+		{ID: "s2", TeacherID: "bob", SubjectID: "choir", Day: model.Monday, Period: 2, StudentNames: []string{"carol"}},
+		{ID: "s1", TeacherID: "alice", SubjectID: "piano", Day: model.Monday, Period: 1, StudentNames: []string{"dave"}},
+		{ID: "s3", TeacherID: "alice", SubjectID: "math", Day: model.Tuesday, Period: 1, StudentNames: []string{"erin"}},
+	}
+	bell := model.NewBellSchedule("b1", "Regular day", true, []model.BellPeriod{
+		{Period: 1, Name: "Period 1", Start: "08:30", End: "09:20"},
+	})
+
+	// alice is away on the second Tuesday, bob has been deleted since the schedule was generated
+	teachers := map[string]*model.Teacher{
+		"alice": model.NewTeacher("alice", "a", "a", "a", "0", model.TeacherCapacity{}, "music", nil,
+			[]model.AvailabilityException{{StartDate: date("2022-05-24"), EndDate: date("2022-05-24"), Reason: "leave"}}),
+		"bob": nil,
+	}
+
+	sessions := expandSessions(term, holidays, teachers, slots, bell)
+
+	got := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		got = append(got, s.Date+" "+s.ScheduleSlotID)
+	}
+	assert.Equal(t, []string{
+		"2022-05-17 s3",
+		"2022-05-23 s1",
+		"2022-05-23 s2",
+	}, got)
+	assert.Equal(t, "08:30", sessions[0].StartTime)
+	assert.Empty(t, sessions[2].StartTime)
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+type StudentService struct {
+	db            db.StudentDB
+	scheduleDB    db.ScheduleDB
+	subjectDB     db.SubjectDB
+	teacherDB     db.TeacherDB
+	roomDB        db.RoomDB
+	bellDB        db.BellScheduleDB
+	mainSubjectDB db.MainSubjectDB
+}
+
+func NewStudentService(db db.StudentDB, scheduleDB db.ScheduleDB, subjectDB db.SubjectDB, teacherDB db.TeacherDB, roomDB db.RoomDB, bellDB db.BellScheduleDB, mainSubjectDB db.MainSubjectDB) *StudentService {
+	return &StudentService{db: db, scheduleDB: scheduleDB, subjectDB: subjectDB, teacherDB: teacherDB, roomDB: roomDB, bellDB: bellDB, mainSubjectDB: mainSubjectDB}
+}
+
+func (s *StudentService) AddStudent(ns *dto.NewStudent) error {
+	return s.db.AddStudent(ns.ToModel(uuid.New().String()))
+}
+
+func (s *StudentService) GetAllStudent(page *dto.PageRequest) (*dto.Page, error) {
+
+	list, next, err := s.db.GetAllStudent(toDBPage(page))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewPage(dto.ToStudentDTO(list), next), nil
+}
+
+func (s *StudentService) GetStudent(id string) (*dto.Student, error) {
+
+	st, err := s.db.GetStudent(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToStudent(st), nil
+}
+
+func (s *StudentService) UpdateStudent(id string, ns *dto.NewStudent) error {
+	return s.db.UpdateStudent(ns.ToModel(id))
+}
+
+func (s *StudentService) DeleteStudent(id string) error {
+	return s.db.DeleteStudent(id)
+}
+
+// GetStudentTimetable returns the lessons of the student in a schedule grouped by day and period, together
+// with the lessons the student asked for which could not be scheduled. scheduleID is required.
+func (s *StudentService) GetStudentTimetable(id string, scheduleID string) (*dto.StudentTimetable, error) {
+
+	student, schedule, slots, err := s.studentSlots(id, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	bell, err := resolveBellSchedule(s.bellDB, schedule.BellScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := lookupTimetableNames(s.subjectDB, s.teacherDB, s.roomDB, slots)
+	if err != nil {
+		return nil, err
+	}
+
+	unscheduled := make([]model.UnscheduledLesson, 0)
+	for _, u := range schedule.Unscheduled {
+		if u.StudentID == id {
+			unscheduled = append(unscheduled, u)
+		}
+	}
+
+	return buildTimetable(student, schedule.ID, slots, unscheduled, bell, names), nil
+}
+
+// ExportStudentTimetable returns the lessons of the student in a schedule as a row for every slot, sorted by
+// day and period. scheduleID is required.
+func (s *StudentService) ExportStudentTimetable(id string, scheduleID string) (*dto.Export, error) {
+
+	student, schedule, slots, err := s.studentSlots(id, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := newExportNames(s.subjectDB, s.mainSubjectDB, s.teacherDB)
+	rows := func(write func(row []string) error) error {
+		for _, slot := range slots {
+			if err := exportSlot(write, names, student.Name, slot); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return &dto.Export{Name: "timetable-" + student.ID + "-" + schedule.ID, Columns: dto.ExportColumns, Rows: rows}, nil
+}
+
+// studentSlots returns the student, the schedule and the slots of the student in the schedule sorted by day
+// and period. Schedules of different confirmations cover different terms, so the schedule has to be named
+// rather than guessed.
+func (s *StudentService) studentSlots(id string, scheduleID string) (*model.Student, *model.Schedule, []*model.ScheduleSlot, error) {
+
+	if len(scheduleID) == 0 {
+		return nil, nil, nil, &c.ErrValidation{Violations: errors.New("scheduleId is required to build a timetable")}
+	}
+
+	student, err := s.db.GetStudent(id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	schedule, err := s.scheduleDB.GetSchedule(scheduleID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to find schedule for timetable: %w", err)
+	}
+
+	slots, err := s.scheduleDB.GetAllStudentScheduleSlot(schedule.ID, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sortScheduleSlots(slots)
+	return student, schedule, slots, nil
+}
+
+// timetableNames are the display names of the subjects, teachers and rooms of a timetable by id.
+// Entities deleted since the schedule was generated have no name.
+type timetableNames struct {
+	subjects map[string]string
+	teachers map[string]string
+	rooms    map[string]string
+}
+
+func lookupTimetableNames(subjectDB db.SubjectDB, teacherDB db.TeacherDB, roomDB db.RoomDB, slots []*model.ScheduleSlot) (*timetableNames, error) {
+	names := &timetableNames{subjects: make(map[string]string), teachers: make(map[string]string), rooms: make(map[string]string)}
+	for _, slot := range slots {
+		if _, ok := names.subjects[slot.SubjectID]; !ok {
+			subject, err := subjectDB.GetSubject(slot.SubjectID)
+			if err != nil && !errors.Is(err, c.ErrDBNoSuchEntity) {
+				return nil, err
+			}
+			names.subjects[slot.SubjectID] = ""
+			if subject != nil {
+				names.subjects[slot.SubjectID] = subject.SubjectName
+			}
+		}
+		if _, ok := names.teachers[slot.TeacherID]; !ok {
+			teacher, err := teacherDB.GetTeacher(slot.TeacherID)
+			if err != nil && !errors.Is(err, c.ErrDBNoSuchEntity) {
+				return nil, err
+			}
+			names.teachers[slot.TeacherID] = ""
+			if teacher != nil {
+				names.teachers[slot.TeacherID] = strings.TrimSpace(teacher.FirstName + " " + teacher.LastName)
+			}
+		}
+		if _, ok := names.rooms[slot.RoomID]; !ok && len(slot.RoomID) > 0 {
+			room, err := roomDB.GetRoom(slot.RoomID)
+			if err != nil && !errors.Is(err, c.ErrDBNoSuchEntity) {
+				return nil, err
+			}
+			names.rooms[slot.RoomID] = ""
+			if room != nil {
+				names.rooms[slot.RoomID] = room.Name
+			}
+		}
+	}
+	return names, nil
+}
+
+// buildTimetable groups the slots, which have to be sorted by day and period, into the days of the timetable
+func buildTimetable(student *model.Student, scheduleID string, slots []*model.ScheduleSlot, unscheduled []model.UnscheduledLesson, bell *model.BellSchedule, names *timetableNames) *dto.StudentTimetable {
+	res := &dto.StudentTimetable{
+		StudentID:   student.ID,
+		StudentName: student.Name,
+		Level:       student.Level,
+		ScheduleID:  scheduleID,
+		Days:        make([]*dto.TimetableDay, 0),
+		Unscheduled: dto.ToUnscheduledLessonDTO(unscheduled, bell),
+	}
+
+	var day *dto.TimetableDay
+	for _, slot := range slots {
+		if day == nil || day.Day != slot.Day {
+			day = &dto.TimetableDay{Day: slot.Day, Periods: make([]*dto.TimetablePeriod, 0)}
+			res.Days = append(res.Days, day)
+		}
+		day.Periods = append(day.Periods, &dto.TimetablePeriod{
+			Period:         slot.Period,
+			ScheduleSlotID: slot.ID,
+			SubjectID:      slot.SubjectID,
+			SubjectName:    names.subjects[slot.SubjectID],
+			TeacherID:      slot.TeacherID,
+			TeacherName:    names.teachers[slot.TeacherID],
+			RoomID:         slot.RoomID,
+			RoomName:       names.rooms[slot.RoomID],
+			PeriodTime:     dto.NewPeriodTime(bell, slot.Period),
+		})
+	}
+	return res
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+func TestBuildTimetable(t *testing.T) {
+	student := model.NewStudent("s1", "alice", "P.4", "", "", "", "")
+	slots := []*model.ScheduleSlot{
+		{ID: "1", TeacherID: "t1", SubjectID: "math", Day: model.Tuesday, Period: 1},
+		{ID: "2", TeacherID: "t2", SubjectID: "piano", Day: model.Monday, Period: 2, RoomID: "r1"},
+		{ID: "3", TeacherID: "t1", SubjectID: "math", Day: model.Monday, Period: 1},
+	}
+	sortScheduleSlots(slots)
+	names := &timetableNames{
+		subjects: map[string]string{"math": "Math", "piano": "Piano"},
+		teachers: map[string]string{"t1": "Bob", "t2": "Carol"},
+		rooms:    map[string]string{"r1": "Music room"},
+	}
+	bell := model.NewBellSchedule("b1", "Regular day", true, []model.BellPeriod{
+		{Period: 1, Name: "Period 1", Start: "08:30", End: "09:20"},
+		{Period: 2, Name: "Period 2", Start: "09:20", End: "10:10"},
+	})
+	unscheduled := []model.UnscheduledLesson{{StudentID: "s1", StudentName: "alice", SubjectID: "choir", Day: model.Friday, Period: 1, Reason: reasonNoTeacher}}
+
+	got := buildTimetable(student, "sc1", slots, unscheduled, bell, names)
+
+	assert.Equal(t, "sc1", got.ScheduleID)
+	assert.Len(t, got.Days, 2)
+	assert.Equal(t, model.Monday, got.Days[0].Day)
+	assert.Len(t, got.Days[0].Periods, 2)
+	assert.Equal(t, "Math", got.Days[0].Periods[0].SubjectName)
+	assert.Equal(t, "08:30", got.Days[0].Periods[0].StartTime)
+	assert.Equal(t, "Music room", got.Days[0].Periods[1].RoomName)
+	assert.Equal(t, "Carol", got.Days[0].Periods[1].TeacherName)
+	assert.Equal(t, model.Tuesday, got.Days[1].Day)
+	assert.Len(t, got.Unscheduled, 1)
+	assert.Equal(t, "08:30", got.Unscheduled[0].StartTime)
+}
+
+func TestStudentTimetableRequiresSchedule(t *testing.T) {
+	s := NewStudentService(nil, nil, nil, nil, nil, nil, nil)
+
+	var errValidation *c.ErrValidation
+	_, err := s.GetStudentTimetable("s1", "")
+	assert.ErrorAs(t, err, &errValidation)
+	_, err = s.ExportStudentTimetable("s1", "")
+	assert.ErrorAs(t, err, &errValidation)
+}
@@ -1,17 +1,22 @@
 package service
 
 import (
+	"fmt"
+
 	"github.com/google/uuid"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
 )
 
 type SubjectService struct {
 	db  db.SubjectDB
+	responsibilityDB db.TeacherResponsibilityDB
+	mainSubjectDB db.MainSubjectDB
 }
 
-func NewSubjectService(db db.SubjectDB) *SubjectService {
-	return &SubjectService{db: db}
+func NewSubjectService(db db.SubjectDB, responsibilityDB db.TeacherResponsibilityDB, mainSubjectDB db.MainSubjectDB) *SubjectService {
+	return &SubjectService{db: db, responsibilityDB: responsibilityDB, mainSubjectDB: mainSubjectDB}
 }
 
 
@@ -25,15 +30,146 @@ func (m *SubjectService) AddSubject(subjectRequest *dto.NewSubject) error {
 
 
 
-func (m *SubjectService) GetAllSubject() ([]*dto.Subject, error) {
+// GetAllSubject returns the subjects matching the query, or only those the teacher is able to teach when TeacherID is set
+func (m *SubjectService) GetAllSubject(query *dto.SubjectQuery, page *dto.PageRequest) (*dto.Page, error) {
 
-	subjectList, err := m.db.GetAllSubject()
+	var subjectList []*model.Subject
+	var next string
+	var err error
+	if len(query.TeacherID) > 0 {
+		subjectList, next, err = m.responsibilityDB.GetAllSubjectByTeacher(query.TeacherID, toDBPage(page))
+	} else {
+		sq := db.SubjectQuery{MainSubjectID: query.MainSubjectID, MinStudentsGte: query.MinStudentsGte}
+		subjectList, next, err = m.db.GetAllSubject(sq, toDBPage(page))
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	subjectResponse := dto.ToSubjectDTO(subjectList)
 
-	return subjectResponse, nil
+	return dto.NewPage(subjectResponse, next), nil
+}
+
+func (m *SubjectService) GetSubject(id string) (*dto.Subject, error) {
+
+	subject, err := m.db.GetSubject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToSubject(subject), nil
+}
+
+func (m *SubjectService) UpdateSubject(id string, ns *dto.NewSubject) error {
+	return m.db.UpdateSubject(ns.ToModel(id))
+}
+
+func (m *SubjectService) PatchSubject(id string, ps *dto.PatchSubject) error {
+
+	subject, err := m.db.GetSubject(id)
+	if err != nil {
+		return err
+	}
+
+	ps.ApplyTo(subject)
+
+	return m.db.UpdateSubject(subject)
 }
 
+func (m *SubjectService) DeleteSubject(id string, cascade bool) error {
+	return m.db.DeleteSubject(id, cascade)
+}
+
+// ImportSubjects adds or replaces the subjects read from a spreadsheet. A row replaces the subject with its
+// id, or without an id the subject with the same name. Main subjects are found by name and created when
+// createMainSubjects is set. Nothing is written when dryRun is set.
+func (m *SubjectService) ImportSubjects(rows []*dto.SubjectRow, createMainSubjects bool, dryRun bool) (*dto.ImportSummary, error) {
+
+	mainSubjects, err := newMainSubjectsByName(m.mainSubjectDB, createMainSubjects, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*model.Subject)
+	byName := make(map[string][]*model.Subject)
+	err = fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := m.db.GetAllSubject(db.SubjectQuery{}, page)
+		for _, s := range list {
+			byID[s.ID] = s
+			byName[importKey(s.SubjectName)] = append(byName[importKey(s.SubjectName)], s)
+		}
+		return next, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.NewImportSummary(len(rows), dryRun)
+	for _, row := range rows {
+		if row.Err != nil {
+			res.Reject(row.Line, row.Err)
+			continue
+		}
+		if err := row.Subject.Validate(); err != nil {
+			res.Reject(row.Line, err)
+			continue
+		}
+
+		var existing *model.Subject
+		if len(row.ID) > 0 {
+			if existing = byID[row.ID]; existing == nil {
+				res.Reject(row.Line, fmt.Errorf("subject %v does not exist", row.ID))
+				continue
+			}
+		} else if same := byName[importKey(row.Subject.SubjectName)]; len(same) > 1 {
+			res.Reject(row.Line, fmt.Errorf("there are several subjects named %v, give the id of the one to replace", row.Subject.SubjectName))
+			continue
+		} else if len(same) == 1 {
+			existing = same[0]
+		}
+
+		mainSubjectID, err := mainSubjects.resolve(row.Subject.MainSubjectId)
+		if rowError(err) {
+			res.Reject(row.Line, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		row.Subject.MainSubjectId = mainSubjectID
+
+		if existing == nil {
+			subject := row.Subject.ToModel(uuid.New().String())
+			if !dryRun {
+				err = m.db.AddSubject(subject)
+			}
+			if err == nil {
+				byID[subject.ID] = subject
+				byName[importKey(subject.SubjectName)] = []*model.Subject{subject}
+				res.Created++
+			}
+		} else {
+			subject := row.Subject.ToModel(existing.ID)
+			if row.KeepFeatures {
+				subject.RequiredFeatures = existing.RequiredFeatures
+			}
+			if !dryRun {
+				err = m.db.UpdateSubject(subject)
+			}
+			if err == nil {
+				res.Updated++
+			}
+		}
+		if rowError(err) {
+			res.Reject(row.Line, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res.MainSubjectsCreated = mainSubjects.created
+	return res, nil
+}
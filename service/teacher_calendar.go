@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
+)
+
+// calendarEvents turns each weekly slot into an event repeating on the slot's day from the first to the last
+// date of the term. Dates covered by a holiday or by one of the teacher's availability exceptions become
+// exclusions of the event. Slots whose period has no time in the bell schedule become all day events, slots
+// on a day the term does not have are left out.
+func calendarEvents(term *model.Term, holidays []*model.Holiday, teacher *model.Teacher, slots []*model.ScheduleSlot, bell *model.BellSchedule, names *timetableNames, loc *time.Location) []*dto.CalendarEvent {
+	events := make([]*dto.CalendarEvent, 0, len(slots))
+	for _, slot := range slots {
+		dates := term.Dates(slot.Day)
+		if len(dates) == 0 {
+			continue
+		}
+
+		subject := names.subjects[slot.SubjectID]
+		if len(subject) == 0 {
+			subject = slot.SubjectID
+		}
+		event := &dto.CalendarEvent{
+			UID:         slot.ID + "@" + slot.ScheduleID,
+			Summary:     subject,
+			Description: fmt.Sprintf("Subject: %v\nPeriod: %d\nStudents: %v", subject, slot.Period, strings.Join(slot.StudentNames, ", ")),
+			Location:    names.rooms[slot.RoomID],
+			ExDates:     make([]time.Time, 0),
+		}
+
+		var bp model.BellPeriod
+		timed := false
+		if bell != nil {
+			bp, timed = bell.TeachingPeriod(slot.Period)
+		}
+		// at is when a class starting at the clock time takes place on the date, the date itself when untimed
+		at := func(date time.Time, clock string) time.Time {
+			y, m, d := date.Date()
+			if !timed {
+				return time.Date(y, m, d, 0, 0, 0, 0, loc)
+			}
+			t, _ := time.Parse("15:04", clock)
+			return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, loc)
+		}
+
+		event.AllDay = !timed
+		event.Start = at(dates[0], bp.Start)
+		if timed {
+			event.End = at(dates[0], bp.End)
+		}
+		event.Until = at(dates[len(dates)-1], bp.Start)
+		for _, d := range dates {
+			if model.CoveredByHoliday(d, holidays) || !teacher.AvailableOn(d) {
+				event.ExDates = append(event.ExDates, at(d, bp.Start))
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+func TestCalendarEvents(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	// 2022-05-16 is a Monday
+	term := model.NewTerm("t1", "Term 1", date("2022-05-16"), date("2022-06-10"))
+	holidays := []*model.Holiday{model.NewHoliday("h1", "Visakha Bucha", date("2022-05-23"), date("2022-05-23"))}
+	slots := []*model.ScheduleSlot{
+		{ID: "s1", ScheduleID: "sc1", TeacherID: "t1", SubjectID: "piano", Day: model.Monday, Period: 1, RoomID: "r1", StudentNames: []string{"alice", "bob"}},
+		{ID: "s2", ScheduleID: "sc1", TeacherID: "t1", SubjectID: "math", Day: model.Friday, Period: 5},
+		{ID: "s3", ScheduleID: "sc1", TeacherID: "t1", SubjectID: "math", Day: model.Sunday, Period: 1},
+	}
+	bell := model.NewBellSchedule("b1", "Regular day", true, []model.BellPeriod{
+		{Period: 1, Name: "Period 1", Start: "08:30", End: "09:20"},
+	})
+	names := &timetableNames{subjects: map[string]string{"piano": "Piano"}, rooms: map[string]string{"r1": "Music room"}}
+
+	// the teacher is away on the third Monday
+	teacher := model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{}, "music", nil,
+		[]model.AvailabilityException{{StartDate: date("2022-05-30"), EndDate: date("2022-05-31"), Reason: "leave"}})
+
+	events := calendarEvents(term, holidays, teacher, slots, bell, names, bangkok)
+
+	// the term has Mondays, Fridays and Sundays so every slot becomes an event
+	assert.Len(t, events, 3)
+
+	piano := events[0]
+	assert.Equal(t, "s1@sc1", piano.UID)
+	assert.Equal(t, "Piano", piano.Summary)
+	assert.Equal(t, "Subject: Piano\nPeriod: 1\nStudents: alice, bob", piano.Description)
+	assert.Equal(t, "Music room", piano.Location)
+	assert.False(t, piano.AllDay)
+	assert.Equal(t, time.Date(2022, 5, 16, 8, 30, 0, 0, bangkok), piano.Start)
+	assert.Equal(t, time.Date(2022, 5, 16, 9, 20, 0, 0, bangkok), piano.End)
+	assert.Equal(t, time.Date(2022, 6, 6, 8, 30, 0, 0, bangkok), piano.Until)
+	assert.Equal(t, []time.Time{time.Date(2022, 5, 23, 8, 30, 0, 0, bangkok), time.Date(2022, 5, 30, 8, 30, 0, 0, bangkok)}, piano.ExDates)
+
+	// period 5 is not in the bell schedule
+	math := events[1]
+	assert.Equal(t, "math", math.Summary)
+	assert.True(t, math.AllDay)
+	assert.Equal(t, time.Date(2022, 5, 20, 0, 0, 0, 0, bangkok), math.Start)
+	assert.Equal(t, time.Date(2022, 6, 10, 0, 0, 0, 0, bangkok), math.Until)
+	assert.Empty(t, math.ExDates)
+}
+
+func TestGetTeacherCalendarRequiresSchedule(t *testing.T) {
+	s := NewScheduleService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, time.UTC)
+
+	_, err := s.GetTeacherCalendar("t1", "")
+	var errValidation *c.ErrValidation
+	assert.ErrorAs(t, err, &errValidation)
+}
@@ -1,23 +1,63 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/db"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
 	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/server/dto"
 	"github.com/google/uuid"
 )
 
 type TeacherService struct {
 	db  db.TeacherDB
+	responsibilityDB db.TeacherResponsibilityDB
+	scheduleDB db.ScheduleDB
+	bellDB db.BellScheduleDB
+	mainSubjectDB db.MainSubjectDB
 }
 
-func NewTeacherService(db db.TeacherDB) *TeacherService {
-	return &TeacherService{db: db}
+func NewTeacherService(db db.TeacherDB, responsibilityDB db.TeacherResponsibilityDB, scheduleDB db.ScheduleDB, bellDB db.BellScheduleDB, mainSubjectDB db.MainSubjectDB) *TeacherService {
+	return &TeacherService{db: db, responsibilityDB: responsibilityDB, scheduleDB: scheduleDB, bellDB: bellDB, mainSubjectDB: mainSubjectDB}
+}
+
+// checkAvailability returns a validation error when a window refers to a period the default bell schedule does not have
+func (s *TeacherService) checkAvailability(windows []dto.AvailabilityWindow) error {
+	bell, err := resolveBellSchedule(s.bellDB, "")
+	if err != nil {
+		return err
+	}
+	for _, w := range windows {
+		if err := checkPeriods(bell, w.StartPeriod, w.EndPeriod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveAvailability sets the times of the availability windows of the teachers from the default bell schedule
+func (s *TeacherService) resolveAvailability(teachers ...*dto.Teacher) error {
+	bell, err := resolveBellSchedule(s.bellDB, "")
+	if err != nil {
+		return err
+	}
+	for _, t := range teachers {
+		dto.ResolveAvailabilityTimes(t.Availability, bell)
+	}
+	return nil
 }
 
 
 
 func (s *TeacherService) AddTeacher(teacherRequest *dto.NewTeacher) error {
 
+	if err := s.checkAvailability(teacherRequest.Availability); err != nil {
+		return err
+	}
+
 	id := uuid.New()
 	kymDetail := teacherRequest.ToModel(id.String())
 
@@ -26,15 +66,299 @@ func (s *TeacherService) AddTeacher(teacherRequest *dto.NewTeacher) error {
 
 
 
-func (s *TeacherService) GetAllTeacher() ([]*dto.Teacher, error) {
+// teacherSorts maps the sort options of the api to the properties teachers are listed by
+var teacherSorts = map[string]db.TeacherSort{
+	"firstName": db.TeacherSortFirstName,
+	"nickName":  db.TeacherSortNickName,
+	"lastName":  db.TeacherSortLastName,
+}
+
+// GetAllTeacher returns the teachers matching the query, or only those able to teach the subject when SubjectID is set
+func (s *TeacherService) GetAllTeacher(query *dto.TeacherQuery, page *dto.PageRequest) (*dto.Page, error) {
 
-	teacherList, err := s.db.GetAllTeacher()
+	var teacherList []*model.Teacher
+	var next string
+	var err error
+	if len(query.SubjectID) > 0 {
+		teacherList, next, err = s.responsibilityDB.GetAllTeacherBySubject(query.SubjectID, toDBPage(page))
+	} else {
+		tq := db.TeacherQuery{MainSubjectID: query.MainSubjectID, Q: query.Q, Sort: teacherSorts[query.Sort]}
+		teacherList, next, err = s.db.GetAllTeacher(tq, toDBPage(page))
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	klmResponse := dto.ToTeacherDTO(teacherList)
+	if err := s.resolveAvailability(klmResponse...); err != nil {
+		return nil, err
+	}
+
+	return dto.NewPage(klmResponse, next), nil
+}
+
+func (s *TeacherService) GetTeacher(id string) (*dto.Teacher, error) {
+
+	teacher, err := s.db.GetTeacher(id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToTeacher(teacher)
+	if err := s.resolveAvailability(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *TeacherService) UpdateTeacher(id string, nt *dto.NewTeacher) error {
+
+	if err := s.checkAvailability(nt.Availability); err != nil {
+		return err
+	}
+
+	return s.db.UpdateTeacher(nt.ToModel(id))
+}
+
+func (s *TeacherService) PatchTeacher(id string, pt *dto.PatchTeacher) error {
+
+	if pt.Availability != nil {
+		if err := s.checkAvailability(*pt.Availability); err != nil {
+			return err
+		}
+	}
+
+	teacher, err := s.db.GetTeacher(id)
+	if err != nil {
+		return err
+	}
+
+	pt.ApplyTo(teacher)
+
+	return s.db.UpdateTeacher(teacher)
+}
+
+func (s *TeacherService) DeleteTeacher(id string) error {
+	return s.db.DeleteTeacher(id)
+}
+
+func (s *TeacherService) GetTeacherAvailability(id string) (*dto.TeacherAvailability, error) {
+
+	teacher, err := s.db.GetTeacher(id)
+	if err != nil {
+		return nil, err
+	}
+
+	bell, err := resolveBellSchedule(s.bellDB, "")
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToTeacherAvailabilityDTO(teacher)
+	dto.ResolveAvailabilityTimes(res.Availability, bell)
+	return res, nil
+}
+
+func (s *TeacherService) UpdateTeacherAvailability(id string, availability *dto.TeacherAvailability) error {
+
+	if err := s.checkAvailability(availability.Availability); err != nil {
+		return err
+	}
+
+	return s.db.UpdateTeacherAvailability(
+		id,
+		dto.ToAvailabilityWindowModel(availability.Availability),
+		dto.ToAvailabilityExceptionModel(availability.AvailabilityExceptions),
+	)
+}
+
+// GetTeacherSubjects returns the subjects the teacher is explicitly qualified for
+func (s *TeacherService) GetTeacherSubjects(id string, page *dto.PageRequest) (*dto.Page, error) {
+
+	if _, err := s.db.GetTeacher(id); err != nil {
+		return nil, err
+	}
+
+	list, next, err := s.responsibilityDB.GetAllTeacherResponsibility(id, "", toDBPage(page))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewPage(dto.ToTeacherResponsibilityDTO(list), next), nil
+}
+
+func (s *TeacherService) AddTeacherSubject(id string, ts *dto.NewTeacherSubject) error {
+	tr := model.NewTeacherResponsibility(fmt.Sprintf("%v-%v", id, ts.SubjectID), id, ts.SubjectID)
+	return s.responsibilityDB.AddTeacherResponsibility(tr)
+}
+
+func (s *TeacherService) UpdateTeacherSubjects(id string, ts *dto.TeacherSubjects) error {
+	return s.responsibilityDB.ReplaceTeacherResponsibility(id, ts.SubjectIDs)
+}
+
+func (s *TeacherService) DeleteTeacherSubject(id string, subjectID string) error {
+	return s.responsibilityDB.DeleteTeacherResponsibility(id, subjectID)
+}
+
+// ImportTeachers adds or replaces the teachers read from a spreadsheet. A row replaces the teacher with its
+// id, or without an id the teacher with the same first and last name, and a replaced teacher keeps its
+// availability. Main subjects are found by name and created when createMainSubjects is set. Nothing is
+// written when dryRun is set.
+func (s *TeacherService) ImportTeachers(rows []*dto.TeacherRow, createMainSubjects bool, dryRun bool) (*dto.ImportSummary, error) {
+
+	mainSubjects, err := newMainSubjectsByName(s.mainSubjectDB, createMainSubjects, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*model.Teacher)
+	byName := make(map[string][]*model.Teacher)
+	err = fetchAllPages(func(page db.Page) (string, error) {
+		list, next, err := s.db.GetAllTeacher(db.TeacherQuery{}, page)
+		for _, t := range list {
+			byID[t.ID] = t
+			byName[importKey(t.FirstName, t.LastName)] = append(byName[importKey(t.FirstName, t.LastName)], t)
+		}
+		return next, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.NewImportSummary(len(rows), dryRun)
+	for _, row := range rows {
+		if row.Err != nil {
+			res.Reject(row.Line, row.Err)
+			continue
+		}
+		if err := row.Teacher.Validate(); err != nil {
+			res.Reject(row.Line, err)
+			continue
+		}
 
-	return klmResponse, nil
+		var existing *model.Teacher
+		if len(row.ID) > 0 {
+			if existing = byID[row.ID]; existing == nil {
+				res.Reject(row.Line, fmt.Errorf("teacher %v does not exist", row.ID))
+				continue
+			}
+		} else if same := byName[importKey(row.Teacher.FirstName, row.Teacher.LastName)]; len(same) > 1 {
+			res.Reject(row.Line, fmt.Errorf("there are several teachers named %v %v, give the id of the one to replace", row.Teacher.FirstName, row.Teacher.LastName))
+			continue
+		} else if len(same) == 1 {
+			existing = same[0]
+		}
+
+		mainSubjectID, err := mainSubjects.resolve(row.Teacher.MainSubjectID)
+		if rowError(err) {
+			res.Reject(row.Line, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		row.Teacher.MainSubjectID = mainSubjectID
+
+		if existing == nil {
+			teacher := row.Teacher.ToModel(uuid.New().String())
+			if !dryRun {
+				err = s.db.AddTeacher(teacher)
+			}
+			if err == nil {
+				byID[teacher.ID] = teacher
+				byName[importKey(teacher.FirstName, teacher.LastName)] = []*model.Teacher{teacher}
+				res.Created++
+			}
+		} else {
+			teacher := row.Teacher.ToModel(existing.ID)
+			teacher.Availability = existing.Availability
+			teacher.AvailabilityExceptions = existing.AvailabilityExceptions
+			if !dryRun {
+				err = s.db.UpdateTeacher(teacher)
+			}
+			if err == nil {
+				res.Updated++
+			}
+		}
+		if rowError(err) {
+			res.Reject(row.Line, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res.MainSubjectsCreated = mainSubjects.created
+	return res, nil
 }
 
+// GetTeacherWorkload computes the periods and students booked for the teacher in a schedule against the
+// teacher's limits. The limits are weekly and every schedule is a week of its own confirmation, so the
+// workload is computed from the one schedule named by scheduleID, which is required.
+func (s *TeacherService) GetTeacherWorkload(id string, scheduleID string) (*dto.TeacherWorkload, error) {
+
+	if len(scheduleID) == 0 {
+		return nil, &c.ErrValidation{Violations: errors.New("scheduleId is required to compute a workload")}
+	}
+
+	teacher, err := s.db.GetTeacher(id)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.scheduleDB.GetSchedule(scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find schedule for workload: %w", err)
+	}
+
+	slots, err := s.scheduleDB.GetAllScheduleSlot(schedule.ID, id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return computeWorkload(teacher, schedule.ID, slots), nil
+}
+
+// computeWorkload tallies the slots of a teacher and reports any limit they exceed
+func computeWorkload(teacher *model.Teacher, scheduleID string, slots []*model.ScheduleSlot) *dto.TeacherWorkload {
+	w := &dto.TeacherWorkload{
+		TeacherID:     teacher.ID,
+		ScheduleID:    scheduleID,
+		Capacity:      dto.ToTeacherCapacityDTO(teacher.Capacity),
+		PeriodsPerDay: make(map[model.Day]int),
+		Violations:    make([]string, 0),
+	}
+
+	students := make(map[string]bool)
+	for _, slot := range slots {
+		w.PeriodsPerDay[slot.Day]++
+		w.PeriodsPerWeek++
+		if len(slot.StudentNames) > w.MaxStudentsInPeriod {
+			w.MaxStudentsInPeriod = len(slot.StudentNames)
+		}
+		for _, student := range slot.StudentKeys() {
+			students[student] = true
+		}
+	}
+	w.DistinctStudents = len(students)
+
+	limits := teacher.Capacity
+	days := make([]model.Day, 0, len(w.PeriodsPerDay))
+	for day := range w.PeriodsPerDay {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	for _, day := range days {
+		if limits.MaxPeriodsPerDay > 0 && w.PeriodsPerDay[day] > limits.MaxPeriodsPerDay {
+			w.Violations = append(w.Violations, fmt.Sprintf("%d periods on %v exceeds the limit of %d", w.PeriodsPerDay[day], day, limits.MaxPeriodsPerDay))
+		}
+	}
+	if limits.MaxPeriodsPerWeek > 0 && w.PeriodsPerWeek > limits.MaxPeriodsPerWeek {
+		w.Violations = append(w.Violations, fmt.Sprintf("%d periods in the week exceeds the limit of %d", w.PeriodsPerWeek, limits.MaxPeriodsPerWeek))
+	}
+	if limits.MaxStudentsPerPeriod > 0 && w.MaxStudentsInPeriod > limits.MaxStudentsPerPeriod {
+		w.Violations = append(w.Violations, fmt.Sprintf("%d students in a period exceeds the limit of %d", w.MaxStudentsInPeriod, limits.MaxStudentsPerPeriod))
+	}
+	return w
+}
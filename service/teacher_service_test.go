@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	c "github.com/thoniwutr/schedule-school-teachning-bsd13-backend/constant"
+	"github.com/thoniwutr/schedule-school-teachning-bsd13-backend/model"
+)
+
+func TestComputeWorkload(t *testing.T) {
+	teacher := model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{
+		MaxPeriodsPerDay:     2,
+		MaxPeriodsPerWeek:    3,
+		MaxStudentsPerPeriod: 2,
+	}, "music", nil, nil)
+
+	tests := []struct {
+		name           string
+		slots          []*model.ScheduleSlot
+		wantPerWeek    int
+		wantStudents   int
+		wantViolations int
+	}{
+		{
+			name: "withinLimits",
+			slots: []*model.ScheduleSlot{
+				{ID: "1", TeacherID: "t1", Day: "monday", Period: 1, StudentNames: []string{"alice", "bob"}},
+				{ID: "2", TeacherID: "t1", Day: "tuesday", Period: 1, StudentNames: []string{"alice"}},
+			},
+			wantPerWeek:    2,
+			wantStudents:   2,
+			wantViolations: 0,
+		},
+		{
+			name: "exceedsEveryLimit",
+			slots: []*model.ScheduleSlot{
+				{ID: "1", TeacherID: "t1", Day: "monday", Period: 1, StudentNames: []string{"alice", "bob", "carol"}},
+				{ID: "2", TeacherID: "t1", Day: "monday", Period: 2, StudentNames: []string{"dave"}},
+				{ID: "3", TeacherID: "t1", Day: "monday", Period: 3, StudentNames: []string{"erin"}},
+				{ID: "4", TeacherID: "t1", Day: "tuesday", Period: 1, StudentNames: []string{"alice"}},
+			},
+			wantPerWeek:    4,
+			wantStudents:   5,
+			wantViolations: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := computeWorkload(teacher, "s1", tt.slots)
+
+			assert.Equal(t, "t1", w.TeacherID)
+			assert.Equal(t, "s1", w.ScheduleID)
+			assert.Equal(t, tt.wantPerWeek, w.PeriodsPerWeek)
+			assert.Equal(t, tt.wantStudents, w.DistinctStudents)
+			assert.Len(t, w.Violations, tt.wantViolations)
+		})
+	}
+}
+
+func TestComputeWorkloadWithoutLimits(t *testing.T) {
+	teacher := model.NewTeacher("t1", "a", "a", "a", "0", model.TeacherCapacity{}, "music", nil, nil)
+	slots := []*model.ScheduleSlot{
+		{ID: "1", TeacherID: "t1", Day: "monday", Period: 1, StudentNames: []string{"alice", "bob", "carol"}},
+	}
+
+	w := computeWorkload(teacher, "s1", slots)
+	assert.Equal(t, 3, w.MaxStudentsInPeriod)
+	assert.Empty(t, w.Violations)
+}
+
+func TestGetTeacherWorkloadRequiresSchedule(t *testing.T) {
+	s := NewTeacherService(nil, nil, nil, nil, nil)
+
+	_, err := s.GetTeacherWorkload("t1", "")
+	var errValidation *c.ErrValidation
+	assert.ErrorAs(t, err, &errValidation)
+}
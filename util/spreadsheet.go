@@ -0,0 +1,313 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxSpreadsheetPart is the most bytes read from a single part of an xlsx file once it is decompressed
+const maxSpreadsheetPart = 64 << 20
+
+// Sheet is the table read from an uploaded spreadsheet. The first row is the header, it names the columns.
+type Sheet struct {
+	// Columns are the header cells in order, normalised with ColumnKey
+	Columns []string
+	// Rows are the data rows, rows without any value are skipped
+	Rows []*SheetRow
+}
+
+// SheetRow is a data row of a Sheet
+type SheetRow struct {
+	// Line is the 1 based number of the row in the spreadsheet, the header is line 1
+	Line int
+	// Values maps the normalised column names to the trimmed cells of the row
+	Values map[string]string
+}
+
+// Get returns the value of the column, name is normalised with ColumnKey
+func (r *SheetRow) Get(name string) string {
+	return r.Values[ColumnKey(name)]
+}
+
+// ColumnKey normalises a column name so "Student Name", "student_name" and "studentName" are the same column
+func ColumnKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsSpace(r) || r == '_' || r == '-' || r == '\ufeff' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ReadSpreadsheet reads the first sheet of a csv or xlsx file, the format is chosen by the file name
+func ReadSpreadsheet(name string, r io.ReaderAt, size int64) (*Sheet, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		return ReadCSV(io.NewSectionReader(r, 0, size))
+	case ".xlsx":
+		return ReadXLSX(r, size)
+	}
+	return nil, fmt.Errorf("unsupported spreadsheet %q, upload a .csv or .xlsx file", name)
+}
+
+// ReadCSV reads a comma separated file, rows may have fewer cells than the header
+func ReadCSV(r io.Reader) (*Sheet, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	return newSheet(records)
+}
+
+// ReadXLSX reads the first worksheet of an Office Open XML workbook. Cells are read as the text they hold,
+// formulas as their cached result.
+func ReadXLSX(r io.ReaderAt, size int64) (*Sheet, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := xlsxFirstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := make([]string, 0)
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		sst := &struct {
+			Items []xlsxText `xml:"si"`
+		}{}
+		if err := xlsxDecode(f, sst); err != nil {
+			return nil, err
+		}
+		for _, si := range sst.Items {
+			shared = append(shared, si.String())
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid xlsx: missing %v", sheetPath)
+	}
+	ws := &struct {
+		Rows []struct {
+			Ref   int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}{}
+	if err := xlsxDecode(f, ws); err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(ws.Rows))
+	for _, row := range ws.Rows {
+		// rows without values are left out of the file, the row numbers say where they were
+		for row.Ref > len(records)+1 {
+			records = append(records, nil)
+		}
+		record := make([]string, 0, len(row.Cells))
+		for _, cell := range row.Cells {
+			if col := xlsxColumn(cell.Ref); col >= len(record) {
+				record = append(record, make([]string, col-len(record))...)
+			}
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				i, err := strconv.Atoi(cell.Value)
+				if err != nil || i < 0 || i >= len(shared) {
+					return nil, fmt.Errorf("invalid xlsx: cell %v refers to unknown shared string %q", cell.Ref, cell.Value)
+				}
+				value = shared[i]
+			case "inlineStr":
+				value = cell.Inline.String()
+			case "b":
+				value = strings.ToUpper(strconv.FormatBool(cell.Value == "1"))
+			}
+			record = append(record, value)
+		}
+		records = append(records, record)
+	}
+	return newSheet(records)
+}
+
+// xlsxText is a string of an xlsx file, either plain or made of formatted runs
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+// xlsxFirstSheet finds the part holding the first worksheet of the workbook
+func xlsxFirstSheet(files map[string]*zip.File) (string, error) {
+	f, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("invalid xlsx: missing xl/workbook.xml")
+	}
+	wb := &struct {
+		Sheets []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}{}
+	if err := xlsxDecode(f, wb); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", fmt.Errorf("invalid xlsx: the workbook has no sheets")
+	}
+
+	// workbooks written without relationships use the conventional name
+	f, ok = files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	rels := &struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}{}
+	if err := xlsxDecode(f, rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("invalid xlsx: no part for sheet %v", wb.Sheets[0].RelID)
+}
+
+// xlsxDecode unmarshals a part of the xlsx file into v
+func xlsxDecode(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("invalid xlsx: %v: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	b, err := ioutil.ReadAll(io.LimitReader(rc, maxSpreadsheetPart+1))
+	if err != nil {
+		return fmt.Errorf("invalid xlsx: %v: %w", f.Name, err)
+	}
+	if len(b) > maxSpreadsheetPart {
+		return fmt.Errorf("invalid xlsx: %v is larger than %d bytes", f.Name, maxSpreadsheetPart)
+	}
+	if err := xml.NewDecoder(bytes.NewReader(b)).Decode(v); err != nil {
+		return fmt.Errorf("invalid xlsx: %v: %w", f.Name, err)
+	}
+	return nil
+}
+
+// xlsxColumn is the 0 based column of a cell reference such as B7, -1 when the reference is missing
+func xlsxColumn(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A') + 1
+	}
+	return col - 1
+}
+
+// newSheet turns the records of a file into a Sheet, the first non blank record is the header
+func newSheet(records [][]string) (*Sheet, error) {
+	sheet := &Sheet{Rows: make([]*SheetRow, 0, len(records))}
+	for i, record := range records {
+		if blankRecord(record) {
+			continue
+		}
+		if sheet.Columns == nil {
+			columns, err := sheetColumns(record)
+			if err != nil {
+				return nil, err
+			}
+			sheet.Columns = columns
+			continue
+		}
+
+		if len(record) > len(sheet.Columns) && !blankRecord(record[len(sheet.Columns):]) {
+			return nil, fmt.Errorf("line %d has more cells than the header", i+1)
+		}
+		row := &SheetRow{Line: i + 1, Values: make(map[string]string, len(sheet.Columns))}
+		for j, column := range sheet.Columns {
+			row.Values[column] = ""
+			if j < len(record) {
+				row.Values[column] = strings.TrimSpace(record[j])
+			}
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+
+	if sheet.Columns == nil {
+		return nil, fmt.Errorf("the spreadsheet is empty, the first row has to name the columns")
+	}
+	return sheet, nil
+}
+
+func sheetColumns(header []string) ([]string, error) {
+	columns := make([]string, 0, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, cell := range header {
+		column := ColumnKey(cell)
+		if len(column) == 0 {
+			if blankRecord(header[i:]) {
+				break
+			}
+			return nil, fmt.Errorf("column %d of the header has no name", i+1)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("column %q appears twice in the header", cell)
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func blankRecord(record []string) bool {
+	for _, cell := range record {
+		if len(strings.TrimSpace(cell)) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCSV(t *testing.T) {
+	csv := "\ufeffStudent Name,subject_detail_id,Day,Period\n" +
+		"alice,\"piano, violin\",monday,1\n" +
+		",,,\n" +
+		"bob,guitar,tue\n"
+
+	sheet, err := ReadCSV(strings.NewReader(csv))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"studentname", "subjectdetailid", "day", "period"}, sheet.Columns)
+	if assert.Len(t, sheet.Rows, 2) {
+		assert.Equal(t, 2, sheet.Rows[0].Line)
+		assert.Equal(t, "piano, violin", sheet.Rows[0].Get("subjectDetailId"))
+		assert.Equal(t, 4, sheet.Rows[1].Line)
+		assert.Equal(t, "tue", sheet.Rows[1].Get("day"))
+		assert.Equal(t, "", sheet.Rows[1].Get("period"))
+	}
+}
+
+func TestReadCSVRejectsBadHeader(t *testing.T) {
+	for name, csv := range map[string]string{
+		"empty":           "\n\n",
+		"duplicateColumn": "day,Day\nmonday,monday\n",
+		"unnamedColumn":   "day,,period\nmonday,x,1\n",
+		"extraCells":      "day,period\nmonday,1,x\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(csv))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Details" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="sharedStrings.xml"/>` +
+			`<Relationship Id="rId3" Target="worksheets/details.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>Student Name</t></si><si><t>Day</t></si><si><r><t>ali</t></r><r><t>ce</t></r></si></sst>`,
+		"xl/worksheets/details.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>Period</t></is></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="C3"><v>2</v></c></row>` +
+			`<row r="4"><c r="B4" t="str"><v>friday</v></c></row>` +
+			`</sheetData></worksheet>`,
+	} {
+		w, err := zw.Create(name)
+		if !assert.NoError(t, err) {
+			return
+		}
+		w.Write([]byte(content))
+	}
+	if !assert.NoError(t, zw.Close()) {
+		return
+	}
+
+	sheet, err := ReadSpreadsheet("details.XLSX", bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"studentname", "day", "period"}, sheet.Columns)
+	if assert.Len(t, sheet.Rows, 2) {
+		assert.Equal(t, &SheetRow{Line: 3, Values: map[string]string{"studentname": "alice", "day": "", "period": "2"}}, sheet.Rows[0])
+		assert.Equal(t, &SheetRow{Line: 4, Values: map[string]string{"studentname": "", "day": "friday", "period": ""}}, sheet.Rows[1])
+	}
+}
+
+func TestReadSpreadsheetRejectsOtherFormats(t *testing.T) {
+	_, err := ReadSpreadsheet("details.xls", strings.NewReader("day\nmonday\n"), 11)
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package util
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SheetWriter writes the rows of a spreadsheet as they come, so large spreadsheets are never held in memory
+type SheetWriter interface {
+	// Write adds a row to the spreadsheet
+	Write(row []string) error
+	// Close finishes the spreadsheet, it does not close the underlying writer
+	Close() error
+}
+
+// NewCSVWriter returns a SheetWriter writing a comma separated file to w
+func NewCSVWriter(w io.Writer) SheetWriter {
+	return &csvSheetWriter{w: csv.NewWriter(w)}
+}
+
+type csvSheetWriter struct {
+	w *csv.Writer
+}
+
+func (cw *csvSheetWriter) Write(row []string) error {
+	return cw.w.Write(row)
+}
+
+func (cw *csvSheetWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// xlsxStaticParts are the parts of a workbook with a single worksheet besides the worksheet itself
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// NewXLSXWriter returns a SheetWriter writing an Office Open XML workbook with a single worksheet to w.
+// The cells are written as inline strings.
+func NewXLSXWriter(w io.Writer, sheetName string) (SheetWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		if err := xlsxWritePart(zw, part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	var name bytes.Buffer
+	if err := xml.EscapeText(&name, []byte(xlsxSheetName(sheetName))); err != nil {
+		return nil, err
+	}
+	workbook := xml.Header +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := xlsxWritePart(zw, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	xw := &xlsxSheetWriter{zw: zw, w: bufio.NewWriter(sheet)}
+	xw.w.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return xw, nil
+}
+
+type xlsxSheetWriter struct {
+	zw *zip.Writer
+	w  *bufio.Writer
+	// rows is the number of rows written so far
+	rows int
+}
+
+func (xw *xlsxSheetWriter) Write(row []string) error {
+	xw.rows++
+	fmt.Fprintf(xw.w, `<row r="%d">`, xw.rows)
+	for i, cell := range row {
+		fmt.Fprintf(xw.w, `<c r="%v%d" t="inlineStr"><is><t xml:space="preserve">`, xlsxColumnName(i), xw.rows)
+		if err := xml.EscapeText(xw.w, []byte(cell)); err != nil {
+			return err
+		}
+		xw.w.WriteString(`</t></is></c>`)
+	}
+	_, err := xw.w.WriteString(`</row>`)
+	return err
+}
+
+func (xw *xlsxSheetWriter) Close() error {
+	xw.w.WriteString(`</sheetData></worksheet>`)
+	if err := xw.w.Flush(); err != nil {
+		return err
+	}
+	return xw.zw.Close()
+}
+
+// xlsxColumnName is the name of the 0 based column in a cell reference, A for 0 and AA for 26
+func xlsxColumnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
+
+func xlsxWritePart(zw *zip.Writer, name string, content string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+// xlsxSheetName makes name acceptable as the name of a worksheet, which is at most 31 characters long
+// without any of []:*?/\
+func xlsxSheetName(name string) string {
+	runes := []rune(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return ' '
+		}
+		return r
+	}, name))
+	if len(runes) > 31 {
+		runes = runes[:31]
+	}
+	if len(strings.TrimSpace(string(runes))) == 0 {
+		return "Sheet1"
+	}
+	return string(runes)
+}
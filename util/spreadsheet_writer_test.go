@@ -0,0 +1,64 @@
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSheetWriterRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"Student", "Subject", "Note"},
+		{"alice", "Piano", "  <keeps> spaces & \"quotes\", commas  "},
+		{"บุญมี", "", "second\nline"},
+	}
+
+	for _, format := range []string{"csv", "xlsx"} {
+		t.Run(format, func(t *testing.T) {
+			var b bytes.Buffer
+			var w SheetWriter
+			if format == "csv" {
+				w = NewCSVWriter(&b)
+			} else {
+				var err error
+				w, err = NewXLSXWriter(&b, "schedule: term/1")
+				if !assert.NoError(t, err) {
+					return
+				}
+			}
+			for _, row := range rows {
+				assert.NoError(t, w.Write(row))
+			}
+			if !assert.NoError(t, w.Close()) {
+				return
+			}
+
+			sheet, err := ReadSpreadsheet("export."+format, bytes.NewReader(b.Bytes()), int64(b.Len()))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, []string{"student", "subject", "note"}, sheet.Columns)
+			if assert.Len(t, sheet.Rows, 2) {
+				// the reader trims cells
+				assert.Equal(t, strings.TrimSpace(rows[1][2]), sheet.Rows[0].Get("note"))
+				assert.Equal(t, "บุญมี", sheet.Rows[1].Get("student"))
+				assert.Equal(t, "second\nline", sheet.Rows[1].Get("note"))
+				assert.Equal(t, 3, sheet.Rows[1].Line)
+			}
+		})
+	}
+}
+
+func TestXLSXNames(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+	assert.Equal(t, 52, xlsxColumn("BA7"))
+
+	assert.Equal(t, "schedule  term 1", xlsxSheetName("schedule: term/1"))
+	assert.Equal(t, "Sheet1", xlsxSheetName("[]"))
+	assert.Len(t, []rune(xlsxSheetName(strings.Repeat("ก", 40))), 31)
+}